@@ -1,25 +1,64 @@
 package app
 
 import (
+	"bytes"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
-	"sync/atomic"
 
+	projectconfig "github.com/evanrichards/tree-sorter-ts/internal/config"
+	"github.com/evanrichards/tree-sorter-ts/internal/diff"
 	"github.com/evanrichards/tree-sorter-ts/internal/fileutil"
+	"github.com/evanrichards/tree-sorter-ts/internal/gitutil"
 	"github.com/evanrichards/tree-sorter-ts/internal/processor"
+	"github.com/evanrichards/tree-sorter-ts/internal/report"
 )
 
 // Version is set during build time
 var Version = "dev"
 
+// subcommands run instead of the sorter when named by the first argument
+var subcommands = map[string]func(args []string) error{
+	"lsp":   runLSP,
+	"serve": runServe,
+}
+
 func Run() {
+	if len(os.Args) > 1 {
+		if subcommand, ok := subcommands[os.Args[1]]; ok {
+			if err := subcommand(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	config := parseFlags()
 
-	if err := run(config); err != nil {
+	resolver, err := projectconfig.NewResolver(config.ConfigPath)
+	if err == nil {
+		err = applyProjectConfig(&config, resolver)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	runFn := run
+	if config.Stdin {
+		runFn = func(config processor.Config, resolver *projectconfig.Resolver) error {
+			return runStdin(config, resolver, os.Stdin, os.Stdout)
+		}
+	}
+
+	if err := runFn(config, resolver); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
@@ -29,6 +68,9 @@ func parseFlags() processor.Config {
 	var config processor.Config
 	var extensions string
 	var showVersion bool
+	var color string
+	var format string
+	var lines []string
 
 	flag.BoolVar(&config.Check, "check", false, "Check if files are sorted (exit 1 if not)")
 	flag.BoolVar(&config.Write, "write", false, "Write changes to files (default: dry-run)")
@@ -36,6 +78,23 @@ func parseFlags() processor.Config {
 	flag.StringVar(&extensions, "extensions", ".ts,.tsx", "File extensions to process")
 	flag.IntVar(&config.Workers, "workers", 0, "Number of parallel workers (0 = number of CPUs)")
 	flag.BoolVar(&config.Verbose, "verbose", false, "Show detailed output")
+	flag.BoolVar(&config.AllowParseErrors, "allow-parse-errors", false, "Sort structures in files with syntax errors when the structure itself parsed cleanly")
+	flag.BoolVar(&config.Diff, "diff", false, "Print a unified diff of the changes for each file")
+	flag.IntVar(&config.DiffContext, "diff-context", 3, "Number of context lines around each change in --diff output")
+	flag.StringVar(&color, "color", "auto", "Colorize --diff output: auto, always or never")
+	flag.BoolVar(&config.Stdin, "stdin", false, "Read content from stdin and write the sorted result to stdout")
+	flag.StringVar(&config.StdinFilepath, "stdin-filepath", "", "Path used to pick the grammar for --stdin content (the file need not exist)")
+	flag.Var((*stringList)(&config.Include), "include", "Only process files matching this glob (repeatable)")
+	flag.Var((*stringList)(&config.Exclude), "exclude", "Skip files and directories matching this glob (repeatable)")
+	flag.BoolVar(&config.Hidden, "hidden", false, "Search dot-directories")
+	flag.BoolVar(&config.NoIgnore, "no-ignore", false, "Do not read .gitignore and .treesorterignore files")
+	flag.StringVar(&config.ChangedSince, "changed-since", "", "Only process files changed since the merge-base with this git revision")
+	flag.StringVar(&config.DiffRangesFrom, "diff-ranges-from", "", "Only process structures on lines changed since the merge-base with this git revision")
+	flag.Var((*stringList)(&lines), "lines", "Only process structures overlapping file:start-end (repeatable)")
+	flag.BoolVar(&config.Staged, "staged", false, "Only process staged files, sorting and re-staging their staged content")
+	flag.BoolVar(&config.Watch, "watch", false, "Keep running and sort files again whenever they change (implies --write)")
+	flag.StringVar(&config.ConfigPath, "config", "", "Project configuration file (default: nearest .tree-sorter-ts.json or tree-sorter-ts.config)")
+	flag.StringVar(&format, "format", "", "Output format: text, json, sarif or github (default: github when GITHUB_ACTIONS=true, text otherwise)")
 	flag.BoolVar(&showVersion, "version", false, "Show version information")
 
 	flag.Parse()
@@ -45,53 +104,216 @@ func parseFlags() processor.Config {
 		os.Exit(0)
 	}
 
+	switch format {
+	case "":
+		config.Format = defaultFormat(config)
+	case report.FormatText, report.FormatJSON, report.FormatSARIF, report.FormatGitHub:
+		config.Format = format
+	default:
+		fmt.Fprintf(os.Stderr, "Error: invalid --format value %q (use text, json, sarif or github)\n", format)
+		os.Exit(1)
+	}
+	if config.Format != report.FormatText && (config.Diff || config.Stdin) {
+		fmt.Fprintf(os.Stderr, "Error: --format=%s cannot be combined with --diff or --stdin\n", config.Format)
+		os.Exit(1)
+	}
+
 	args := flag.Args()
-	if len(args) < 1 {
-		fmt.Fprintf(os.Stderr, "Usage: %s [flags] <path>\n", os.Args[0])
+	fileModes := 0
+	for _, set := range []bool{config.ChangedSince != "", config.Staged, config.DiffRangesFrom != "", len(lines) > 0} {
+		if set {
+			fileModes++
+		}
+	}
+	if fileModes > 1 {
+		fmt.Fprintf(os.Stderr, "Error: only one of --changed-since, --staged, --diff-ranges-from and --lines can be used\n")
+		os.Exit(1)
+	}
+	gitMode := config.ChangedSince != "" || config.Staged || config.DiffRangesFrom != ""
+
+	if len(lines) > 0 {
+		fileLines, err := parseLines(lines)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(args) > 0 {
+			fmt.Fprintf(os.Stderr, "Error: --lines does not accept path arguments\n")
+			os.Exit(1)
+		}
+		if _, ok := fileLines[""]; ok && !config.Stdin {
+			fmt.Fprintf(os.Stderr, "Error: --lines needs a file:start-end value without --stdin\n")
+			os.Exit(1)
+		}
+		config.FileLines = fileLines
+	}
+	if config.Stdin {
+		if len(args) > 0 {
+			fmt.Fprintf(os.Stderr, "Error: --stdin does not accept path arguments\n")
+			os.Exit(1)
+		}
+		if config.Write || config.Check || config.Diff || config.Watch || gitMode {
+			fmt.Fprintf(os.Stderr, "Error: --stdin cannot be combined with --write, --check, --diff, --watch or git file selection\n")
+			os.Exit(1)
+		}
+	} else if config.Watch && (config.Check || config.Diff || gitMode || config.FileLines != nil || config.Format != report.FormatText) {
+		fmt.Fprintf(os.Stderr, "Error: --watch cannot be combined with --check, --diff, --format, --lines or git file selection\n")
+		os.Exit(1)
+	} else if config.StdinFilepath != "" {
+		fmt.Fprintf(os.Stderr, "Error: --stdin-filepath requires --stdin\n")
+		os.Exit(1)
+	} else if len(args) < 1 && !gitMode && config.FileLines == nil {
+		fmt.Fprintf(os.Stderr, "Usage: %s [flags] <path|glob>...\n       %s lsp|serve [flags]\n", os.Args[0], os.Args[0])
 		flag.PrintDefaults()
 		os.Exit(1)
 	}
 
-	config.Path = args[0]
+	config.Paths = args
+	if config.Watch {
+		config.Write = true
+	}
 	config.Extensions = strings.Split(extensions, ",")
 
+	switch color {
+	case "always":
+		config.Color = true
+	case "never":
+		config.Color = false
+	case "auto":
+		config.Color = isTerminal(os.Stdout) && os.Getenv("NO_COLOR") == ""
+	default:
+		fmt.Fprintf(os.Stderr, "Error: invalid --color value %q (use auto, always or never)\n", color)
+		os.Exit(1)
+	}
+
 	return config
 }
 
-func run(config processor.Config) error {
-	fileInfo, err := os.Stat(config.Path)
+// applyProjectConfig applies the run-wide settings of the project
+// configuration for the current directory, unless the matching flag was given
+func applyProjectConfig(config *processor.Config, resolver *projectconfig.Resolver) error {
+	pc, err := resolver.ForDir(".")
+	if err != nil || pc == nil {
+		return err
+	}
+
+	explicit := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+
+	if !explicit["extensions"] && len(pc.Extensions) > 0 {
+		config.Extensions = pc.Extensions
+	}
+	if !explicit["workers"] && pc.Workers > 0 {
+		config.Workers = pc.Workers
+	}
+	return nil
+}
+
+// parseLines parses --lines values of the form file:start-end, where a
+// value without a file applies to --stdin content
+func parseLines(values []string) (map[string][]processor.LineRange, error) {
+	fileLines := make(map[string][]processor.LineRange)
+	for _, value := range values {
+		file := ""
+		spec := value
+		if i := strings.LastIndex(value, ":"); i >= 0 {
+			file = filepath.Clean(value[:i])
+			spec = value[i+1:]
+		}
+
+		lineRange, err := processor.ParseLineRange(spec)
+		if err != nil {
+			return nil, fmt.Errorf("--lines %s: %w", value, err)
+		}
+		fileLines[file] = append(fileLines[file], lineRange)
+	}
+	return fileLines, nil
+}
+
+// stringList is a flag.Value collecting every occurrence of a repeatable flag
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+func run(config processor.Config, resolver *projectconfig.Resolver) error {
+	filter, err := fileutil.NewFilter(fileutil.FilterOptions{
+		Hidden:   config.Hidden,
+		NoIgnore: config.NoIgnore,
+		Include:  config.Include,
+		Exclude:  config.Exclude,
+	})
 	if err != nil {
-		return fmt.Errorf("cannot access path %s: %w", config.Path, err)
+		return err
 	}
 
-	var files []string
+	if config.Watch {
+		return runWatch(config, resolver, filter)
+	}
 
-	if fileInfo.IsDir() {
-		files, err = fileutil.FindFiles(config.Path, config.Extensions, config.Recursive)
+	var files []string
+	var pathErrors []error
+	var process fileProcessor = processor.ProcessFileAST
+	switch {
+	case config.DiffRangesFrom != "":
+		repo, err := gitutil.Open(".")
 		if err != nil {
-			return fmt.Errorf("error finding files: %w", err)
+			return err
 		}
-	} else {
-		if fileutil.HasValidExtension(config.Path, config.Extensions) {
-			files = []string{config.Path}
-		} else {
-			return fmt.Errorf("file %s does not have a valid extension", config.Path)
+		var fileLines map[string][]processor.LineRange
+		if files, fileLines, err = changedLineFiles(repo, config, filter); err != nil {
+			return err
+		}
+		process = scopedProcessor(process, fileLines)
+	case config.FileLines != nil:
+		files, pathErrors = collectLineFiles(config, filter)
+		process = scopedProcessor(process, config.FileLines)
+	case config.ChangedSince != "" || config.Staged:
+		repo, err := gitutil.Open(".")
+		if err != nil {
+			return err
 		}
+		if files, err = gitFiles(repo, config, filter); err != nil {
+			return err
+		}
+		if config.Staged {
+			process = stagedProcessor(repo)
+		}
+	default:
+		files, pathErrors = collectFiles(config, filter)
+	}
+
+	// Report unusable arguments but keep going with everything else
+	for _, err := range pathErrors {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+
+	files, settings, err := applyFileSettings(files, resolver)
+	if err != nil {
+		return err
 	}
 
 	if len(files) == 0 {
-		if config.Verbose {
-			fmt.Println("No TypeScript files found")
+		if config.Verbose && len(pathErrors) == 0 {
+			fmt.Fprintln(statusOutput(config), "No TypeScript files found")
 		}
-		return nil
+		return pathArgumentsError(pathErrors)
 	}
 
 	if config.Verbose {
-		fmt.Printf("Found %d TypeScript file(s)\n", len(files))
+		fmt.Fprintf(statusOutput(config), "Found %d TypeScript file(s)\n", len(files))
 	}
 
 	// Process files in parallel
-	needsSorting, err := processFilesParallel(files, config)
+	needsSorting, err := processFilesParallel(files, config, settings, process)
 	if err != nil {
 		return err
 	}
@@ -103,185 +325,496 @@ func run(config processor.Config) error {
 		return fmt.Errorf("some files are not properly sorted")
 	}
 
+	return pathArgumentsError(pathErrors)
+}
+
+// runStdin sorts the content read from in and writes the result to out. The
+// content is echoed unchanged when there is nothing to sort; on error nothing
+// is written so callers can keep their buffer as is.
+func runStdin(config processor.Config, resolver *projectconfig.Resolver, in io.Reader, out io.Writer) error {
+	content, err := io.ReadAll(in)
+	if err != nil {
+		return fmt.Errorf("reading stdin: %w", err)
+	}
+
+	name := config.StdinFilepath
+	if name == "" {
+		name = "<stdin>"
+	}
+
+	settings, err := resolver.SettingsForContent(config.StdinFilepath)
+	if err != nil {
+		return err
+	}
+	if settings.Ignored {
+		_, err := out.Write(content)
+		return err
+	}
+	config.SortDefaults = settings.Defaults
+	if config.FileLines != nil {
+		config.Lines = append([]processor.LineRange{}, config.FileLines[""]...)
+		if config.StdinFilepath != "" {
+			config.Lines = append(config.Lines, config.FileLines[filepath.Clean(config.StdinFilepath)]...)
+		}
+	}
+
+	result, err := processor.ProcessContentAST(config.StdinFilepath, content, config)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	output := content
+	if result.Changed {
+		output = result.Sorted
+	}
+	if _, err := out.Write(output); err != nil {
+		return fmt.Errorf("writing stdout: %w", err)
+	}
 	return nil
 }
 
-type fileResult struct {
-	file            string
-	changed         bool
-	err             error
-	objectsFound    int
-	objectsNeedSort int
+// applyFileSettings drops the files the project configuration ignores and
+// returns the default magic comment options of the remaining ones
+func applyFileSettings(files []string, resolver *projectconfig.Resolver) ([]string, map[string]projectconfig.SortConfig, error) {
+	kept := make([]string, 0, len(files))
+	defaults := make(map[string]projectconfig.SortConfig, len(files))
+
+	for _, file := range files {
+		settings, err := resolver.SettingsFor(file)
+		if err != nil {
+			return nil, nil, err
+		}
+		if settings.Ignored {
+			continue
+		}
+		kept = append(kept, file)
+		defaults[file] = settings.Defaults
+	}
+
+	return kept, defaults, nil
 }
 
-type stats struct {
-	totalFiles      int
-	filesNeedSort   int
-	filesNoChanges  int
-	errorFiles      int
-	totalObjects    int
-	objectsNeedSort int
+// collectFiles expands every path argument into a de-duplicated list of files,
+// returning one error per argument that could not be used
+func collectFiles(config processor.Config, filter *fileutil.Filter) ([]string, []error) {
+	var files []string
+	var errs []error
+	seen := make(map[string]bool)
+
+	for _, arg := range config.Paths {
+		resolved, err := filter.ResolvePath(arg, config.Extensions, config.Recursive)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, file := range resolved {
+			key := filepath.Clean(file)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			files = append(files, file)
+		}
+	}
+
+	return files, errs
 }
 
-func processFilesParallel(files []string, config processor.Config) (bool, error) {
-	// Set up worker pool
-	workerCount := config.Workers
-	if workerCount == 0 {
-		workerCount = runtime.NumCPU()
+// gitFiles lists the files changed since --changed-since or staged with
+// --staged, limited to the path arguments, extensions and filter
+func gitFiles(repo *gitutil.Repo, config processor.Config, filter *fileutil.Filter) ([]string, error) {
+	var changed []string
+	var err error
+	if config.Staged {
+		changed, err = repo.Staged(config.Paths)
+	} else {
+		changed, err = repo.ChangedSince(config.ChangedSince, config.Paths)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	// Channels for work distribution
-	fileChan := make(chan string, len(files))
-	resultChan := make(chan fileResult, len(files))
+	files := make([]string, 0, len(changed))
+	for _, file := range changed {
+		if fileutil.HasValidExtension(file, config.Extensions) && !filter.Skips(file, false) {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// changedLineFiles lists the files with lines changed since --diff-ranges-from
+// and the changed lines of each, limited like gitFiles
+func changedLineFiles(repo *gitutil.Repo, config processor.Config, filter *fileutil.Filter) ([]string, map[string][]processor.LineRange, error) {
+	hunks, err := repo.ChangedLines(config.DiffRangesFrom, config.Paths)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var files []string
+	fileLines := make(map[string][]processor.LineRange)
+	for file, fileHunks := range hunks {
+		if !fileutil.HasValidExtension(file, config.Extensions) || filter.Skips(file, false) {
+			continue
+		}
+		files = append(files, file)
+
+		fileLines[filepath.Clean(file)] = hunkRanges(fileHunks)
+	}
+	sort.Strings(files)
+	return files, fileLines, nil
+}
+
+// hunkRanges converts the hunks of a file into the line ranges they touch
+func hunkRanges(hunks []gitutil.Hunk) []processor.LineRange {
+	ranges := make([]processor.LineRange, 0, len(hunks))
+	for _, hunk := range hunks {
+		if hunk.Count == 0 {
+			// A deletion touches the lines on both sides of it
+			ranges = append(ranges, processor.LineRange{Start: max(hunk.Start, 1), End: hunk.Start + 1})
+			continue
+		}
+		ranges = append(ranges, processor.LineRange{Start: hunk.Start, End: hunk.Start + hunk.Count - 1})
+	}
+	return ranges
+}
+
+// collectLineFiles resolves the files named by --lines like path arguments
+func collectLineFiles(config processor.Config, filter *fileutil.Filter) ([]string, []error) {
+	lineConfig := config
+	lineConfig.Paths = nil
+	for file := range config.FileLines {
+		lineConfig.Paths = append(lineConfig.Paths, file)
+	}
+	sort.Strings(lineConfig.Paths)
+	return collectFiles(lineConfig, filter)
+}
+
+// scopedProcessor limits every file to its line ranges; files without ranges
+// have no structure in scope
+func scopedProcessor(process fileProcessor, fileLines map[string][]processor.LineRange) fileProcessor {
+	return func(file string, config processor.Config) (processor.ProcessResult, error) {
+		config.Lines = fileLines[filepath.Clean(file)]
+		if config.Lines == nil {
+			config.Lines = []processor.LineRange{}
+		}
+		return process(file, config)
+	}
+}
+
+// fileProcessor sorts a single file, writing the result when config.Write is set
+type fileProcessor func(file string, config processor.Config) (processor.ProcessResult, error)
+
+// stagedProcessor sorts the staged content of files instead of the working
+// tree. With --write the result is staged again, and the working tree file is
+// updated too when it has no unstaged changes.
+func stagedProcessor(repo *gitutil.Repo) fileProcessor {
+	return func(file string, config processor.Config) (processor.ProcessResult, error) {
+		content, err := repo.StagedContent(file)
+		if err != nil {
+			return processor.ProcessResult{}, fmt.Errorf("reading staged content: %w", err)
+		}
+
+		result, err := processor.ProcessContentAST(file, content, config)
+		if err != nil || !result.Changed || !config.Write {
+			return result, err
+		}
+
+		if err := repo.Stage(file, result.Sorted); err != nil {
+			return result, fmt.Errorf("staging file: %w", err)
+		}
+		if worktree, err := os.ReadFile(file); err == nil && bytes.Equal(worktree, content) {
+			if err := os.WriteFile(file, result.Sorted, 0o600); err != nil {
+				return result, fmt.Errorf("writing file: %w", err)
+			}
+		}
+		return result, nil
+	}
+}
+
+// pathArgumentsError summarizes the path arguments that could not be processed
+func pathArgumentsError(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d path argument(s) could not be processed", len(errs))
+}
+
+// defaultFormat picks GitHub annotations when running inside GitHub Actions,
+// unless stdout is reserved for --diff or --stdin output or --watch is running
+func defaultFormat(config processor.Config) string {
+	if os.Getenv("GITHUB_ACTIONS") == "true" && !config.Diff && !config.Stdin && !config.Watch {
+		return report.FormatGitHub
+	}
+	return report.FormatText
+}
+
+// statusOutput returns where progress and summary lines go. With --diff or a
+// machine-readable format, stdout carries only that output so it can be
+// redirected to a file.
+func statusOutput(config processor.Config) io.Writer {
+	if config.Diff || config.Format == report.FormatJSON || config.Format == report.FormatSARIF {
+		return os.Stderr
+	}
+	return os.Stdout
+}
 
-	// Create wait group for workers
-	var wg sync.WaitGroup
+// isTerminal reports whether f is attached to a terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// diffEdits converts the processor's edits for rendering as a diff
+func diffEdits(edits []processor.TextEdit) []diff.Edit {
+	converted := make([]diff.Edit, 0, len(edits))
+	for _, edit := range edits {
+		converted = append(converted, diff.Edit{Start: edit.StartByte, End: edit.EndByte, NewText: edit.NewText})
+	}
+	return converted
+}
 
-	// Start workers
-	for i := 0; i < workerCount; i++ {
-		wg.Add(1)
+// diffLabels returns the git-style "a/" and "b/" names for a file so the
+// output of --diff can be applied with git apply or patch -p1
+func diffLabels(file string) (string, string) {
+	if filepath.IsAbs(file) {
+		return file, file
+	}
+	name := filepath.ToSlash(filepath.Clean(file))
+	return "a/" + name, "b/" + name
+}
+
+func processFilesParallel(files []string, config processor.Config, defaults map[string]projectconfig.SortConfig, process fileProcessor) (bool, error) {
+	results := processFiles(files, config, defaults, process)
+	summary := report.Summarize(report.ModeFor(config), results)
+
+	var errors []error
+	for _, result := range results {
+		if result.Err != nil {
+			errors = append(errors, fmt.Errorf("%s: %w", result.Path, result.Err))
+		}
+	}
+
+	switch config.Format {
+	case report.FormatJSON:
+		if err := report.WriteJSON(os.Stdout, results, summary); err != nil {
+			return false, fmt.Errorf("writing report: %w", err)
+		}
+	case report.FormatSARIF:
+		if err := report.WriteSARIF(os.Stdout, results, Version); err != nil {
+			return false, fmt.Errorf("writing report: %w", err)
+		}
+	case report.FormatGitHub:
+		if err := report.WriteGitHubAnnotations(os.Stdout, results, summary.Mode); err != nil {
+			return false, fmt.Errorf("writing report: %w", err)
+		}
+		printTextReport(results, summary, config)
+		for _, err := range errors {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+	default:
+		printTextReport(results, summary, config)
+		for _, err := range errors {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+	}
+
+	needsSorting := summary.FilesNeedSort > 0
+	if len(errors) > 0 {
+		// Only return first error to maintain backwards compatibility
+		return needsSorting, errors[0]
+	}
+
+	return needsSorting, nil
+}
+
+// processFiles runs every file through the processor on a pool of workers and
+// returns the results in a stable order so output does not depend on scheduling
+func processFiles(files []string, config processor.Config, defaults map[string]projectconfig.SortConfig, process fileProcessor) []report.File {
+	pool := newWorkerPool(config.Workers, process)
+	defer pool.close()
+	return pool.processAll(files, config, defaults)
+}
+
+// workerPool processes files on a fixed set of goroutines that live until
+// close, so watch mode does not start new workers for every change
+type workerPool struct {
+	jobs chan poolJob
+	wg   sync.WaitGroup
+}
+
+// poolJob is a single file for the workers, with the channel for its result
+type poolJob struct {
+	file    string
+	config  processor.Config
+	results chan<- report.File
+}
+
+// newWorkerPool starts the workers; zero workers means one per CPU
+func newWorkerPool(workers int, process fileProcessor) *workerPool {
+	if workers == 0 {
+		workers = runtime.NumCPU()
+	}
+
+	pool := &workerPool{jobs: make(chan poolJob)}
+	for i := 0; i < workers; i++ {
+		pool.wg.Add(1)
 		go func() {
-			defer wg.Done()
-			for file := range fileChan {
-				processResult, err := processor.ProcessFileAST(file, config)
-				resultChan <- fileResult{
-					file:            file,
-					changed:         processResult.Changed,
-					err:             err,
-					objectsFound:    processResult.ObjectsFound,
-					objectsNeedSort: processResult.ObjectsNeedSort,
+			defer pool.wg.Done()
+			for job := range pool.jobs {
+				processResult, err := process(job.file, job.config)
+				job.results <- report.File{
+					Path:   job.file,
+					Result: processResult,
+					Err:    err,
 				}
 			}
 		}()
 	}
+	return pool
+}
+
+// processAll processes the files with their default magic comment options and
+// returns the results sorted by path
+func (p *workerPool) processAll(files []string, config processor.Config, defaults map[string]projectconfig.SortConfig) []report.File {
+	resultChan := make(chan report.File, len(files))
 
 	// Send files to workers
-	for _, file := range files {
-		fileChan <- file
+	go func() {
+		for _, file := range files {
+			fileConfig := config
+			fileConfig.SortDefaults = defaults[file]
+			p.jobs <- poolJob{file: file, config: fileConfig, results: resultChan}
+		}
+	}()
+
+	results := make([]report.File, 0, len(files))
+	for range files {
+		results = append(results, <-resultChan)
 	}
-	close(fileChan)
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Path < results[j].Path
+	})
 
-	// Wait for workers to finish
-	wg.Wait()
-	close(resultChan)
+	return results
+}
 
-	// Collect results
-	fileStats := stats{
-		totalFiles: len(files),
-	}
-	var needsSorting atomic.Bool
-	var errors []error
-	var filesNeedingSorting []string
+// close stops the workers once they finished their jobs
+func (p *workerPool) close() {
+	close(p.jobs)
+	p.wg.Wait()
+}
+
+// printTextReport prints the human readable per-file lines and summary
+func printTextReport(results []report.File, fileStats report.Summary, config processor.Config) {
+	out := statusOutput(config)
 
-	for result := range resultChan {
-		if result.err != nil {
-			errors = append(errors, fmt.Errorf("%s: %w", result.file, result.err))
-			fileStats.errorFiles++
+	for _, file := range results {
+		if file.Err != nil {
 			continue
 		}
+		result := file.Result
 
-		fileStats.totalObjects += result.objectsFound
-		fileStats.objectsNeedSort += result.objectsNeedSort
+		if result.ObjectsSkipped > 0 && config.Verbose {
+			fmt.Fprintf(out, "⚠ Skipped %d structure(s) with syntax errors in %s\n", result.ObjectsSkipped, file.Path)
+		}
 
-		if result.changed {
-			needsSorting.Store(true)
-			fileStats.filesNeedSort++
-			filesNeedingSorting = append(filesNeedingSorting, result.file)
+		if result.Changed {
+			if config.Diff {
+				from, to := diffLabels(file.Path)
+				fmt.Print(diff.UnifiedEdits(result.Original, diffEdits(result.Edits), diff.Options{
+					FromFile: from,
+					ToFile:   to,
+					Context:  config.DiffContext,
+					Color:    config.Color,
+				}))
+			}
 
 			if config.Verbose {
 				switch {
 				case config.Write:
-					fmt.Printf("✓ Sorted %s (%d objects)\n", result.file, result.objectsNeedSort)
+					fmt.Fprintf(out, "✓ Sorted %s (%d objects)\n", file.Path, result.ObjectsNeedSort)
 				case config.Check:
-					fmt.Printf("✗ Needs sorting: %s (%d objects need sorting)\n", result.file, result.objectsNeedSort)
+					fmt.Fprintf(out, "✗ Needs sorting: %s (%d objects need sorting)\n", file.Path, result.ObjectsNeedSort)
 				default:
 					// Dry-run mode
-					fmt.Printf("Would sort %s (%d objects need sorting)\n", result.file, result.objectsNeedSort)
+					fmt.Fprintf(out, "Would sort %s (%d objects need sorting)\n", file.Path, result.ObjectsNeedSort)
 				}
 			} else {
 				// Non-verbose mode: always show files that need sorting for better CI feedback
 				switch {
 				case config.Check:
-					fmt.Printf("✗ %s needs sorting (%d items)\n", result.file, result.objectsNeedSort)
+					fmt.Fprintf(out, "✗ %s needs sorting (%d items)\n", file.Path, result.ObjectsNeedSort)
 				case config.Write:
-					fmt.Printf("✓ Sorted %s (%d items)\n", result.file, result.objectsNeedSort)
+					fmt.Fprintf(out, "✓ Sorted %s (%d items)\n", file.Path, result.ObjectsNeedSort)
 				default:
 					// Dry-run mode
-					fmt.Printf("Would sort %s (%d items)\n", result.file, result.objectsNeedSort)
+					fmt.Fprintf(out, "Would sort %s (%d items)\n", file.Path, result.ObjectsNeedSort)
 				}
 			}
-		} else {
-			fileStats.filesNoChanges++
-			if config.Verbose {
-				// Only print in check mode if objects were found
-				if config.Check && result.objectsFound > 0 {
-					fmt.Printf("✓ No changes needed %s (%d objects already sorted)\n", result.file, result.objectsFound)
-				} else if config.Check {
-					fmt.Printf("✓ No changes needed %s\n", result.file)
-				}
+		} else if config.Verbose {
+			// Only print in check mode if objects were found
+			if config.Check && result.ObjectsFound > 0 {
+				fmt.Fprintf(out, "✓ No changes needed %s (%d objects already sorted)\n", file.Path, result.ObjectsFound)
+			} else if config.Check {
+				fmt.Fprintf(out, "✓ No changes needed %s\n", file.Path)
 			}
 		}
 	}
 
 	// Print summary - always show summary in check mode or when there are issues
-	shouldShowSummary := config.Verbose || (config.Check && fileStats.filesNeedSort > 0) || fileStats.totalFiles > 1
-	
-	if shouldShowSummary {
-		if !config.Verbose {
-			fmt.Println()
-		} else {
-			fmt.Println("\n─────────────────────────────────────")
-		}
-		
-		// Always show total files processed for context
-		if fileStats.totalFiles > 1 {
-			fmt.Printf("Processed %d files\n", fileStats.totalFiles)
-		}
-
-		switch {
-		case config.Check:
-			if fileStats.filesNeedSort > 0 {
-				fmt.Printf("❌ %d file(s) need sorting\n", fileStats.filesNeedSort)
-				if fileStats.objectsNeedSort > 0 {
-					fmt.Printf("   %d item(s) need to be sorted\n", fileStats.objectsNeedSort)
-				}
-			} else if config.Verbose || fileStats.totalFiles > 1 {
-				fmt.Printf("✅ All files are properly sorted\n")
-			}
-		case config.Write:
-			if fileStats.filesNeedSort > 0 {
-				fmt.Printf("✅ Sorted %d file(s)\n", fileStats.filesNeedSort)
-				if fileStats.objectsNeedSort > 0 {
-					fmt.Printf("   %d item(s) were sorted\n", fileStats.objectsNeedSort)
-				}
-			} else if config.Verbose || fileStats.totalFiles > 1 {
-				fmt.Printf("✅ No files needed sorting\n")
+	shouldShowSummary := config.Verbose || (config.Check && fileStats.FilesNeedSort > 0) || fileStats.TotalFiles > 1
+
+	if !shouldShowSummary {
+		return
+	}
+
+	if !config.Verbose {
+		fmt.Fprintln(out)
+	} else {
+		fmt.Fprintln(out, "\n─────────────────────────────────────")
+	}
+
+	// Always show total files processed for context
+	if fileStats.TotalFiles > 1 {
+		fmt.Fprintf(out, "Processed %d files\n", fileStats.TotalFiles)
+	}
+
+	switch {
+	case config.Check:
+		if fileStats.FilesNeedSort > 0 {
+			fmt.Fprintf(out, "❌ %d file(s) need sorting\n", fileStats.FilesNeedSort)
+			if fileStats.StructuresNeedSort > 0 {
+				fmt.Fprintf(out, "   %d item(s) need to be sorted\n", fileStats.StructuresNeedSort)
 			}
-		default:
-			// Dry-run mode
-			if fileStats.filesNeedSort > 0 {
-				fmt.Printf("Would sort %d file(s)\n", fileStats.filesNeedSort)
-				if fileStats.objectsNeedSort > 0 {
-					fmt.Printf("   %d item(s) would be sorted\n", fileStats.objectsNeedSort)
-				}
-			} else if config.Verbose || fileStats.totalFiles > 1 {
-				fmt.Printf("✅ All files are properly sorted\n")
+		} else if config.Verbose || fileStats.TotalFiles > 1 {
+			fmt.Fprintf(out, "✅ All files are properly sorted\n")
+		}
+	case config.Write:
+		if fileStats.FilesNeedSort > 0 {
+			fmt.Fprintf(out, "✅ Sorted %d file(s)\n", fileStats.FilesNeedSort)
+			if fileStats.StructuresNeedSort > 0 {
+				fmt.Fprintf(out, "   %d item(s) were sorted\n", fileStats.StructuresNeedSort)
 			}
+		} else if config.Verbose || fileStats.TotalFiles > 1 {
+			fmt.Fprintf(out, "✅ No files needed sorting\n")
 		}
-
-		if fileStats.errorFiles > 0 {
-			fmt.Printf("❌ %d file(s) had errors\n", fileStats.errorFiles)
+	default:
+		// Dry-run mode
+		if fileStats.FilesNeedSort > 0 {
+			fmt.Fprintf(out, "Would sort %d file(s)\n", fileStats.FilesNeedSort)
+			if fileStats.StructuresNeedSort > 0 {
+				fmt.Fprintf(out, "   %d item(s) would be sorted\n", fileStats.StructuresNeedSort)
+			}
+		} else if config.Verbose || fileStats.TotalFiles > 1 {
+			fmt.Fprintf(out, "✅ All files are properly sorted\n")
 		}
 	}
 
-	// Handle errors
-	if len(errors) > 0 {
-		for _, err := range errors {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		}
-		// Only return first error to maintain backwards compatibility
-		return needsSorting.Load(), errors[0]
+	if fileStats.ErrorFiles > 0 {
+		fmt.Fprintf(out, "❌ %d file(s) had errors\n", fileStats.ErrorFiles)
 	}
-
-	return needsSorting.Load(), nil
 }
@@ -0,0 +1,229 @@
+package app
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	projectconfig "github.com/evanrichards/tree-sorter-ts/internal/config"
+	"github.com/evanrichards/tree-sorter-ts/internal/gitutil"
+	"github.com/evanrichards/tree-sorter-ts/internal/processor"
+)
+
+const unsortedObject = `const config = {
+  /** tree-sorter-ts: keep-sorted **/
+  b: 2,
+  a: 1,
+};
+`
+
+const sortedObject = `const config = {
+  /** tree-sorter-ts: keep-sorted **/
+  a: 1,
+  b: 2,
+};
+`
+
+// newTestResolver returns a resolver using a configuration file with the
+// given content, written to a temporary directory
+func newTestResolver(t *testing.T, content string) (*projectconfig.Resolver, string) {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".tree-sorter-ts.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	resolver, err := projectconfig.NewResolver(path)
+	if err != nil {
+		t.Fatalf("NewResolver failed: %v", err)
+	}
+	return resolver, dir
+}
+
+func TestRunStdin(t *testing.T) {
+	resolver, dir := newTestResolver(t, `{"ignore": ["generated"]}`)
+
+	twoObjects := unsortedObject + "\n" + unsortedObject
+	stdinFile := filepath.Join(dir, "src", "config.ts")
+	tests := []struct {
+		name   string
+		config processor.Config
+		input  string
+		want   string
+	}{
+		{
+			name:  "sorts the content",
+			input: unsortedObject,
+			want:  sortedObject,
+		},
+		{
+			name:  "echoes content with nothing to sort",
+			input: sortedObject,
+			want:  sortedObject,
+		},
+		{
+			name:   "echoes content of an ignored path",
+			config: processor.Config{StdinFilepath: filepath.Join(dir, "generated", "config.ts")},
+			input:  unsortedObject,
+			want:   unsortedObject,
+		},
+		{
+			name: "limits sorting to --lines values without a file",
+			config: processor.Config{FileLines: map[string][]processor.LineRange{
+				"": {{Start: 7, End: 11}},
+			}},
+			input: twoObjects,
+			want:  unsortedObject + "\n" + sortedObject,
+		},
+		{
+			name: "adds --lines values of the --stdin-filepath file",
+			config: processor.Config{
+				StdinFilepath: stdinFile,
+				FileLines: map[string][]processor.LineRange{
+					"":        {{Start: 1, End: 1}},
+					stdinFile: {{Start: 7, End: 7}},
+				},
+			},
+			input: twoObjects,
+			want:  sortedObject + "\n" + sortedObject,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := runStdin(tt.config, resolver, bytes.NewBufferString(tt.input), &out); err != nil {
+				t.Fatalf("runStdin failed: %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("Expected:\n%s\nGot:\n%s", tt.want, out.String())
+			}
+		})
+	}
+}
+
+func TestRunStdinWritesNothingOnError(t *testing.T) {
+	resolver, _ := newTestResolver(t, `{}`)
+	input := `const config = {
+  /** tree-sorter-ts: keep-sorted key="id" sort-by-comment **/
+  b: 2,
+  a: 1,
+};
+`
+	var out bytes.Buffer
+	err := runStdin(processor.Config{}, resolver, bytes.NewBufferString(input), &out)
+	if !errors.Is(err, processor.ErrInvalidMagicComment) {
+		t.Fatalf("Expected ErrInvalidMagicComment, got %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("Expected nothing to be written, got:\n%s", out.String())
+	}
+}
+
+func TestParseLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []string
+		want    map[string][]processor.LineRange
+		wantErr bool
+	}{
+		{
+			name:   "file and range",
+			values: []string{"src/app.ts:3-7"},
+			want:   map[string][]processor.LineRange{"src/app.ts": {{Start: 3, End: 7}}},
+		},
+		{
+			name:   "single line",
+			values: []string{"src/app.ts:4"},
+			want:   map[string][]processor.LineRange{"src/app.ts": {{Start: 4, End: 4}}},
+		},
+		{
+			name:   "ranges of the same file collect, with the path cleaned",
+			values: []string{"src/app.ts:1-2", "./src/../src/app.ts:9-9"},
+			want:   map[string][]processor.LineRange{"src/app.ts": {{Start: 1, End: 2}, {Start: 9, End: 9}}},
+		},
+		{
+			name:   "no file applies to stdin",
+			values: []string{"5-6"},
+			want:   map[string][]processor.LineRange{"": {{Start: 5, End: 6}}},
+		},
+		{
+			name:   "only the last colon separates the file",
+			values: []string{"C:/src/app.ts:2-3"},
+			want:   map[string][]processor.LineRange{filepath.Clean("C:/src/app.ts"): {{Start: 2, End: 3}}},
+		},
+		{
+			name:    "end before start",
+			values:  []string{"src/app.ts:7-3"},
+			wantErr: true,
+		},
+		{
+			name:    "not a number",
+			values:  []string{"src/app.ts:x"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseLines(tt.values)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseLines failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseLines(%q) = %v, want %v", tt.values, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHunkRanges(t *testing.T) {
+	tests := []struct {
+		name  string
+		hunks []gitutil.Hunk
+		want  []processor.LineRange
+	}{
+		{
+			name:  "added or changed lines",
+			hunks: []gitutil.Hunk{{Start: 4, Count: 3}},
+			want:  []processor.LineRange{{Start: 4, End: 6}},
+		},
+		{
+			name:  "single line",
+			hunks: []gitutil.Hunk{{Start: 9, Count: 1}},
+			want:  []processor.LineRange{{Start: 9, End: 9}},
+		},
+		{
+			name:  "deletion touches the lines around it",
+			hunks: []gitutil.Hunk{{Start: 5, Count: 0}},
+			want:  []processor.LineRange{{Start: 5, End: 6}},
+		},
+		{
+			name:  "deletion at the start of the file",
+			hunks: []gitutil.Hunk{{Start: 0, Count: 0}},
+			want:  []processor.LineRange{{Start: 1, End: 1}},
+		},
+		{
+			name:  "several hunks",
+			hunks: []gitutil.Hunk{{Start: 2, Count: 2}, {Start: 10, Count: 0}},
+			want:  []processor.LineRange{{Start: 2, End: 3}, {Start: 10, End: 11}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hunkRanges(tt.hunks); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("hunkRanges(%v) = %v, want %v", tt.hunks, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/lsp"
+)
+
+// runLSP serves the language server over stdin and stdout; stdout carries
+// the protocol, so errors go to stderr
+func runLSP(args []string) error {
+	flags := flag.NewFlagSet("lsp", flag.ExitOnError)
+	configPath := flags.String("config", "", "Project configuration file (default: nearest .tree-sorter-ts.json or tree-sorter-ts.config)")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s lsp [flags]\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("lsp does not accept path arguments")
+	}
+
+	server, err := lsp.NewServer(os.Stdin, os.Stdout, lsp.Options{ConfigPath: *configPath, Version: Version})
+	if err != nil {
+		return err
+	}
+	return server.Run()
+}
@@ -0,0 +1,33 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/serve"
+)
+
+// runServe answers newline-delimited JSON requests from stdin on stdout
+// until stdin is closed
+func runServe(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	configPath := flags.String("config", "", "Project configuration file (default: nearest .tree-sorter-ts.json or tree-sorter-ts.config)")
+	workers := flags.Int("workers", 0, "Number of requests handled at once (0 = number of CPUs)")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s serve [flags]\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("serve does not accept path arguments")
+	}
+
+	server, err := serve.NewServer(os.Stdin, os.Stdout, serve.Options{ConfigPath: *configPath, Workers: *workers})
+	if err != nil {
+		return err
+	}
+	return server.Run()
+}
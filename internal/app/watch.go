@@ -0,0 +1,219 @@
+package app
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	projectconfig "github.com/evanrichards/tree-sorter-ts/internal/config"
+	"github.com/evanrichards/tree-sorter-ts/internal/fileutil"
+	"github.com/evanrichards/tree-sorter-ts/internal/processor"
+	"github.com/evanrichards/tree-sorter-ts/internal/watch"
+)
+
+// watchDebounce is how long watch mode waits after the last change before
+// processing, so the burst of writes from a single save is handled once
+const watchDebounce = 100 * time.Millisecond
+
+// watchTarget is the part of the file system a path argument covers
+type watchTarget struct {
+	dir       string // Directory argument
+	file      string // File argument
+	pattern   string // Glob argument
+	recursive bool
+}
+
+// newWatchTargets maps the path arguments to watch targets, skipping
+// arguments that do not exist
+func newWatchTargets(paths []string, recursive bool) []watchTarget {
+	var targets []watchTarget
+	for _, arg := range paths {
+		if fileutil.IsGlob(arg) {
+			targets = append(targets, watchTarget{dir: fileutil.GlobBase(arg), pattern: arg, recursive: true})
+			continue
+		}
+
+		info, err := os.Stat(arg)
+		switch {
+		case err != nil:
+			continue
+		case info.IsDir():
+			targets = append(targets, watchTarget{dir: arg, recursive: recursive})
+		default:
+			targets = append(targets, watchTarget{dir: filepath.Dir(arg), file: arg})
+		}
+	}
+	return targets
+}
+
+// root returns the directory to watch for the target
+func (t watchTarget) root() watch.Root {
+	return watch.Root{Dir: t.dir, Recursive: t.recursive}
+}
+
+// matches reports whether a changed file is covered by the target
+func (t watchTarget) matches(path string) bool {
+	switch {
+	case t.pattern != "":
+		return fileutil.MatchGlob(filepath.ToSlash(filepath.Clean(t.pattern)), filepath.ToSlash(filepath.Clean(path)))
+	case t.file != "":
+		return filepath.Clean(path) == filepath.Clean(t.file)
+	}
+
+	rel, err := filepath.Rel(t.dir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false
+	}
+	return t.recursive || !strings.ContainsRune(rel, filepath.Separator)
+}
+
+// watchSession keeps the state of a --watch run between changes
+type watchSession struct {
+	config   processor.Config
+	resolver *projectconfig.Resolver
+	pool     *workerPool
+	out      io.Writer
+	// seen holds the content hash of every file as last processed or
+	// written, so unchanged saves and the session's own writes are skipped
+	seen map[string][sha256.Size]byte
+}
+
+// runWatch sorts the files once, then keeps sorting the ones that change
+// until interrupted
+func runWatch(config processor.Config, resolver *projectconfig.Resolver, filter *fileutil.Filter) error {
+	targets := newWatchTargets(config.Paths, config.Recursive)
+	files, pathErrors := collectFiles(config, filter)
+	for _, err := range pathErrors {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+	if len(targets) == 0 {
+		return pathArgumentsError(pathErrors)
+	}
+
+	session := &watchSession{
+		config:   config,
+		resolver: resolver,
+		pool:     newWorkerPool(config.Workers, processor.ProcessFileAST),
+		out:      os.Stdout,
+		seen:     make(map[string][sha256.Size]byte),
+	}
+	defer session.pool.close()
+
+	session.process(files)
+
+	roots := make([]watch.Root, 0, len(targets))
+	for _, target := range targets {
+		roots = append(roots, target.root())
+	}
+	watcher, err := watch.New(roots, watch.Options{SkipDir: filter.SkipsDir})
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	fmt.Fprintf(session.out, "Watching %d file(s) for changes (press Ctrl+C to stop)\n", len(files))
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+
+	pending := make(map[string]bool)
+	debounce := time.NewTimer(watchDebounce)
+	debounce.Stop()
+
+	for {
+		select {
+		case <-interrupt:
+			return nil
+		case path, ok := <-watcher.Events():
+			if !ok {
+				return nil
+			}
+			if !fileutil.HasValidExtension(path, config.Extensions) || filter.Skips(path, false) || !matchesAnyTarget(targets, path) {
+				continue
+			}
+			pending[path] = true
+			debounce.Reset(watchDebounce)
+		case err, ok := <-watcher.Errors():
+			if ok {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			}
+		case <-debounce.C:
+			changed := make([]string, 0, len(pending))
+			for path := range pending {
+				changed = append(changed, path)
+			}
+			pending = make(map[string]bool)
+			sort.Strings(changed)
+			session.process(session.modified(changed))
+		}
+	}
+}
+
+func matchesAnyTarget(targets []watchTarget, path string) bool {
+	for _, target := range targets {
+		if target.matches(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// modified drops the files whose content did not change since they were last
+// processed, which includes the files the session just sorted itself
+func (s *watchSession) modified(files []string) []string {
+	var changed []string
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			// Deleted or renamed away before we got to it
+			delete(s.seen, file)
+			continue
+		}
+		if hash, ok := s.seen[file]; ok && hash == sha256.Sum256(content) {
+			continue
+		}
+		changed = append(changed, file)
+	}
+	return changed
+}
+
+// process sorts the files and prints a line per sorted file or error
+func (s *watchSession) process(files []string) {
+	files, defaults, err := applyFileSettings(files, s.resolver)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return
+	}
+
+	for _, file := range s.pool.processAll(files, s.config, defaults) {
+		stamp := time.Now().Format("15:04:05")
+		result := file.Result
+
+		switch {
+		case file.Err != nil:
+			fmt.Fprintf(os.Stderr, "%s ✗ %s: %v\n", stamp, file.Path, file.Err)
+		case result.Changed:
+			fmt.Fprintf(s.out, "%s ✓ Sorted %s (%d items)\n", stamp, file.Path, result.ObjectsNeedSort)
+		case s.config.Verbose:
+			fmt.Fprintf(s.out, "%s ✓ No changes needed %s\n", stamp, file.Path)
+		}
+
+		content := result.Original
+		if result.Changed && s.config.Write && file.Err == nil {
+			content = result.Sorted
+		}
+		if content == nil {
+			delete(s.seen, file.Path)
+			continue
+		}
+		s.seen[file.Path] = sha256.Sum256(content)
+	}
+}
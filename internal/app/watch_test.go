@@ -0,0 +1,122 @@
+package app
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/processor"
+)
+
+func TestWatchTargetMatches(t *testing.T) {
+	glob := watchTarget{dir: "src", pattern: "src/**/*.ts", recursive: true}
+	file := watchTarget{dir: "src", file: "src/app.ts"}
+	recursive := watchTarget{dir: "src", recursive: true}
+	flat := watchTarget{dir: "src"}
+
+	tests := []struct {
+		name   string
+		target watchTarget
+		path   string
+		want   bool
+	}{
+		{name: "glob matches nested file", target: glob, path: "src/a/b.ts", want: true},
+		{name: "glob matches cleaned path", target: glob, path: "./src/a/../b.ts", want: true},
+		{name: "glob skips other extension", target: glob, path: "src/a/b.js", want: false},
+		{name: "glob skips other directory", target: glob, path: "lib/b.ts", want: false},
+		{name: "file matches itself", target: file, path: "./src/app.ts", want: true},
+		{name: "file skips sibling", target: file, path: "src/other.ts", want: false},
+		{name: "recursive directory matches nested file", target: recursive, path: "src/a/b.ts", want: true},
+		{name: "non-recursive directory matches direct child", target: flat, path: "src/b.ts", want: true},
+		{name: "non-recursive directory skips nested file", target: flat, path: "src/a/b.ts", want: false},
+		{name: "directory skips parent", target: recursive, path: "other/b.ts", want: false},
+		{name: "directory skips its parent directory", target: recursive, path: "src/..", want: false},
+		{name: "directory matches name starting with dots", target: recursive, path: "src/..b.ts", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.FromSlash(tt.path)
+			if got := tt.target.matches(path); got != tt.want {
+				t.Errorf("%+v.matches(%q) = %v, want %v", tt.target, path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewWatchTargets(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "app.ts")
+	if err := os.WriteFile(file, []byte(sortedObject), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	pattern := filepath.Join(dir, "**", "*.ts")
+
+	targets := newWatchTargets([]string{dir, file, pattern, filepath.Join(dir, "missing.ts")}, false)
+	want := []watchTarget{
+		{dir: dir},
+		{dir: dir, file: file},
+		{dir: dir, pattern: pattern, recursive: true},
+	}
+	if len(targets) != len(want) {
+		t.Fatalf("Expected %d targets, got %+v", len(want), targets)
+	}
+	for i := range want {
+		if targets[i] != want[i] {
+			t.Errorf("Target %d = %+v, want %+v", i, targets[i], want[i])
+		}
+	}
+}
+
+func TestWatchSessionModified(t *testing.T) {
+	resolver, dir := newTestResolver(t, `{}`)
+	file := filepath.Join(dir, "config.ts")
+	if err := os.WriteFile(file, []byte(unsortedObject), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	var out bytes.Buffer
+	session := &watchSession{
+		config:   processor.Config{Write: true, Workers: 1},
+		resolver: resolver,
+		pool:     newWorkerPool(1, processor.ProcessFileAST),
+		out:      &out,
+		seen:     make(map[string][sha256.Size]byte),
+	}
+	defer session.pool.close()
+
+	if got := session.modified([]string{file}); len(got) != 1 {
+		t.Fatalf("Expected a file never processed to count as modified, got %v", got)
+	}
+
+	session.process([]string{file})
+	content, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if string(content) != sortedObject {
+		t.Fatalf("Expected the file to be sorted, got:\n%s", content)
+	}
+	if got := session.modified([]string{file}); len(got) != 0 {
+		t.Errorf("Expected the session's own write to be skipped, got %v", got)
+	}
+
+	if err := os.WriteFile(file, []byte(unsortedObject), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if got := session.modified([]string{file}); len(got) != 1 {
+		t.Errorf("Expected a changed file to count as modified, got %v", got)
+	}
+
+	if err := os.Remove(file); err != nil {
+		t.Fatalf("Failed to remove file: %v", err)
+	}
+	if got := session.modified([]string{file}); len(got) != 0 {
+		t.Errorf("Expected a deleted file to be dropped, got %v", got)
+	}
+	if _, ok := session.seen[file]; ok {
+		t.Errorf("Expected a deleted file to be forgotten")
+	}
+}
@@ -35,6 +35,16 @@ func TestParseSortConfig(t *testing.T) {
 			comment: `/** tree-sorter-ts: keep-sorted key="name" */`,
 			want:    SortConfig{Key: "name"},
 		},
+		{
+			name:    "group-by-kind option",
+			comment: "/** tree-sorter-ts: keep-sorted group-by-kind */",
+			want:    SortConfig{GroupByKind: true},
+		},
+		{
+			name:    "enum value options",
+			comment: "/** tree-sorter-ts: keep-sorted sort-by-value materialize-values */",
+			want:    SortConfig{SortByValue: true, MaterializeValues: true},
+		},
 		{
 			name:    "multiple options",
 			comment: "/** tree-sorter-ts: keep-sorted deprecated-at-end with-new-line */",
@@ -49,6 +59,11 @@ func TestParseSortConfig(t *testing.T) {
 			 */`,
 			want: SortConfig{WithNewLine: true, DeprecatedAtEnd: true},
 		},
+		{
+			name:    "start-sort section options",
+			comment: `/** tree-sorter-ts: start-sort key="id" with-new-line */`,
+			want:    SortConfig{Key: "id", WithNewLine: true},
+		},
 	}
 
 	for _, tt := range tests {
@@ -63,6 +78,15 @@ func TestParseSortConfig(t *testing.T) {
 			if got.SortByComment != tt.want.SortByComment {
 				t.Errorf("SortByComment = %v, want %v", got.SortByComment, tt.want.SortByComment)
 			}
+			if got.GroupByKind != tt.want.GroupByKind {
+				t.Errorf("GroupByKind = %v, want %v", got.GroupByKind, tt.want.GroupByKind)
+			}
+			if got.SortByValue != tt.want.SortByValue {
+				t.Errorf("SortByValue = %v, want %v", got.SortByValue, tt.want.SortByValue)
+			}
+			if got.MaterializeValues != tt.want.MaterializeValues {
+				t.Errorf("MaterializeValues = %v, want %v", got.MaterializeValues, tt.want.MaterializeValues)
+			}
 			if got.Key != tt.want.Key {
 				t.Errorf("Key = %q, want %q", got.Key, tt.want.Key)
 			}
@@ -0,0 +1,251 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/fileutil"
+)
+
+// ProjectConfigNames lists the project configuration file names, in the
+// order they are looked for in each directory
+var ProjectConfigNames = []string{".tree-sorter-ts.json", "tree-sorter-ts.config"}
+
+// ProjectConfig holds the settings of a project configuration file. Both file
+// names use the same JSON format.
+type ProjectConfig struct {
+	Path       string     `json:"-"`          // File the configuration was loaded from
+	Extensions []string   `json:"extensions"` // File extensions to process
+	Workers    int        `json:"workers"`    // Number of parallel workers
+	Ignore     []string   `json:"ignore"`     // Glob patterns of files to skip
+	Options    string     `json:"options"`    // Default magic comment options
+	Overrides  []Override `json:"overrides"`  // Settings for files matching glob patterns
+
+	defaults SortConfig
+}
+
+// Override applies settings to the files matching its glob patterns. Later
+// overrides take precedence over earlier ones.
+type Override struct {
+	Files   []string `json:"files"`   // Glob patterns relative to the configuration file
+	Options string   `json:"options"` // Magic comment options added for matching files
+	Ignore  bool     `json:"ignore"`  // Skip matching files
+
+	defaults SortConfig
+}
+
+// FileSettings are the project settings that apply to a single file
+type FileSettings struct {
+	Ignored  bool
+	Defaults SortConfig // Default magic comment options
+}
+
+// LoadProjectConfig reads and validates a project configuration file
+func LoadProjectConfig(path string) (*ProjectConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config %s: %w", path, err)
+	}
+
+	var pc ProjectConfig
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&pc); err != nil {
+		return nil, fmt.Errorf("parsing config %s: %w", path, err)
+	}
+	pc.Path = path
+
+	if pc.Workers < 0 {
+		return nil, fmt.Errorf("config %s: workers must not be negative", path)
+	}
+	for _, ext := range pc.Extensions {
+		if !strings.HasPrefix(ext, ".") {
+			return nil, fmt.Errorf("config %s: extension %q must start with a dot", path, ext)
+		}
+	}
+	if pc.defaults, err = ParseOptions(pc.Options); err != nil {
+		return nil, fmt.Errorf("config %s: options: %w", path, err)
+	}
+	for i := range pc.Overrides {
+		override := &pc.Overrides[i]
+		if len(override.Files) == 0 {
+			return nil, fmt.Errorf("config %s: override %d has no files patterns", path, i+1)
+		}
+		if override.defaults, err = ParseOptions(override.Options); err != nil {
+			return nil, fmt.Errorf("config %s: override %d options: %w", path, i+1, err)
+		}
+	}
+
+	return &pc, nil
+}
+
+// FindProjectConfig returns the path of the nearest project configuration
+// file in dir or one of its parents, or "" when there is none
+func FindProjectConfig(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		for _, name := range ProjectConfigNames {
+			candidate := filepath.Join(dir, name)
+			info, err := os.Stat(candidate)
+			if err == nil && !info.IsDir() {
+				return candidate, nil
+			}
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return "", fmt.Errorf("cannot access %s: %w", candidate, err)
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// SettingsFor returns the settings for path, matching the ignore and override
+// patterns against the path relative to the configuration file's directory
+func (pc *ProjectConfig) SettingsFor(path string) FileSettings {
+	settings := FileSettings{Defaults: pc.defaults}
+
+	rel, ok := pc.relativePath(path)
+	if !ok {
+		return settings
+	}
+
+	settings.Ignored = matchesAny(pc.Ignore, rel)
+	for _, override := range pc.Overrides {
+		if !matchesAny(override.Files, rel) {
+			continue
+		}
+		settings.Defaults = override.defaults.WithDefaults(settings.Defaults)
+		settings.Ignored = settings.Ignored || override.Ignore
+	}
+	return settings
+}
+
+func (pc *ProjectConfig) relativePath(path string) (string, bool) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", false
+	}
+	rel, err := filepath.Rel(filepath.Dir(pc.Path), abs)
+	if err != nil {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// matchesAny reports whether the slash-separated relative path matches one of
+// the patterns. Like .gitignore, a pattern without a slash matches a name at
+// any depth, and a pattern matching a directory matches everything below it.
+func matchesAny(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.TrimSuffix(strings.TrimPrefix(pattern, "./"), "/")
+		if pattern == "" {
+			continue
+		}
+		if !strings.Contains(pattern, "/") {
+			pattern = "**/" + pattern
+		}
+		if fileutil.MatchGlob(pattern, rel) || fileutil.MatchGlob(pattern+"/**", rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolver finds the project configuration that applies to each file
+type Resolver struct {
+	explicit *ProjectConfig
+
+	mu    sync.Mutex
+	byDir map[string]*ProjectConfig // Nearest configuration per directory, nil for none
+}
+
+// NewResolver creates a resolver. With an explicit configuration path that
+// file applies to every file; otherwise each file uses its nearest
+// configuration file.
+func NewResolver(explicitPath string) (*Resolver, error) {
+	r := &Resolver{byDir: make(map[string]*ProjectConfig)}
+	if explicitPath != "" {
+		pc, err := LoadProjectConfig(explicitPath)
+		if err != nil {
+			return nil, err
+		}
+		r.explicit = pc
+	}
+	return r, nil
+}
+
+// ForDir returns the configuration that applies to files in dir, or nil when there is none
+func (r *Resolver) ForDir(dir string) (*ProjectConfig, error) {
+	if r.explicit != nil {
+		return r.explicit, nil
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if pc, ok := r.byDir[abs]; ok {
+		return pc, nil
+	}
+
+	path, err := FindProjectConfig(abs)
+	if err != nil {
+		return nil, err
+	}
+
+	var pc *ProjectConfig
+	if path != "" {
+		// Directories sharing a configuration file share the loaded copy
+		for _, loaded := range r.byDir {
+			if loaded != nil && loaded.Path == path {
+				pc = loaded
+				break
+			}
+		}
+		if pc == nil {
+			if pc, err = LoadProjectConfig(path); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	r.byDir[abs] = pc
+	return pc, nil
+}
+
+// SettingsFor returns the project settings for a file
+func (r *Resolver) SettingsFor(path string) (FileSettings, error) {
+	pc, err := r.ForDir(filepath.Dir(path))
+	if err != nil || pc == nil {
+		return FileSettings{}, err
+	}
+	return pc.SettingsFor(path), nil
+}
+
+// SettingsForContent returns the project settings for content that may not
+// come from a file, such as stdin. Without a path the content is treated as a
+// file in the current directory.
+func (r *Resolver) SettingsForContent(path string) (FileSettings, error) {
+	if path == "" {
+		path = "stdin.ts"
+	}
+	return r.SettingsFor(path)
+}
@@ -0,0 +1,232 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfigFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+}
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		options string
+		want    SortConfig
+		wantErr string
+	}{
+		{name: "empty", options: "", want: SortConfig{}},
+		{name: "booleans", options: "deprecated-at-end with-new-line", want: SortConfig{DeprecatedAtEnd: true, WithNewLine: true}},
+		{name: "key", options: `key="id"`, want: SortConfig{Key: "id"}},
+		{name: "separate_key_value", options: `key= "id" with-new-line`, want: SortConfig{Key: "id", WithNewLine: true}},
+		{name: "unknown_option", options: "deprecated-at-end sort-fast", wantErr: `unknown option "sort-fast"`},
+		{name: "conflicting_options", options: `key="id" sort-by-comment`, wantErr: "cannot use both"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseOptions(tt.options)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseOptions(%q) error = %v, want %q", tt.options, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseOptions(%q) failed: %v", tt.options, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseOptions(%q) = %+v, want %+v", tt.options, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithDefaults(t *testing.T) {
+	tests := []struct {
+		name     string
+		comment  SortConfig
+		defaults SortConfig
+		want     SortConfig
+	}{
+		{
+			name:     "booleans_combine",
+			comment:  SortConfig{WithNewLine: true},
+			defaults: SortConfig{DeprecatedAtEnd: true},
+			want:     SortConfig{WithNewLine: true, DeprecatedAtEnd: true},
+		},
+		{
+			name:     "default_sort_mode",
+			comment:  SortConfig{},
+			defaults: SortConfig{SortByComment: true},
+			want:     SortConfig{SortByComment: true},
+		},
+		{
+			name:     "comment_sort_mode_wins",
+			comment:  SortConfig{Key: "id"},
+			defaults: SortConfig{SortByComment: true},
+			want:     SortConfig{Key: "id"},
+		},
+		{
+			name:     "conflict_in_comment_is_kept",
+			comment:  SortConfig{Key: "id", SortByComment: true},
+			defaults: SortConfig{},
+			want:     SortConfig{Key: "id", SortByComment: true, HasError: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.comment.WithDefaults(tt.defaults); got != tt.want {
+				t.Errorf("WithDefaults() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadProjectConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "valid",
+			content: `{"extensions": [".ts"], "workers": 2, "ignore": ["dist"], "options": "deprecated-at-end", "overrides": [{"files": ["src/**"], "options": "with-new-line"}]}`,
+		},
+		{name: "invalid_json", content: `{"extensions": [".ts"`, wantErr: "parsing config"},
+		{name: "unknown_field", content: `{"extension": [".ts"]}`, wantErr: `unknown field "extension"`},
+		{name: "bad_extension", content: `{"extensions": ["ts"]}`, wantErr: "must start with a dot"},
+		{name: "negative_workers", content: `{"workers": -1}`, wantErr: "workers must not be negative"},
+		{name: "unknown_option", content: `{"options": "sideways"}`, wantErr: `unknown option "sideways"`},
+		{name: "override_without_files", content: `{"overrides": [{"options": "with-new-line"}]}`, wantErr: "override 1 has no files patterns"},
+		{name: "bad_override_option", content: `{"overrides": [{"files": ["a"], "options": "upside-down"}]}`, wantErr: "override 1 options"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".tree-sorter-ts.json")
+			writeConfigFile(t, path, tt.content)
+
+			pc, err := LoadProjectConfig(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadProjectConfig error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadProjectConfig failed: %v", err)
+			}
+			if pc.Path != path || pc.Workers != 2 || len(pc.Extensions) != 1 {
+				t.Errorf("unexpected config: %+v", pc)
+			}
+		})
+	}
+}
+
+func TestProjectConfigSettingsFor(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "tree-sorter-ts.config")
+	writeConfigFile(t, path, `{
+  "ignore": ["dist", "*.generated.ts"],
+  "options": "deprecated-at-end",
+  "overrides": [
+    {"files": ["src/legacy/**"], "options": "with-new-line"},
+    {"files": ["src/legacy/vendor/*.ts"], "ignore": true}
+  ]
+}`)
+
+	pc, err := LoadProjectConfig(path)
+	if err != nil {
+		t.Fatalf("LoadProjectConfig failed: %v", err)
+	}
+
+	tests := []struct {
+		file        string
+		wantIgnored bool
+		want        SortConfig
+	}{
+		{file: "src/app.ts", want: SortConfig{DeprecatedAtEnd: true}},
+		{file: "src/legacy/old.ts", want: SortConfig{DeprecatedAtEnd: true, WithNewLine: true}},
+		{file: "src/legacy/vendor/lib.ts", wantIgnored: true, want: SortConfig{DeprecatedAtEnd: true, WithNewLine: true}},
+		{file: "dist/app.ts", wantIgnored: true, want: SortConfig{DeprecatedAtEnd: true}},
+		{file: "src/nested/api.generated.ts", wantIgnored: true, want: SortConfig{DeprecatedAtEnd: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			got := pc.SettingsFor(filepath.Join(root, filepath.FromSlash(tt.file)))
+			if got.Ignored != tt.wantIgnored {
+				t.Errorf("Ignored = %v, want %v", got.Ignored, tt.wantIgnored)
+			}
+			if got.Defaults != tt.want {
+				t.Errorf("Defaults = %+v, want %+v", got.Defaults, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolver(t *testing.T) {
+	root := t.TempDir()
+	writeConfigFile(t, filepath.Join(root, ".tree-sorter-ts.json"), `{"options": "deprecated-at-end"}`)
+	writeConfigFile(t, filepath.Join(root, "packages", "web", "tree-sorter-ts.config"), `{"options": "with-new-line"}`)
+	explicitPath := filepath.Join(root, "explicit.json")
+	writeConfigFile(t, explicitPath, `{"options": "sort-by-comment"}`)
+
+	resolver, err := NewResolver("")
+	if err != nil {
+		t.Fatalf("NewResolver failed: %v", err)
+	}
+
+	tests := []struct {
+		file string
+		want SortConfig
+	}{
+		{file: "src/app.ts", want: SortConfig{DeprecatedAtEnd: true}},
+		{file: "packages/api/src/app.ts", want: SortConfig{DeprecatedAtEnd: true}},
+		// The nearest configuration file wins; configurations are not merged
+		{file: "packages/web/src/app.ts", want: SortConfig{WithNewLine: true}},
+	}
+	for _, tt := range tests {
+		settings, err := resolver.SettingsFor(filepath.Join(root, filepath.FromSlash(tt.file)))
+		if err != nil {
+			t.Fatalf("SettingsFor(%s) failed: %v", tt.file, err)
+		}
+		if settings.Defaults != tt.want {
+			t.Errorf("SettingsFor(%s).Defaults = %+v, want %+v", tt.file, settings.Defaults, tt.want)
+		}
+	}
+
+	explicit, err := NewResolver(explicitPath)
+	if err != nil {
+		t.Fatalf("NewResolver failed: %v", err)
+	}
+	settings, err := explicit.SettingsFor(filepath.Join(root, "packages", "web", "src", "app.ts"))
+	if err != nil {
+		t.Fatalf("SettingsFor failed: %v", err)
+	}
+	if settings.Defaults != (SortConfig{SortByComment: true}) {
+		t.Errorf("explicit config should apply to every file, got %+v", settings.Defaults)
+	}
+	settings, err = explicit.SettingsForContent("")
+	if err != nil {
+		t.Fatalf("SettingsForContent failed: %v", err)
+	}
+	if settings.Defaults != (SortConfig{SortByComment: true}) {
+		t.Errorf("explicit config should apply to content without a path, got %+v", settings.Defaults)
+	}
+
+	if _, err := NewResolver(filepath.Join(root, "missing.json")); err == nil {
+		t.Error("expected error for missing explicit config")
+	}
+}
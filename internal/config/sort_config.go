@@ -11,19 +11,24 @@ type SortConfig struct {
 	DeprecatedAtEnd bool
 	Key             string // For array sorting
 	SortByComment   bool   // Sort by comment content
-	HasError        bool   // Indicates a validation error
+	GroupByKind     bool   // Group class members by kind before sorting them by name
+	SortByValue     bool   // Sort enum members by value
+	// MaterializeValues writes out the values of enum members that depend on
+	// their position before sorting them
+	MaterializeValues bool
+	HasError          bool // Indicates a validation error
 }
 
 // ParseSortConfig extracts configuration from a magic comment
 func ParseSortConfig(commentText []byte) SortConfig {
-	config := SortConfig{}
+	var config SortConfig
 
 	// Extract configuration from magic comment
 	text := string(commentText)
 	// Look for the pattern inside the comment
-	if strings.Contains(text, "tree-sorter-ts:") && strings.Contains(text, "keep-sorted") {
-		// Find the part after "keep-sorted"
-		parts := strings.Split(text, "keep-sorted")
+	if marker := commentMarker(text); marker != "" {
+		// Find the part after the marker
+		parts := strings.Split(text, marker)
 		if len(parts) > 1 {
 			// Extract the configuration part before the closing */
 			configPart := parts[1]
@@ -47,34 +52,104 @@ func ParseSortConfig(commentText []byte) SortConfig {
 			configPart = strings.Join(cleanedLines, " ")
 
 			// Parse configuration options
-			options := strings.Fields(configPart)
-			for i, opt := range options {
-				switch opt {
-				case "with-new-line":
-					config.WithNewLine = true
-				case "deprecated-at-end":
-					config.DeprecatedAtEnd = true
-				case "sort-by-comment":
-					config.SortByComment = true
-				default:
-					// Check for key="value" pattern
-					if strings.HasPrefix(opt, "key=") {
-						// Extract the quoted value
-						keyPart := opt[4:]
-						keyPart = strings.Trim(keyPart, "\"'")
-						config.Key = keyPart
-					} else if opt == "key=" && i+1 < len(options) {
-						// Handle case where key= and value are separate
-						config.Key = strings.Trim(options[i+1], "\"'")
-					}
-				}
-			}
+			config, _ = parseOptions(strings.Fields(configPart))
 		}
 	}
 
 	return config
 }
 
+// commentMarkers are the magic comment keywords that options can follow:
+// keep-sorted sorts the rest of a structure, start-sort opens a section
+// that an end-sort marker closes
+var commentMarkers = []string{"keep-sorted", "start-sort"}
+
+// commentMarker returns the marker of a magic comment, or "" if there is none
+func commentMarker(text string) string {
+	if !strings.Contains(text, "tree-sorter-ts:") {
+		return ""
+	}
+	for _, marker := range commentMarkers {
+		if strings.Contains(text, marker) {
+			return marker
+		}
+	}
+	return ""
+}
+
+// ParseOptions parses magic comment options written without the comment
+// itself, such as "deprecated-at-end with-new-line", as used in project
+// configuration files. Unlike ParseSortConfig it rejects unknown options.
+func ParseOptions(options string) (SortConfig, error) {
+	config, unknown := parseOptions(strings.Fields(options))
+	if len(unknown) > 0 {
+		return config, fmt.Errorf("unknown option %q", unknown[0])
+	}
+	if err := config.Validate(); err != nil {
+		return config, err
+	}
+	return config, nil
+}
+
+// parseOptions parses option words, returning the ones it did not recognize
+func parseOptions(options []string) (SortConfig, []string) {
+	config := SortConfig{}
+	var unknown []string
+
+	for i := 0; i < len(options); i++ {
+		opt := options[i]
+		switch opt {
+		case "with-new-line":
+			config.WithNewLine = true
+		case "deprecated-at-end":
+			config.DeprecatedAtEnd = true
+		case "sort-by-comment":
+			config.SortByComment = true
+		case "group-by-kind":
+			config.GroupByKind = true
+		case "sort-by-value":
+			config.SortByValue = true
+		case "materialize-values":
+			config.MaterializeValues = true
+		default:
+			// Check for key="value" pattern
+			if opt == "key=" && i+1 < len(options) {
+				// Handle case where key= and value are separate
+				config.Key = strings.Trim(options[i+1], "\"'")
+				i++
+			} else if strings.HasPrefix(opt, "key=") {
+				// Extract the quoted value
+				keyPart := opt[4:]
+				keyPart = strings.Trim(keyPart, "\"'")
+				config.Key = keyPart
+			} else {
+				unknown = append(unknown, opt)
+			}
+		}
+	}
+
+	return config, unknown
+}
+
+// WithDefaults fills in options the magic comment left unset from defaults,
+// such as those of a project configuration file. Boolean options are
+// combined; choosing a sort mode (key, sort-by-comment or sort-by-value) in
+// the comment replaces the default sort mode.
+func (c SortConfig) WithDefaults(defaults SortConfig) SortConfig {
+	merged := c
+	merged.WithNewLine = c.WithNewLine || defaults.WithNewLine
+	merged.DeprecatedAtEnd = c.DeprecatedAtEnd || defaults.DeprecatedAtEnd
+	merged.GroupByKind = c.GroupByKind || defaults.GroupByKind
+	merged.MaterializeValues = c.MaterializeValues || defaults.MaterializeValues
+	if c.Key == "" && !c.SortByComment && !c.SortByValue {
+		merged.Key = defaults.Key
+		merged.SortByComment = defaults.SortByComment
+		merged.SortByValue = defaults.SortByValue
+	}
+	merged.HasError = merged.Key != "" && merged.SortByComment
+	return merged
+}
+
 // Validate checks for configuration conflicts and returns an error if found
 func (c *SortConfig) Validate() error {
 	// Validation: cannot use both key and sort-by-comment
@@ -93,5 +168,8 @@ func (c *SortConfig) GetSortingMode() string {
 	if c.Key != "" {
 		return fmt.Sprintf("key=%q", c.Key)
 	}
+	if c.SortByValue {
+		return "sort-by-value"
+	}
 	return "property-name"
 }
\ No newline at end of file
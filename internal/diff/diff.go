@@ -0,0 +1,325 @@
+package diff
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// ANSI escape sequences used when colored output is requested
+const (
+	colorReset  = "\x1b[0m"
+	colorBold   = "\x1b[1m"
+	colorRed    = "\x1b[31m"
+	colorGreen  = "\x1b[32m"
+	colorCyan   = "\x1b[36m"
+	noNewlineAt = "\\ No newline at end of file"
+)
+
+// Options controls how a unified diff is rendered
+type Options struct {
+	FromFile string // Name shown on the "---" line
+	ToFile   string // Name shown on the "+++" line
+	Context  int    // Number of unchanged lines shown around each change
+	Color    bool   // Colorize the output with ANSI escape sequences
+}
+
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+// op is one step of the edit script turning a into b
+type op struct {
+	kind opKind
+	line string
+}
+
+// Edit replaces the bytes a[Start:End] with NewText
+type Edit struct {
+	Start   int
+	End     int
+	NewText string
+}
+
+// Unified returns a unified diff between a and b, or an empty string when they are equal
+func Unified(a, b []byte, opts Options) string {
+	if bytes.Equal(a, b) {
+		return ""
+	}
+	return render(diffLines(splitLines(a), splitLines(b)), opts)
+}
+
+// UnifiedEdits returns the unified diff of applying edits to a, or an empty
+// string when there are none. Only the lines the edits touch are compared, so
+// small changes to large files stay cheap. The edits must not overlap and
+// must be ordered by position.
+func UnifiedEdits(a []byte, edits []Edit, opts Options) string {
+	if len(edits) == 0 {
+		return ""
+	}
+
+	var ops []op
+	pos := 0
+	for i := 0; i < len(edits); {
+		// Widen the edit to whole lines, taking in the edits on the same or the
+		// following line
+		regionStart := bytes.LastIndexByte(a[:edits[i].Start], '\n') + 1
+		regionEnd := lineEnd(a, edits[i].End)
+		j := i + 1
+		for j < len(edits) && edits[j].Start <= regionEnd {
+			regionEnd = max(regionEnd, lineEnd(a, edits[j].End))
+			j++
+		}
+
+		var region []byte
+		cursor := regionStart
+		for _, edit := range edits[i:j] {
+			region = append(region, a[cursor:edit.Start]...)
+			region = append(region, edit.NewText...)
+			cursor = edit.End
+		}
+		region = append(region, a[cursor:regionEnd]...)
+
+		for _, line := range splitLines(a[pos:regionStart]) {
+			ops = append(ops, op{kind: opEqual, line: line})
+		}
+		ops = append(ops, diffLines(splitLines(a[regionStart:regionEnd]), splitLines(region))...)
+		pos = regionEnd
+		i = j
+	}
+	for _, line := range splitLines(a[pos:]) {
+		ops = append(ops, op{kind: opEqual, line: line})
+	}
+
+	for _, o := range ops {
+		if o.kind != opEqual {
+			return render(ops, opts)
+		}
+	}
+	return ""
+}
+
+// lineEnd returns the offset just past the newline ending the line that
+// contains offset, or the end of content
+func lineEnd(content []byte, offset int) int {
+	if next := bytes.IndexByte(content[offset:], '\n'); next >= 0 {
+		return offset + next + 1
+	}
+	return len(content)
+}
+
+// render formats an edit script as a unified diff
+func render(ops []op, opts Options) string {
+	if opts.Context < 0 {
+		opts.Context = 0
+	}
+
+	var sb strings.Builder
+	writeLine(&sb, opts.Color, colorBold, "--- "+opts.FromFile+"\n")
+	writeLine(&sb, opts.Color, colorBold, "+++ "+opts.ToFile+"\n")
+
+	for _, h := range groupHunks(ops, opts.Context) {
+		header := fmt.Sprintf("@@ -%s +%s @@\n", hunkRange(h.aStart, h.aLen), hunkRange(h.bStart, h.bLen))
+		writeLine(&sb, opts.Color, colorCyan, header)
+
+		for _, o := range ops[h.start:h.end] {
+			switch o.kind {
+			case opEqual:
+				writeDiffLine(&sb, opts.Color, "", " ", o.line)
+			case opDelete:
+				writeDiffLine(&sb, opts.Color, colorRed, "-", o.line)
+			case opInsert:
+				writeDiffLine(&sb, opts.Color, colorGreen, "+", o.line)
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+// hunk is a run of ops shown together, with the 0-based line positions it covers
+type hunk struct {
+	start, end   int
+	aStart, aLen int
+	bStart, bLen int
+}
+
+// groupHunks splits the edit script into hunks, merging changes whose
+// surrounding context would overlap
+func groupHunks(ops []op, context int) []hunk {
+	var hunks []hunk
+
+	aPos, bPos := make([]int, len(ops)+1), make([]int, len(ops)+1)
+	for i, o := range ops {
+		aPos[i+1], bPos[i+1] = aPos[i], bPos[i]
+		if o.kind != opInsert {
+			aPos[i+1]++
+		}
+		if o.kind != opDelete {
+			bPos[i+1]++
+		}
+	}
+
+	for i := 0; i < len(ops); {
+		if ops[i].kind == opEqual {
+			i++
+			continue
+		}
+
+		// Extend the hunk until a run of unchanged lines is long enough to split on
+		start := max(i-context, 0)
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != opEqual {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].kind == opEqual {
+				run++
+			}
+			if run == len(ops) || run-end > 2*context {
+				end = min(end+context, len(ops))
+				break
+			}
+			end = run
+		}
+
+		hunks = append(hunks, hunk{
+			start:  start,
+			end:    end,
+			aStart: aPos[start],
+			aLen:   aPos[end] - aPos[start],
+			bStart: bPos[start],
+			bLen:   bPos[end] - bPos[start],
+		})
+		i = end
+	}
+
+	return hunks
+}
+
+// hunkRange formats a hunk header range; empty ranges point at the preceding line
+func hunkRange(start, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if length == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, length)
+}
+
+// writeLine writes a header line, wrapping it in the color code when enabled
+func writeLine(sb *strings.Builder, color bool, code, text string) {
+	if color {
+		sb.WriteString(code)
+		sb.WriteString(strings.TrimSuffix(text, "\n"))
+		sb.WriteString(colorReset + "\n")
+		return
+	}
+	sb.WriteString(text)
+}
+
+// writeDiffLine writes one prefixed content line, marking a missing final newline
+func writeDiffLine(sb *strings.Builder, color bool, code, prefix, line string) {
+	text := prefix + strings.TrimSuffix(line, "\n")
+	if color && code != "" {
+		text = code + text + colorReset
+	}
+	sb.WriteString(text + "\n")
+	if !strings.HasSuffix(line, "\n") {
+		sb.WriteString(noNewlineAt + "\n")
+	}
+}
+
+// splitLines splits content into lines, keeping each line's newline
+func splitLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines computes a shortest edit script between a and b using Myers' algorithm
+func diffLines(a, b []string) []op {
+	n, m := len(a), len(b)
+	maxSteps := n + m
+	offset := maxSteps + 1
+	v := make([]int, 2*maxSteps+3)
+
+	// trace[d] holds the furthest reaching x for each diagonal before step d
+	var trace [][]int
+	steps := 0
+
+search:
+	for d := 0; d <= maxSteps; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				steps = d
+				break search
+			}
+		}
+	}
+
+	// Walk back through the trace to recover the edit script
+	ops := make([]op, 0, n+m)
+	x, y := n, m
+	for d := steps; d > 0; d-- {
+		prev := trace[d]
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && prev[offset+k-1] < prev[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := prev[offset+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			ops = append(ops, op{kind: opEqual, line: a[x-1]})
+			x--
+			y--
+		}
+		if x == prevX {
+			ops = append(ops, op{kind: opInsert, line: b[y-1]})
+			y--
+		} else {
+			ops = append(ops, op{kind: opDelete, line: a[x-1]})
+			x--
+		}
+	}
+	for x > 0 && y > 0 {
+		ops = append(ops, op{kind: opEqual, line: a[x-1]})
+		x--
+		y--
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
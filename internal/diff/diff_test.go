@@ -0,0 +1,259 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name    string
+		a       string
+		b       string
+		context int
+		want    string
+	}{
+		{
+			name: "identical",
+			a:    "a\nb\n",
+			b:    "a\nb\n",
+			want: "",
+		},
+		{
+			name: "swapped_lines",
+			a: `const config = {
+  /** tree-sorter-ts: keep-sorted **/
+  zebra: 1,
+  alpha: 2,
+};
+`,
+			b: `const config = {
+  /** tree-sorter-ts: keep-sorted **/
+  alpha: 2,
+  zebra: 1,
+};
+`,
+			context: 3,
+			want: `--- a/file.ts
++++ b/file.ts
+@@ -1,5 +1,5 @@
+ const config = {
+   /** tree-sorter-ts: keep-sorted **/
+-  zebra: 1,
+   alpha: 2,
++  zebra: 1,
+ };
+`,
+		},
+		{
+			name:    "separate_hunks",
+			a:       "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			b:       "1\nX\n3\n4\n5\n6\n7\nY\n9\n",
+			context: 1,
+			want: `--- a/file.ts
++++ b/file.ts
+@@ -1,3 +1,3 @@
+ 1
+-2
++X
+ 3
+@@ -7,3 +7,3 @@
+ 7
+-8
++Y
+ 9
+`,
+		},
+		{
+			name:    "merged_hunks",
+			a:       "1\n2\n3\n4\n5\n",
+			b:       "1\nX\n3\nY\n5\n",
+			context: 1,
+			want: `--- a/file.ts
++++ b/file.ts
+@@ -1,5 +1,5 @@
+ 1
+-2
++X
+ 3
+-4
++Y
+ 5
+`,
+		},
+		{
+			name:    "zero_context_insertion",
+			a:       "1\n2\n",
+			b:       "1\nX\n2\n",
+			context: 0,
+			want: `--- a/file.ts
++++ b/file.ts
+@@ -1,0 +2 @@
++X
+`,
+		},
+		{
+			name:    "missing_final_newline",
+			a:       "b\na",
+			b:       "a\nb\n",
+			context: 3,
+			want: `--- a/file.ts
++++ b/file.ts
+@@ -1,2 +1,2 @@
++a
+ b
+-a
+\ No newline at end of file
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Unified([]byte(tt.a), []byte(tt.b), Options{
+				FromFile: "a/file.ts",
+				ToFile:   "b/file.ts",
+				Context:  tt.context,
+			})
+			if got != tt.want {
+				t.Errorf("Unified() mismatch\nGot:\n%s\nWant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnifiedColor(t *testing.T) {
+	got := Unified([]byte("b\na\n"), []byte("a\nb\n"), Options{
+		FromFile: "a/file.ts",
+		ToFile:   "b/file.ts",
+		Context:  3,
+		Color:    true,
+	})
+
+	for _, want := range []string{
+		colorBold + "--- a/file.ts" + colorReset + "\n",
+		colorCyan + "@@ -1,2 +1,2 @@" + colorReset + "\n",
+		colorRed + "-b" + colorReset + "\n",
+		colorGreen + "+b" + colorReset + "\n",
+		" a\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("colored diff missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestUnifiedEdits(t *testing.T) {
+	long := strings.Repeat("line\n", 20)
+	a := "const a = {\n  b: 1,\n  a: 2,\n};\n" + long + "const x = [2, 1];\nconst y = [\"b\", \"a\"]"
+
+	tests := []struct {
+		name  string
+		edits []Edit
+		hunks int
+	}{
+		{
+			name: "no edits",
+		},
+		{
+			name: "no change",
+			edits: []Edit{
+				{Start: 14, End: 18, NewText: "b: 1"},
+			},
+		},
+		{
+			name: "one edit within a line",
+			edits: []Edit{
+				{Start: 14, End: 15, NewText: "c"},
+			},
+			hunks: 1,
+		},
+		{
+			name: "edit spanning lines",
+			edits: []Edit{
+				{Start: 14, End: 27, NewText: "a: 2,\n  b: 1"},
+			},
+			hunks: 1,
+		},
+		{
+			name: "separate hunks and adjacent lines",
+			edits: []Edit{
+				{Start: 14, End: 27, NewText: "a: 2,\n  b: 1"},
+				{Start: 142, End: 146, NewText: "1, 2"},
+				{Start: 160, End: 163, NewText: "\"a\""},
+				{Start: 165, End: 168, NewText: "\"b\""},
+			},
+			hunks: 2,
+		},
+		{
+			name: "insertion at the end without a newline",
+			edits: []Edit{
+				{Start: len(a), End: len(a), NewText: ";\n"},
+			},
+			hunks: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b []byte
+			last := 0
+			for _, edit := range tt.edits {
+				b = append(b, a[last:edit.Start]...)
+				b = append(b, edit.NewText...)
+				last = edit.End
+			}
+			b = append(b, a[last:]...)
+
+			got := UnifiedEdits([]byte(a), tt.edits, Options{FromFile: "a/file.ts", ToFile: "b/file.ts", Context: 3})
+			if hunks := strings.Count(got, "\n@@ "); hunks != tt.hunks {
+				t.Errorf("got %d hunks, want %d:\n%s", hunks, tt.hunks, got)
+			}
+			if tt.hunks == 0 {
+				return
+			}
+			if patched := applyUnified(t, a, got); patched != string(b) {
+				t.Errorf("applying the diff gave:\n%s\nwant:\n%s\ndiff:\n%s", patched, b, got)
+			}
+		})
+	}
+}
+
+// applyUnified applies a unified diff produced by this package to a
+func applyUnified(t *testing.T, a, patch string) string {
+	t.Helper()
+
+	oldLines := splitLines([]byte(a))
+	var out []string
+	next := 0 // Index of the next old line to copy
+
+	var prev string
+	for _, line := range strings.Split(strings.TrimSuffix(patch, "\n"), "\n")[2:] {
+		switch {
+		case strings.HasPrefix(line, "@@ "):
+			var oldStart int
+			if _, err := fmt.Sscanf(line, "@@ -%d", &oldStart); err != nil {
+				t.Fatalf("Failed to parse hunk header %q: %v", line, err)
+			}
+			for ; next < oldStart-1; next++ {
+				out = append(out, oldLines[next])
+			}
+		case line == noNewlineAt:
+			// Marks the previous line; removed lines are not in the output
+			if !strings.HasPrefix(prev, "-") {
+				out[len(out)-1] = strings.TrimSuffix(out[len(out)-1], "\n")
+			}
+		case strings.HasPrefix(line, " "):
+			out = append(out, oldLines[next])
+			next++
+		case strings.HasPrefix(line, "-"):
+			next++
+		case strings.HasPrefix(line, "+"):
+			out = append(out, line[1:]+"\n")
+		}
+		prev = line
+	}
+	out = append(out, oldLines[next:]...)
+	return strings.Join(out, "")
+}
@@ -18,6 +18,16 @@ func HasValidExtension(path string, extensions []string) bool {
 
 // FindFiles recursively finds all files with the given extensions
 func FindFiles(root string, extensions []string, recursive bool) ([]string, error) {
+	return findFiles(root, extensions, recursive, nil)
+}
+
+// FindFiles is like the package-level FindFiles, additionally skipping
+// everything the filter excludes
+func (f *Filter) FindFiles(root string, extensions []string, recursive bool) ([]string, error) {
+	return findFiles(root, extensions, recursive, f)
+}
+
+func findFiles(root string, extensions []string, recursive bool, filter *Filter) ([]string, error) {
 	var files []string
 
 	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
@@ -25,21 +35,24 @@ func FindFiles(root string, extensions []string, recursive bool) ([]string, erro
 			return err
 		}
 
-		// Skip hidden directories and node_modules
+		// Skip hidden directories, node_modules and filtered directories below
+		// the root. The root itself is always searched so that paths like "." work.
 		if info.IsDir() {
-			baseName := filepath.Base(path)
-			if strings.HasPrefix(baseName, ".") || baseName == "node_modules" {
+			if path == root {
+				return nil
+			}
+			if filter.SkipsDir(path) {
 				return filepath.SkipDir
 			}
 			// Skip subdirectories if not recursive
-			if !recursive && path != root {
+			if !recursive {
 				return filepath.SkipDir
 			}
 			return nil
 		}
 
 		// Check if file has valid extension
-		if HasValidExtension(path, extensions) {
+		if HasValidExtension(path, extensions) && !filter.Skips(path, false) {
 			files = append(files, path)
 		}
 
@@ -48,3 +61,8 @@ func FindFiles(root string, extensions []string, recursive bool) ([]string, erro
 
 	return files, err
 }
+
+// isSkippedDir reports whether a directory is excluded from directory walks
+func isSkippedDir(name string) bool {
+	return strings.HasPrefix(name, ".") || name == "node_modules"
+}
@@ -0,0 +1,212 @@
+package fileutil
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// IsGlob reports whether a path contains shell-style glob metacharacters
+func IsGlob(p string) bool {
+	return strings.ContainsAny(p, "*?[")
+}
+
+// MatchGlob reports whether the slash-separated name matches the shell pattern.
+// Besides the path.Match syntax, a "**" segment matches zero or more segments.
+func MatchGlob(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(rest, name[i:]) {
+					return true
+				}
+				// Like a shell, wildcards never match hidden entries implicitly
+				if i < len(name) && strings.HasPrefix(name[i], ".") {
+					break
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+		if strings.HasPrefix(name[0], ".") && !strings.HasPrefix(pattern[0], ".") {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// Glob returns the files and directories matching pattern, which may contain
+// "**" to match any number of directories. Hidden directories and
+// node_modules are only searched when the pattern names them explicitly.
+func Glob(pattern string) ([]string, error) {
+	root, rest := splitGlob(pattern)
+	recursive := strings.Contains(strings.Join(rest, "/"), "**")
+
+	if _, err := os.Stat(root); err != nil {
+		return nil, fmt.Errorf("cannot access path %s: %w", root, err)
+	}
+
+	var matches []string
+	err := filepath.Walk(filepath.FromSlash(root), func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(filepath.FromSlash(root), p)
+		if err != nil || rel == "." {
+			return err
+		}
+		relSegments := strings.Split(filepath.ToSlash(rel), "/")
+
+		if info.IsDir() && isSkippedDir(info.Name()) && !patternNamesDir(rest, len(relSegments)-1, info.Name()) {
+			return filepath.SkipDir
+		}
+
+		if MatchGlob(strings.Join(rest, "/"), filepath.ToSlash(rel)) {
+			matches = append(matches, p)
+		}
+
+		// Without ** the pattern cannot match anything deeper than its segment count
+		if info.IsDir() && !recursive && len(relSegments) >= len(rest) {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+
+	return matches, err
+}
+
+// GlobBase returns the longest leading directory of pattern without
+// metacharacters, below which every match lies
+func GlobBase(pattern string) string {
+	root, _ := splitGlob(pattern)
+	return filepath.FromSlash(root)
+}
+
+// splitGlob splits a pattern into its base directory and the remaining
+// slash-separated segments
+func splitGlob(pattern string) (string, []string) {
+	slashPattern := filepath.ToSlash(pattern)
+	segments := strings.Split(slashPattern, "/")
+
+	// Walk from the longest leading directory without metacharacters
+	baseLen := 0
+	for baseLen < len(segments)-1 && !IsGlob(segments[baseLen]) {
+		baseLen++
+	}
+	root := strings.Join(segments[:baseLen], "/")
+	if root == "" {
+		root = "."
+		if strings.HasPrefix(slashPattern, "/") {
+			root = "/"
+		}
+	}
+	return root, segments[baseLen:]
+}
+
+// patternNamesDir reports whether the pattern segment at depth literally names dir
+func patternNamesDir(pattern []string, depth int, dir string) bool {
+	return depth < len(pattern) && pattern[depth] == dir
+}
+
+// isWithinAny reports whether p lies inside one of dirs
+func isWithinAny(p string, dirs []string) bool {
+	for _, dir := range dirs {
+		if strings.HasPrefix(p, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolvePath expands a single command line argument, which may be a file,
+// a directory or a glob pattern, into the files to process
+func ResolvePath(arg string, extensions []string, recursive bool) ([]string, error) {
+	return resolvePath(arg, extensions, recursive, nil)
+}
+
+// ResolvePath is like the package-level ResolvePath, additionally skipping
+// everything the filter excludes. Filtered paths named explicitly resolve to
+// no files rather than an error.
+func (f *Filter) ResolvePath(arg string, extensions []string, recursive bool) ([]string, error) {
+	return resolvePath(arg, extensions, recursive, f)
+}
+
+func resolvePath(arg string, extensions []string, recursive bool, filter *Filter) ([]string, error) {
+	if IsGlob(arg) {
+		matches, err := Glob(arg)
+		if err != nil {
+			return nil, err
+		}
+
+		var files []string
+		var expandedDirs []string
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err != nil {
+				return nil, fmt.Errorf("cannot access path %s: %w", match, err)
+			}
+			if filter.Skips(match, info.IsDir()) {
+				continue
+			}
+			if info.IsDir() {
+				// Directories below an already expanded directory add nothing new
+				if recursive && isWithinAny(match, expandedDirs) {
+					continue
+				}
+				expandedDirs = append(expandedDirs, match)
+
+				dirFiles, err := findFiles(match, extensions, recursive, filter)
+				if err != nil {
+					return nil, fmt.Errorf("error finding files: %w", err)
+				}
+				files = append(files, dirFiles...)
+			} else if HasValidExtension(match, extensions) {
+				files = append(files, match)
+			}
+		}
+
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no files match pattern %s", arg)
+		}
+		return files, nil
+	}
+
+	info, err := os.Stat(arg)
+	if err != nil {
+		return nil, fmt.Errorf("cannot access path %s: %w", arg, err)
+	}
+
+	if info.IsDir() {
+		if filter.Skips(arg, true) {
+			return nil, nil
+		}
+		files, err := findFiles(arg, extensions, recursive, filter)
+		if err != nil {
+			return nil, fmt.Errorf("error finding files: %w", err)
+		}
+		return files, nil
+	}
+
+	if !HasValidExtension(arg, extensions) {
+		return nil, fmt.Errorf("file %s does not have a valid extension", arg)
+	}
+	if filter.Skips(arg, false) {
+		return nil, nil
+	}
+	return []string{arg}, nil
+}
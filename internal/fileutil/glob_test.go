@@ -0,0 +1,158 @@
+package fileutil
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{pattern: "*.ts", name: "config.ts", want: true},
+		{pattern: "*.ts", name: "src/config.ts", want: false},
+		{pattern: "src/*.ts", name: "src/config.ts", want: true},
+		{pattern: "src/**/*.ts", name: "src/config.ts", want: true},
+		{pattern: "src/**/*.ts", name: "src/a/b/config.ts", want: true},
+		{pattern: "**/*.tsx", name: "src/a/App.tsx", want: true},
+		{pattern: "**/*.tsx", name: "src/a/App.ts", want: false},
+		{pattern: "**", name: "src/a/App.ts", want: true},
+		{pattern: "src/**", name: "lib/App.ts", want: false},
+		{pattern: "src/?.ts", name: "src/a.ts", want: true},
+		{pattern: "src/[ab].ts", name: "src/c.ts", want: false},
+		{pattern: "*.ts", name: ".hidden.ts", want: false},
+		{pattern: "**/*.ts", name: ".cache/config.ts", want: false},
+		{pattern: ".cache/*.ts", name: ".cache/config.ts", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"_"+tt.name, func(t *testing.T) {
+			if got := MatchGlob(tt.pattern, tt.name); got != tt.want {
+				t.Errorf("MatchGlob(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolvePath(t *testing.T) {
+	root := t.TempDir()
+	for _, file := range []string{
+		"index.ts",
+		"readme.md",
+		"src/app.ts",
+		"src/App.tsx",
+		"src/nested/deep.ts",
+		"node_modules/dep/index.ts",
+		".cache/cached.ts",
+	} {
+		path := filepath.Join(root, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("export {};\n"), 0o644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	extensions := []string{".ts", ".tsx"}
+
+	tests := []struct {
+		name    string
+		arg     string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "single_file",
+			arg:  "index.ts",
+			want: []string{"index.ts"},
+		},
+		{
+			name:    "file_with_invalid_extension",
+			arg:     "readme.md",
+			wantErr: true,
+		},
+		{
+			name: "directory",
+			arg:  "src",
+			want: []string{"src/App.tsx", "src/app.ts", "src/nested/deep.ts"},
+		},
+		{
+			name: "dot_directory_is_searched",
+			arg:  ".",
+			want: []string{"index.ts", "src/App.tsx", "src/app.ts", "src/nested/deep.ts"},
+		},
+		{
+			name: "single_level_glob",
+			arg:  "src/*.ts",
+			want: []string{"src/app.ts"},
+		},
+		{
+			name: "double_star_glob",
+			arg:  "**/*.ts",
+			want: []string{"index.ts", "src/app.ts", "src/nested/deep.ts"},
+		},
+		{
+			name: "glob_matching_directories",
+			arg:  "s*",
+			want: []string{"src/App.tsx", "src/app.ts", "src/nested/deep.ts"},
+		},
+		{
+			name: "explicit_hidden_directory",
+			arg:  ".cache/*.ts",
+			want: []string{".cache/cached.ts"},
+		},
+		{
+			name:    "glob_without_matches",
+			arg:     "lib/**/*.ts",
+			wantErr: true,
+		},
+		{
+			name:    "missing_path",
+			arg:     "missing.ts",
+			wantErr: true,
+		},
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	if err := os.Chdir(root); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	defer os.Chdir(wd)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolvePath(tt.arg, extensions, true)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got files %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolvePath(%q) failed: %v", tt.arg, err)
+			}
+
+			for i := range got {
+				got[i] = filepath.ToSlash(got[i])
+			}
+			sort.Strings(got)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ResolvePath(%q) = %v, want %v", tt.arg, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("ResolvePath(%q) = %v, want %v", tt.arg, got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
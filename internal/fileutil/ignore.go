@@ -0,0 +1,374 @@
+package fileutil
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// IgnoreFileNames lists the ignore files read in every directory. Rules in
+// later files and deeper directories take precedence.
+var IgnoreFileNames = []string{".gitignore", ".treesorterignore"}
+
+// ignorePattern is a compiled .gitignore pattern
+type ignorePattern struct {
+	re      *regexp.Regexp
+	negate  bool // Pattern started with "!" and re-includes matches
+	dirOnly bool // Pattern ended with "/" and only matches directories
+}
+
+func (p ignorePattern) matches(rel string, isDir bool) bool {
+	return (isDir || !p.dirOnly) && p.re.MatchString(rel)
+}
+
+// parseIgnorePattern compiles one line of an ignore file, reporting false for
+// blank lines and comments
+func parseIgnorePattern(line string) (ignorePattern, bool, error) {
+	line = strings.TrimSuffix(line, "\r")
+
+	// Trailing spaces are ignored unless escaped with a backslash
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, "\\ ") {
+		line = line[:len(line)-1]
+	}
+
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignorePattern{}, false, nil
+	}
+
+	var p ignorePattern
+	switch {
+	case strings.HasPrefix(line, "!"):
+		p.negate = true
+		line = line[1:]
+	case strings.HasPrefix(line, "\\!"), strings.HasPrefix(line, "\\#"):
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		p.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return ignorePattern{}, false, nil
+	}
+
+	// A slash anywhere but at the end anchors the pattern to its directory
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+
+	re, err := globToRegexp(line, anchored)
+	if err != nil {
+		return ignorePattern{}, false, err
+	}
+	p.re = re
+	return p, true, nil
+}
+
+// globToRegexp translates a .gitignore glob into a regular expression matching
+// slash-separated relative paths. Unanchored patterns match at any depth.
+func globToRegexp(glob string, anchored bool) (*regexp.Regexp, error) {
+	var sb strings.Builder
+	sb.WriteString("^")
+	if !anchored {
+		sb.WriteString("(?:.*/)?")
+	}
+
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case c == '*' && strings.HasPrefix(glob[i:], "**") &&
+			(i == 0 || glob[i-1] == '/') && (i+2 == len(glob) || glob[i+2] == '/'):
+			// "**" as a whole segment matches any number of directories
+			if i+2 == len(glob) {
+				sb.WriteString(".*")
+				i++
+			} else {
+				sb.WriteString("(?:.*/)?")
+				i += 2
+			}
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				sb.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + class + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(glob):
+			sb.WriteString(regexp.QuoteMeta(glob[i+1 : i+2]))
+			i++
+		default:
+			sb.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+
+	sb.WriteString("$")
+	return regexp.Compile(sb.String())
+}
+
+// compilePatterns compiles command line patterns, which use .gitignore syntax
+func compilePatterns(patterns []string) ([]ignorePattern, error) {
+	compiled := make([]ignorePattern, 0, len(patterns))
+	for _, pattern := range patterns {
+		p, ok, err := parseIgnorePattern(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		if !ok {
+			return nil, fmt.Errorf("invalid pattern %q", pattern)
+		}
+		if p.negate {
+			return nil, fmt.Errorf("invalid pattern %q: negation is only supported in ignore files", pattern)
+		}
+		compiled = append(compiled, p)
+	}
+	return compiled, nil
+}
+
+// Filter decides which files and directories are skipped while resolving
+// paths. A nil Filter only skips dot-directories and node_modules.
+type Filter struct {
+	hidden   bool
+	noIgnore bool
+	include  []ignorePattern
+	exclude  []ignorePattern
+
+	mu         sync.Mutex
+	rules      map[string][]ignorePattern // Patterns of the ignore files in a directory
+	tops       map[string]string          // Directory whose ignore files apply first
+	dirIgnored map[string]bool
+}
+
+// FilterOptions configures a Filter
+type FilterOptions struct {
+	Hidden   bool     // Search dot-directories
+	NoIgnore bool     // Do not read .gitignore and .treesorterignore files
+	Include  []string // Only keep files matching one of these patterns
+	Exclude  []string // Skip files and directories matching one of these patterns
+}
+
+// NewFilter creates a filter. Include and exclude patterns use .gitignore
+// syntax and are matched against paths relative to the current directory.
+func NewFilter(opts FilterOptions) (*Filter, error) {
+	include, err := compilePatterns(opts.Include)
+	if err != nil {
+		return nil, fmt.Errorf("--include: %w", err)
+	}
+	exclude, err := compilePatterns(opts.Exclude)
+	if err != nil {
+		return nil, fmt.Errorf("--exclude: %w", err)
+	}
+
+	return &Filter{
+		hidden:     opts.Hidden,
+		noIgnore:   opts.NoIgnore,
+		include:    include,
+		exclude:    exclude,
+		rules:      make(map[string][]ignorePattern),
+		tops:       make(map[string]string),
+		dirIgnored: make(map[string]bool),
+	}, nil
+}
+
+// SkipsDir reports whether a directory walk skips the directory at path
+func (f *Filter) SkipsDir(path string) bool {
+	name := filepath.Base(path)
+	if f == nil {
+		return isSkippedDir(name)
+	}
+	if name == ".git" || name == "node_modules" || (!f.hidden && strings.HasPrefix(name, ".")) {
+		return true
+	}
+	return f.Skips(path, true)
+}
+
+// Skips reports whether the file or directory at path is excluded by the
+// --exclude patterns, an ignore file or, for files, the --include patterns
+func (f *Filter) Skips(path string, isDir bool) bool {
+	if f == nil {
+		return false
+	}
+
+	if rel, ok := relativeToWorkingDir(path); ok {
+		for _, p := range f.exclude {
+			if p.matches(rel, isDir) {
+				return true
+			}
+		}
+		if !isDir && len(f.include) > 0 && !anyMatches(f.include, rel) {
+			return true
+		}
+	}
+
+	return f.ignored(path, isDir)
+}
+
+func anyMatches(patterns []ignorePattern, rel string) bool {
+	for _, p := range patterns {
+		if p.matches(rel, false) {
+			return true
+		}
+	}
+	return false
+}
+
+func relativeToWorkingDir(path string) (string, bool) {
+	if !filepath.IsAbs(path) {
+		return filepath.ToSlash(filepath.Clean(path)), true
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", false
+	}
+	rel, err := filepath.Rel(wd, path)
+	if err != nil {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// ignored evaluates the ignore files from the repository root down to the
+// path's directory. Like git, nothing below an ignored directory can be
+// re-included.
+func (f *Filter) ignored(path string, isDir bool) bool {
+	if f.noIgnore {
+		return false
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.ignoredLocked(abs, isDir)
+}
+
+func (f *Filter) ignoredLocked(abs string, isDir bool) bool {
+	parent := filepath.Dir(abs)
+	if parent == abs {
+		return false
+	}
+	top := f.topDirLocked(parent)
+
+	if parent != top && f.dirIgnoredLocked(parent) {
+		return true
+	}
+
+	ignored := false
+	for _, dir := range dirsBetween(top, parent) {
+		rel, err := filepath.Rel(dir, abs)
+		if err != nil {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+		for _, p := range f.rulesLocked(dir) {
+			if p.matches(rel, isDir) {
+				ignored = !p.negate
+			}
+		}
+	}
+	return ignored
+}
+
+func (f *Filter) dirIgnoredLocked(dir string) bool {
+	if ignored, ok := f.dirIgnored[dir]; ok {
+		return ignored
+	}
+	ignored := f.ignoredLocked(dir, true)
+	f.dirIgnored[dir] = ignored
+	return ignored
+}
+
+// topDirLocked returns the repository root containing dir, or the file system
+// root outside of a repository
+func (f *Filter) topDirLocked(dir string) string {
+	if top, ok := f.tops[dir]; ok {
+		return top
+	}
+
+	top := dir
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		if parent := filepath.Dir(dir); parent != dir {
+			top = f.topDirLocked(parent)
+		}
+	}
+	f.tops[dir] = top
+	return top
+}
+
+// rulesLocked returns the patterns of the ignore files in dir
+func (f *Filter) rulesLocked(dir string) []ignorePattern {
+	if rules, ok := f.rules[dir]; ok {
+		return rules
+	}
+
+	var rules []ignorePattern
+	for _, name := range IgnoreFileNames {
+		patterns, err := readIgnoreFile(filepath.Join(dir, name))
+		if err != nil {
+			// Unreadable ignore files are treated like missing ones
+			continue
+		}
+		rules = append(rules, patterns...)
+	}
+	f.rules[dir] = rules
+	return rules
+}
+
+// readIgnoreFile parses an ignore file, skipping lines that are not valid patterns
+func readIgnoreFile(path string) ([]ignorePattern, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var patterns []ignorePattern
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		p, ok, err := parseIgnorePattern(scanner.Text())
+		if err != nil || !ok {
+			continue
+		}
+		patterns = append(patterns, p)
+	}
+	return patterns, scanner.Err()
+}
+
+// dirsBetween lists the directories from top down to dir, both included
+func dirsBetween(top, dir string) []string {
+	var dirs []string
+	for {
+		dirs = append(dirs, dir)
+		if dir == top {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	for i, j := 0, len(dirs)-1; i < j; i, j = i+1, j-1 {
+		dirs[i], dirs[j] = dirs[j], dirs[i]
+	}
+	return dirs
+}
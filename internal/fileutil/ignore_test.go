@@ -0,0 +1,214 @@
+package fileutil
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestIgnorePatternMatches(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		isDir   bool
+		want    bool
+	}{
+		{pattern: "dist", path: "dist", isDir: true, want: true},
+		{pattern: "dist", path: "packages/web/dist", isDir: true, want: true},
+		{pattern: "/dist", path: "packages/web/dist", isDir: true, want: false},
+		{pattern: "/dist", path: "dist", isDir: true, want: true},
+		{pattern: "build/", path: "build", isDir: true, want: true},
+		{pattern: "build/", path: "build", isDir: false, want: false},
+		{pattern: "*.generated.ts", path: "src/api.generated.ts", want: true},
+		{pattern: "*.generated.ts", path: ".hidden.generated.ts", want: true},
+		{pattern: "src/*.ts", path: "src/a.ts", want: true},
+		{pattern: "src/*.ts", path: "src/nested/a.ts", want: false},
+		{pattern: "src/*.ts", path: "lib/src/a.ts", want: false},
+		{pattern: "**/__generated__", path: "a/b/__generated__", isDir: true, want: true},
+		{pattern: "src/**/mocks", path: "src/mocks", isDir: true, want: true},
+		{pattern: "src/**/mocks", path: "src/a/b/mocks", isDir: true, want: true},
+		{pattern: "out/**", path: "out/a/b.ts", want: true},
+		{pattern: "out/**", path: "out", isDir: true, want: false},
+		{pattern: "file?.ts", path: "file1.ts", want: true},
+		{pattern: "file[0-9].ts", path: "filea.ts", want: false},
+		{pattern: "file[!0-9].ts", path: "filea.ts", want: true},
+		{pattern: `\#hash.ts`, path: "#hash.ts", want: true},
+		{pattern: "trailing.ts   ", path: "trailing.ts", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"_"+tt.path, func(t *testing.T) {
+			p, ok, err := parseIgnorePattern(tt.pattern)
+			if err != nil || !ok {
+				t.Fatalf("parseIgnorePattern(%q) = %v, %v", tt.pattern, ok, err)
+			}
+			if got := p.matches(tt.path, tt.isDir); got != tt.want {
+				t.Errorf("pattern %q matches(%q, dir=%v) = %v, want %v", tt.pattern, tt.path, tt.isDir, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseIgnorePatternSkipsCommentsAndBlanks(t *testing.T) {
+	for _, line := range []string{"", "   ", "# comment", "/"} {
+		if _, ok, err := parseIgnorePattern(line); ok || err != nil {
+			t.Errorf("parseIgnorePattern(%q) = %v, %v; want skipped", line, ok, err)
+		}
+	}
+
+	p, ok, err := parseIgnorePattern("!keep.ts")
+	if err != nil || !ok || !p.negate {
+		t.Errorf("expected negated pattern, got %+v, %v, %v", p, ok, err)
+	}
+}
+
+func TestFilterResolvePath(t *testing.T) {
+	root := t.TempDir()
+	for file, content := range map[string]string{
+		".git/HEAD":                         "ref: refs/heads/main\n",
+		".gitignore":                        "dist/\n*.generated.ts\n!keep.generated.ts\n",
+		".treesorterignore":                 "# generated by codegen\n__generated__\n",
+		"src/app.ts":                        "",
+		"src/api.generated.ts":              "",
+		"src/keep.generated.ts":             "",
+		"src/__generated__/types.ts":        "",
+		"src/legacy/.gitignore":             "/old.ts\n",
+		"src/legacy/old.ts":                 "",
+		"src/legacy/new.ts":                 "",
+		"src/legacy/nested/old.ts":          "",
+		"dist/app.ts":                       "",
+		"dist/.gitignore":                   "!app.ts\n",
+		"build/out.ts":                      "",
+		".storybook/main.ts":                "",
+		"node_modules/dep/index.ts":         "",
+		"test/app.test.ts":                  "",
+		"test/fixtures/unsorted.fixture.ts": "",
+	} {
+		path := filepath.Join(root, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		opts FilterOptions
+		arg  string
+		want []string
+	}{
+		{
+			name: "ignore_files",
+			arg:  ".",
+			want: []string{
+				"build/out.ts",
+				"src/app.ts",
+				"src/keep.generated.ts",
+				"src/legacy/nested/old.ts",
+				"src/legacy/new.ts",
+				"test/app.test.ts",
+				"test/fixtures/unsorted.fixture.ts",
+			},
+		},
+		{
+			name: "no_ignore",
+			opts: FilterOptions{NoIgnore: true},
+			arg:  "src",
+			want: []string{
+				"src/__generated__/types.ts",
+				"src/api.generated.ts",
+				"src/app.ts",
+				"src/keep.generated.ts",
+				"src/legacy/nested/old.ts",
+				"src/legacy/new.ts",
+				"src/legacy/old.ts",
+			},
+		},
+		{
+			name: "exclude",
+			opts: FilterOptions{Exclude: []string{"build", "test/fixtures/"}},
+			arg:  ".",
+			want: []string{
+				"src/app.ts",
+				"src/keep.generated.ts",
+				"src/legacy/nested/old.ts",
+				"src/legacy/new.ts",
+				"test/app.test.ts",
+			},
+		},
+		{
+			name: "include",
+			opts: FilterOptions{Include: []string{"src/**/*.ts"}, Exclude: []string{"legacy"}},
+			arg:  ".",
+			want: []string{"src/app.ts", "src/keep.generated.ts"},
+		},
+		{
+			name: "hidden",
+			opts: FilterOptions{Hidden: true, Include: []string{"main.ts"}},
+			arg:  ".",
+			want: []string{".storybook/main.ts"},
+		},
+		{
+			name: "explicit_ignored_file",
+			arg:  "src/api.generated.ts",
+			want: nil,
+		},
+		{
+			name: "explicit_file_in_ignored_directory",
+			arg:  "dist/app.ts",
+			want: nil,
+		},
+		{
+			name: "glob",
+			arg:  "src/*.ts",
+			want: []string{"src/app.ts", "src/keep.generated.ts"},
+		},
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	if err := os.Chdir(root); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	defer os.Chdir(wd)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := NewFilter(tt.opts)
+			if err != nil {
+				t.Fatalf("NewFilter failed: %v", err)
+			}
+
+			got, err := filter.ResolvePath(tt.arg, []string{".ts"}, true)
+			if err != nil {
+				t.Fatalf("ResolvePath(%q) failed: %v", tt.arg, err)
+			}
+			for i := range got {
+				got[i] = filepath.ToSlash(got[i])
+			}
+			sort.Strings(got)
+
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("ResolvePath(%q) =\n%s\nwant\n%s", tt.arg, strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestNewFilterRejectsInvalidPatterns(t *testing.T) {
+	for _, opts := range []FilterOptions{
+		{Include: []string{"!src"}},
+		{Exclude: []string{"# not a pattern"}},
+		{Exclude: []string{""}},
+	} {
+		if _, err := NewFilter(opts); err == nil {
+			t.Errorf("NewFilter(%+v) should fail", opts)
+		}
+	}
+}
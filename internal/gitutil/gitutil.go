@@ -0,0 +1,301 @@
+package gitutil
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Repo runs git commands in the repository containing the working directory
+type Repo struct {
+	Root string // Absolute path of the working tree root
+	dir  string // Absolute working directory, which pathspecs are relative to
+}
+
+// Open finds the repository containing dir
+func Open(dir string) (*Repo, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := run(abs, nil, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("not a git repository: %w", err)
+	}
+
+	root := strings.TrimSpace(string(out))
+	// rev-parse resolves symlinks; do the same for dir so relative paths line up
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+	return &Repo{Root: filepath.Clean(root), dir: abs}, nil
+}
+
+// ChangedSince lists the files added, copied, modified or renamed since the
+// merge-base of rev and HEAD, including uncommitted and untracked changes.
+// Paths are relative to the working directory and limited to pathspecs when
+// given.
+func (r *Repo) ChangedSince(rev string, pathspecs []string) ([]string, error) {
+	base, err := r.mergeBase(rev)
+	if err != nil {
+		return nil, err
+	}
+
+	changed, err := r.nameList(append([]string{"diff", "--name-only", "-z", "--no-renames", "--diff-filter=ACMR", base, "--"}, pathspecs...))
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := r.nameList(append([]string{"ls-files", "-z", "--others", "--exclude-standard", "--full-name", "--"}, pathspecs...))
+	if err != nil {
+		return nil, err
+	}
+
+	return r.relativePaths(append(changed, untracked...)), nil
+}
+
+// Hunk is a block of added or modified lines in the new version of a file. A
+// Count of zero marks lines deleted after line Start.
+type Hunk struct {
+	Start int // First line, 1-based
+	Count int // Number of lines
+}
+
+// ChangedLines lists the changed lines of every file ChangedSince reports.
+// Untracked files are changed as a whole.
+func (r *Repo) ChangedLines(rev string, pathspecs []string) (map[string][]Hunk, error) {
+	base, err := r.mergeBase(rev)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := r.git(nil, append([]string{"diff", "-U0", "--no-color", "--no-ext-diff", "--no-renames", "--diff-filter=ACMR", base, "--"}, pathspecs...)...)
+	if err != nil {
+		return nil, err
+	}
+	byName, err := parseHunks(out)
+	if err != nil {
+		return nil, err
+	}
+
+	hunks := make(map[string][]Hunk, len(byName))
+	for name, fileHunks := range byName {
+		hunks[r.relativePaths([]string{name})[0]] = fileHunks
+	}
+
+	untracked, err := r.nameList(append([]string{"ls-files", "-z", "--others", "--exclude-standard", "--full-name", "--"}, pathspecs...))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range r.relativePaths(untracked) {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		lines := bytes.Count(content, []byte("\n"))
+		if len(content) > 0 && content[len(content)-1] != '\n' {
+			lines++
+		}
+		hunks[path] = []Hunk{{Start: 1, Count: lines}}
+	}
+
+	return hunks, nil
+}
+
+// parseHunks reads the new-file line ranges of a zero-context unified diff,
+// keyed by path relative to the repository root
+func parseHunks(diff []byte) (map[string][]Hunk, error) {
+	hunks := make(map[string][]Hunk)
+	var name string
+
+	scanner := bufio.NewScanner(bytes.NewReader(diff))
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			name = strings.TrimPrefix(line, "+++ ")
+			if strings.HasPrefix(name, `"`) {
+				unquoted, err := strconv.Unquote(name)
+				if err != nil {
+					return nil, fmt.Errorf("parsing diff file name %s: %w", name, err)
+				}
+				name = unquoted
+			}
+			if name == "/dev/null" {
+				name = ""
+				continue
+			}
+			name = strings.TrimPrefix(name, "b/")
+			// Files without changed lines, such as new empty files, still count
+			if _, ok := hunks[name]; !ok {
+				hunks[name] = nil
+			}
+		case strings.HasPrefix(line, "@@ ") && name != "":
+			// Format: "@@ -<start>[,<count>] +<start>[,<count>] @@"
+			fields := strings.Fields(line)
+			if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+				return nil, fmt.Errorf("malformed hunk header %q", line)
+			}
+			startText, countText, found := strings.Cut(fields[2][1:], ",")
+			hunk := Hunk{Count: 1}
+			var err error
+			if hunk.Start, err = strconv.Atoi(startText); err != nil {
+				return nil, fmt.Errorf("malformed hunk header %q", line)
+			}
+			if found {
+				if hunk.Count, err = strconv.Atoi(countText); err != nil {
+					return nil, fmt.Errorf("malformed hunk header %q", line)
+				}
+			}
+			hunks[name] = append(hunks[name], hunk)
+		}
+	}
+	return hunks, scanner.Err()
+}
+
+// Staged lists the files added, copied, modified or renamed in the index,
+// relative to the working directory and limited to pathspecs when given
+func (r *Repo) Staged(pathspecs []string) ([]string, error) {
+	names, err := r.nameList(append([]string{"diff", "--cached", "--name-only", "-z", "--no-renames", "--diff-filter=ACMR", "--"}, pathspecs...))
+	if err != nil {
+		return nil, err
+	}
+	return r.relativePaths(names), nil
+}
+
+// StagedContent returns the content of path as staged in the index
+func (r *Repo) StagedContent(path string) ([]byte, error) {
+	name, err := r.repoPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return r.git(nil, "cat-file", "blob", ":"+name)
+}
+
+// Stage replaces the staged content of path, keeping its file mode. The
+// working tree is not touched.
+func (r *Repo) Stage(path string, content []byte) error {
+	name, err := r.repoPath(path)
+	if err != nil {
+		return err
+	}
+
+	out, err := run(r.Root, nil, "--literal-pathspecs", "ls-files", "-z", "--stage", "--", name)
+	if err != nil {
+		return err
+	}
+	// Format: "<mode> <object> <stage>\t<path>"
+	fields := strings.Fields(string(bytes.TrimRight(out, "\x00")))
+	if len(fields) < 3 || fields[2] != "0" {
+		return fmt.Errorf("%s is not staged or has unresolved conflicts", path)
+	}
+	mode := fields[0]
+
+	// The content came from the index, so clean filters were already applied
+	hash, err := run(r.Root, content, "hash-object", "-w", "--stdin", "--no-filters")
+	if err != nil {
+		return err
+	}
+
+	_, err = run(r.Root, nil, "update-index", "--cacheinfo", mode+","+strings.TrimSpace(string(hash))+","+name)
+	return err
+}
+
+// mergeBase resolves the best common ancestor of rev and HEAD
+func (r *Repo) mergeBase(rev string) (string, error) {
+	out, err := r.git(nil, "merge-base", rev, "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("finding merge-base with %s: %w", rev, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// nameList runs a git command printing NUL-terminated paths
+func (r *Repo) nameList(args []string) ([]string, error) {
+	out, err := r.git(nil, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, name := range strings.Split(string(out), "\x00") {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// relativePaths converts paths relative to the repository root into paths
+// relative to the working directory, dropping duplicates
+func (r *Repo) relativePaths(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	paths := make([]string, 0, len(names))
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		path := filepath.Join(r.Root, filepath.FromSlash(name))
+		if rel, err := filepath.Rel(r.dir, path); err == nil {
+			path = rel
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// repoPath returns path relative to the repository root with forward slashes
+func (r *Repo) repoPath(path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(r.dir, path)
+	}
+	rel, err := filepath.Rel(r.Root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside the repository %s", path, r.Root)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+func (r *Repo) git(stdin []byte, args ...string) ([]byte, error) {
+	return run(r.dir, stdin, args...)
+}
+
+// run executes git in dir, including its stderr in the returned error
+func run(dir string, stdin []byte, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		command := args[len(args)-1]
+		for _, arg := range args {
+			if !strings.HasPrefix(arg, "-") {
+				command = arg
+				break
+			}
+		}
+
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return nil, fmt.Errorf("git %s: %s", command, msg)
+			}
+		}
+		return nil, fmt.Errorf("git %s: %w", command, err)
+	}
+	return out, nil
+}
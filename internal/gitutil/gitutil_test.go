@@ -0,0 +1,265 @@
+package gitutil
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// newTestRepo creates a repository with one commit on main and changes the
+// working directory into it for the duration of the test
+func newTestRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root := t.TempDir()
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	if err := os.Chdir(root); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	gitCmd(t, "init", "-q", "-b", "main")
+	writeFile(t, "src/a.ts", "a\n")
+	writeFile(t, "src/b.ts", "b\n")
+	writeFile(t, "lib/c.ts", "c\n")
+	gitCmd(t, "add", ".")
+	gitCmd(t, "commit", "-q", "-m", "initial")
+	return root
+}
+
+func gitCmd(t *testing.T, args ...string) {
+	t.Helper()
+	if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, out)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+}
+
+func sorted(paths []string) []string {
+	for i := range paths {
+		paths[i] = filepath.ToSlash(paths[i])
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func TestChangedSince(t *testing.T) {
+	newTestRepo(t)
+
+	gitCmd(t, "checkout", "-q", "-b", "feature")
+	writeFile(t, "src/a.ts", "a changed\n")
+	gitCmd(t, "commit", "-q", "-am", "change a")
+	writeFile(t, "lib/c.ts", "c changed\n")    // Uncommitted
+	writeFile(t, "lib/new.ts", "new\n")        // Untracked
+	writeFile(t, "dist/out.ts", "generated\n") // Ignored
+	writeFile(t, ".gitignore", "dist/\n")
+	gitCmd(t, "rm", "-q", "src/b.ts")
+
+	repo, err := Open(".")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	got, err := repo.ChangedSince("main", nil)
+	if err != nil {
+		t.Fatalf("ChangedSince failed: %v", err)
+	}
+	want := []string{".gitignore", "lib/c.ts", "lib/new.ts", "src/a.ts"}
+	if !reflect.DeepEqual(sorted(got), want) {
+		t.Errorf("ChangedSince = %v, want %v", got, want)
+	}
+
+	got, err = repo.ChangedSince("main", []string{"lib"})
+	if err != nil {
+		t.Fatalf("ChangedSince with pathspec failed: %v", err)
+	}
+	want = []string{"lib/c.ts", "lib/new.ts"}
+	if !reflect.DeepEqual(sorted(got), want) {
+		t.Errorf("ChangedSince(lib) = %v, want %v", got, want)
+	}
+
+	if _, err := repo.ChangedSince("no-such-branch", nil); err == nil {
+		t.Error("expected an error for an unknown revision")
+	}
+}
+
+func TestChangedSinceFromSubdirectory(t *testing.T) {
+	root := newTestRepo(t)
+	writeFile(t, "src/a.ts", "a changed\n")
+	writeFile(t, "lib/c.ts", "c changed\n")
+
+	if err := os.Chdir(filepath.Join(root, "src")); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	repo, err := Open(".")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	got, err := repo.ChangedSince("HEAD", nil)
+	if err != nil {
+		t.Fatalf("ChangedSince failed: %v", err)
+	}
+	want := []string{"../lib/c.ts", "a.ts"}
+	if !reflect.DeepEqual(sorted(got), want) {
+		t.Errorf("ChangedSince = %v, want %v", got, want)
+	}
+
+	content, err := repo.StagedContent("a.ts")
+	if err != nil || string(content) != "a\n" {
+		t.Errorf("StagedContent(a.ts) = %q, %v", content, err)
+	}
+}
+
+func TestStaged(t *testing.T) {
+	newTestRepo(t)
+
+	writeFile(t, "src/a.ts", "a staged\n")
+	gitCmd(t, "add", "src/a.ts")
+	writeFile(t, "src/a.ts", "a staged\nunstaged\n")
+	writeFile(t, "lib/c.ts", "c unstaged\n")
+
+	repo, err := Open(".")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	got, err := repo.Staged(nil)
+	if err != nil {
+		t.Fatalf("Staged failed: %v", err)
+	}
+	if want := []string{"src/a.ts"}; !reflect.DeepEqual(sorted(got), want) {
+		t.Errorf("Staged = %v, want %v", got, want)
+	}
+
+	content, err := repo.StagedContent("src/a.ts")
+	if err != nil {
+		t.Fatalf("StagedContent failed: %v", err)
+	}
+	if string(content) != "a staged\n" {
+		t.Errorf("StagedContent = %q, want %q", content, "a staged\n")
+	}
+
+	if err := repo.Stage("src/a.ts", []byte("a restaged\n")); err != nil {
+		t.Fatalf("Stage failed: %v", err)
+	}
+	content, err = repo.StagedContent("src/a.ts")
+	if err != nil || string(content) != "a restaged\n" {
+		t.Errorf("StagedContent after Stage = %q, %v", content, err)
+	}
+
+	// The working tree keeps its unstaged changes
+	worktree, err := os.ReadFile("src/a.ts")
+	if err != nil || string(worktree) != "a staged\nunstaged\n" {
+		t.Errorf("working tree = %q, %v", worktree, err)
+	}
+
+	if err := repo.Stage("src/missing.ts", []byte("x\n")); err == nil {
+		t.Error("expected an error staging a file that is not in the index")
+	}
+}
+
+func TestOpenOutsideRepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("GIT_CEILING_DIRECTORIES", os.TempDir())
+
+	if _, err := Open(t.TempDir()); err == nil {
+		t.Error("expected an error outside of a repository")
+	}
+}
+
+func TestParseHunks(t *testing.T) {
+	diff := `diff --git a/src/a.ts b/src/a.ts
+index 1111111..2222222 100644
+--- a/src/a.ts
++++ b/src/a.ts
+@@ -3 +3 @@ const a = {
+-  b: 1,
++  b: 2,
+@@ -10,2 +9,0 @@ const c = [
+-  "x",
+-  "y",
+@@ -20,0 +19,3 @@
++  d: 1,
++  e: 2,
++  f: 3,
+diff --git "a/src/sp\303\251cial.ts" "b/src/sp\303\251cial.ts"
+new file mode 100644
+--- /dev/null
++++ "b/src/sp\303\251cial.ts"
+@@ -0,0 +1,2 @@
++const x = 1;
++const y = 2;
+diff --git a/src/empty.ts b/src/empty.ts
+new file mode 100644
+--- /dev/null
++++ b/src/empty.ts
+`
+
+	got, err := parseHunks([]byte(diff))
+	if err != nil {
+		t.Fatalf("parseHunks failed: %v", err)
+	}
+
+	want := map[string][]Hunk{
+		"src/a.ts":       {{Start: 3, Count: 1}, {Start: 9, Count: 0}, {Start: 19, Count: 3}},
+		"src/spécial.ts": {{Start: 1, Count: 2}},
+		"src/empty.ts":   nil,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseHunks = %+v, want %+v", got, want)
+	}
+}
+
+func TestChangedLines(t *testing.T) {
+	newTestRepo(t)
+
+	gitCmd(t, "checkout", "-q", "-b", "feature")
+	writeFile(t, "src/a.ts", "a\nadded\n")
+	gitCmd(t, "commit", "-q", "-am", "change a")
+	writeFile(t, "lib/new.ts", "one\ntwo\n")
+
+	repo, err := Open(".")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	got, err := repo.ChangedLines("main", nil)
+	if err != nil {
+		t.Fatalf("ChangedLines failed: %v", err)
+	}
+	want := map[string][]Hunk{
+		filepath.FromSlash("src/a.ts"):   {{Start: 2, Count: 1}},
+		filepath.FromSlash("lib/new.ts"): {{Start: 1, Count: 2}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ChangedLines = %+v, want %+v", got, want)
+	}
+}
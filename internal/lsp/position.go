@@ -0,0 +1,61 @@
+package lsp
+
+import (
+	"unicode/utf8"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/textutil"
+)
+
+// toPosition converts a byte offset into a position whose character counts
+// UTF-16 code units, as LSP requires
+func toPosition(text []byte, offset int) Position {
+	offset = min(max(offset, 0), len(text))
+
+	var pos Position
+	for i := 0; i < offset; {
+		r, size := utf8.DecodeRune(text[i:])
+		if i+size > offset {
+			// Offsets inside a character stay on the character
+			break
+		}
+		i += size
+
+		if r == '\n' {
+			pos.Line++
+			pos.Character = 0
+			continue
+		}
+		pos.Character += textutil.RuneUTF16Len(r)
+	}
+	return pos
+}
+
+// toOffset converts a position into a byte offset, clamping positions past
+// the end of a line to the line's end and past the end of the text to its end
+func toOffset(text []byte, pos Position) int {
+	i := 0
+	for line := 0; line < pos.Line; line++ {
+		for i < len(text) && text[i] != '\n' {
+			i++
+		}
+		if i == len(text) {
+			return len(text)
+		}
+		i++
+	}
+
+	for units := 0; i < len(text) && text[i] != '\n'; {
+		r, size := utf8.DecodeRune(text[i:])
+		units += textutil.RuneUTF16Len(r)
+		if units > pos.Character {
+			break
+		}
+		i += size
+	}
+	return i
+}
+
+// toRange converts a byte range into an LSP range
+func toRange(text []byte, start, end int) Range {
+	return Range{Start: toPosition(text, start), End: toPosition(text, end)}
+}
@@ -0,0 +1,50 @@
+package lsp
+
+import "testing"
+
+func TestPositionConversion(t *testing.T) {
+	text := []byte("ab\ncé😀d\n\nlast")
+
+	tests := []struct {
+		offset int
+		pos    Position
+	}{
+		{offset: 0, pos: Position{Line: 0, Character: 0}},
+		{offset: 2, pos: Position{Line: 0, Character: 2}},
+		{offset: 3, pos: Position{Line: 1, Character: 0}},
+		{offset: 4, pos: Position{Line: 1, Character: 1}},
+		{offset: 6, pos: Position{Line: 1, Character: 2}},  // After the two-byte é
+		{offset: 10, pos: Position{Line: 1, Character: 4}}, // After the surrogate pair
+		{offset: 12, pos: Position{Line: 2, Character: 0}},
+		{offset: 13, pos: Position{Line: 3, Character: 0}},
+		{offset: 17, pos: Position{Line: 3, Character: 4}},
+	}
+
+	for _, tt := range tests {
+		if got := toPosition(text, tt.offset); got != tt.pos {
+			t.Errorf("toPosition(%d) = %+v, want %+v", tt.offset, got, tt.pos)
+		}
+		if got := toOffset(text, tt.pos); got != tt.offset {
+			t.Errorf("toOffset(%+v) = %d, want %d", tt.pos, got, tt.offset)
+		}
+	}
+}
+
+func TestToOffsetClamps(t *testing.T) {
+	text := []byte("ab\ncd")
+
+	tests := []struct {
+		pos  Position
+		want int
+	}{
+		{pos: Position{Line: 0, Character: 10}, want: 2},
+		{pos: Position{Line: 5, Character: 0}, want: 5},
+		{pos: Position{Line: 1, Character: 99}, want: 5},
+	}
+
+	for _, tt := range tests {
+		if got := toOffset(text, tt.pos); got != tt.want {
+			t.Errorf("toOffset(%+v) = %d, want %d", tt.pos, got, tt.want)
+		}
+	}
+}
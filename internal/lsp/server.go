@@ -0,0 +1,444 @@
+package lsp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/config"
+	"github.com/evanrichards/tree-sorter-ts/internal/processor"
+	"github.com/evanrichards/tree-sorter-ts/internal/report"
+)
+
+// diagnosticSource is the source shown with every diagnostic
+const diagnosticSource = "tree-sorter-ts"
+
+// ErrExitWithoutShutdown is returned by Run when the client sent exit without
+// a shutdown request first; the process should exit with status 1
+var ErrExitWithoutShutdown = errors.New("exit notification received before shutdown")
+
+// Options configures a Server
+type Options struct {
+	ConfigPath string // Project configuration file for every document; empty looks up the nearest one
+	Version    string // Reported to the client as the server version
+}
+
+// document is an open text document and the analysis of its current text
+type document struct {
+	uri     string
+	path    string
+	version int
+	text    []byte
+	result  processor.ProcessResult
+	ignored bool
+}
+
+// Server is a Language Server Protocol server reporting keep-sorted
+// structures as diagnostics and offering their sorted text as code actions
+// and formatting. It handles one message at a time.
+type Server struct {
+	conn     *conn
+	opts     Options
+	resolver *config.Resolver
+	docs     map[string]*document
+
+	initialized bool
+	shutdown    bool
+}
+
+// NewServer creates a server speaking LSP over r and w
+func NewServer(r io.Reader, w io.Writer, opts Options) (*Server, error) {
+	resolver, err := config.NewResolver(opts.ConfigPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Server{
+		conn:     newConn(r, w),
+		opts:     opts,
+		resolver: resolver,
+		docs:     make(map[string]*document),
+	}, nil
+}
+
+// Run serves messages until the client sends exit or closes the stream
+func (s *Server) Run() error {
+	for {
+		msg, err := s.conn.read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			var rpcErr *responseError
+			if errors.As(err, &rpcErr) {
+				// The frame was fine but its body was not JSON; keep serving
+				if err := s.conn.reply(json.RawMessage("null"), nil, rpcErr); err != nil {
+					return err
+				}
+				continue
+			}
+			return err
+		}
+
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return ErrExitWithoutShutdown
+			}
+			return nil
+		}
+
+		if err := s.handle(msg); err != nil {
+			return err
+		}
+	}
+}
+
+// handle dispatches a message, replying to requests. Only failures to write
+// to the client are returned.
+func (s *Server) handle(msg *message) error {
+	isRequest := len(msg.ID) > 0
+	if msg.Method == "" {
+		// Responses to requests the server never sends
+		return nil
+	}
+
+	if !s.initialized && msg.Method != "initialize" {
+		if isRequest {
+			return s.conn.reply(msg.ID, nil, &responseError{Code: codeServerNotInitialized, Message: "server not initialized"})
+		}
+		return nil
+	}
+	if s.shutdown && isRequest {
+		return s.conn.reply(msg.ID, nil, &responseError{Code: codeInvalidRequest, Message: "server is shutting down"})
+	}
+
+	result, rpcErr := s.dispatch(msg)
+	if !isRequest {
+		if rpcErr != nil {
+			return s.logError(fmt.Sprintf("%s: %s", msg.Method, rpcErr.Message))
+		}
+		return nil
+	}
+	return s.conn.reply(msg.ID, result, rpcErr)
+}
+
+func (s *Server) dispatch(msg *message) (any, *responseError) {
+	switch msg.Method {
+	case "initialize":
+		return s.initialize()
+	case "initialized":
+		return nil, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "textDocument/didOpen":
+		var params DidOpenTextDocumentParams
+		if rpcErr := decodeParams(msg, &params); rpcErr != nil {
+			return nil, rpcErr
+		}
+		return nil, s.didOpen(params)
+	case "textDocument/didChange":
+		var params DidChangeTextDocumentParams
+		if rpcErr := decodeParams(msg, &params); rpcErr != nil {
+			return nil, rpcErr
+		}
+		return nil, s.didChange(params)
+	case "textDocument/didClose":
+		var params DidCloseTextDocumentParams
+		if rpcErr := decodeParams(msg, &params); rpcErr != nil {
+			return nil, rpcErr
+		}
+		return nil, s.didClose(params)
+	case "textDocument/codeAction":
+		var params CodeActionParams
+		if rpcErr := decodeParams(msg, &params); rpcErr != nil {
+			return nil, rpcErr
+		}
+		return s.codeActions(params)
+	case "textDocument/formatting":
+		var params DocumentFormattingParams
+		if rpcErr := decodeParams(msg, &params); rpcErr != nil {
+			return nil, rpcErr
+		}
+		return s.formatting(params)
+	}
+
+	if strings.HasPrefix(msg.Method, "$/") {
+		// Optional protocol notifications such as $/cancelRequest
+		return nil, nil
+	}
+	return nil, &responseError{Code: codeMethodNotFound, Message: "method not found: " + msg.Method}
+}
+
+func decodeParams(msg *message, params any) *responseError {
+	if err := json.Unmarshal(msg.Params, params); err != nil {
+		return &responseError{Code: codeInvalidParams, Message: fmt.Sprintf("invalid params: %v", err)}
+	}
+	return nil
+}
+
+func (s *Server) initialize() (any, *responseError) {
+	s.initialized = true
+	return InitializeResult{
+		Capabilities: ServerCapabilities{
+			PositionEncoding: "utf-16",
+			TextDocumentSync: TextDocumentSync{OpenClose: true, Change: SyncIncremental},
+			CodeActionProvider: CodeActionProvider{
+				CodeActionKinds: []string{CodeActionQuickFix, CodeActionSortAll},
+			},
+			DocumentFormattingProvider: true,
+		},
+		ServerInfo: ServerInfo{Name: "tree-sorter-ts", Version: s.opts.Version},
+	}, nil
+}
+
+func (s *Server) didOpen(params DidOpenTextDocumentParams) *responseError {
+	item := params.TextDocument
+	path, err := uriToPath(item.URI)
+	if err != nil {
+		return &responseError{Code: codeInvalidParams, Message: err.Error()}
+	}
+
+	doc := &document{uri: item.URI, path: path, version: item.Version, text: []byte(item.Text)}
+	s.docs[item.URI] = doc
+	return s.analyze(doc)
+}
+
+func (s *Server) didChange(params DidChangeTextDocumentParams) *responseError {
+	doc, rpcErr := s.document(params.TextDocument.URI)
+	if rpcErr != nil {
+		return rpcErr
+	}
+
+	for _, change := range params.ContentChanges {
+		if change.Range == nil {
+			doc.text = []byte(change.Text)
+			continue
+		}
+		start := toOffset(doc.text, change.Range.Start)
+		end := max(toOffset(doc.text, change.Range.End), start)
+
+		text := make([]byte, 0, len(doc.text)-(end-start)+len(change.Text))
+		text = append(text, doc.text[:start]...)
+		text = append(text, change.Text...)
+		text = append(text, doc.text[end:]...)
+		doc.text = text
+	}
+	doc.version = params.TextDocument.Version
+	return s.analyze(doc)
+}
+
+func (s *Server) didClose(params DidCloseTextDocumentParams) *responseError {
+	delete(s.docs, params.TextDocument.URI)
+	// Clear the diagnostics of the closed document
+	if err := s.conn.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+		URI:         params.TextDocument.URI,
+		Diagnostics: []Diagnostic{},
+	}); err != nil {
+		return &responseError{Code: codeInternalError, Message: err.Error()}
+	}
+	return nil
+}
+
+func (s *Server) document(uri string) (*document, *responseError) {
+	doc, ok := s.docs[uri]
+	if !ok {
+		return nil, &responseError{Code: codeInvalidParams, Message: "document is not open: " + uri}
+	}
+	return doc, nil
+}
+
+// analyze processes the document's text and publishes its diagnostics
+func (s *Server) analyze(doc *document) *responseError {
+	doc.result = processor.ProcessResult{}
+	doc.ignored = false
+
+	settings, err := s.resolver.SettingsFor(doc.path)
+	if err != nil {
+		if logErr := s.logError(err.Error()); logErr != nil {
+			return &responseError{Code: codeInternalError, Message: logErr.Error()}
+		}
+	}
+	doc.ignored = settings.Ignored
+
+	if !doc.ignored {
+		// Structures that parsed cleanly are reported while the user is still
+		// typing elsewhere in the file
+		result, err := processor.ProcessContentAST(doc.path, doc.text, processor.Config{
+			AllowParseErrors: true,
+			SortDefaults:     settings.Defaults,
+		})
+		doc.result = result
+
+		var verifyErr *processor.VerificationError
+		if errors.As(err, &verifyErr) {
+			if logErr := s.logError(fmt.Sprintf("%s: %v", doc.path, err)); logErr != nil {
+				return &responseError{Code: codeInternalError, Message: logErr.Error()}
+			}
+		}
+	}
+
+	version := doc.version
+	if err := s.conn.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+		URI:         doc.uri,
+		Version:     &version,
+		Diagnostics: diagnostics(doc),
+	}); err != nil {
+		return &responseError{Code: codeInternalError, Message: err.Error()}
+	}
+	return nil
+}
+
+// diagnostics reports every unsorted or misconfigured structure on its magic comment
+func diagnostics(doc *document) []Diagnostic {
+	diags := []Diagnostic{}
+	for _, structure := range doc.result.Structures {
+		if diag, ok := diagnosticFor(doc, structure); ok {
+			diags = append(diags, diag)
+		}
+	}
+	return diags
+}
+
+func diagnosticFor(doc *document, structure processor.StructureResult) (Diagnostic, bool) {
+	diag := Diagnostic{
+		Range:  toRange(doc.text, structure.CommentStartByte, structure.CommentEndByte),
+		Source: diagnosticSource,
+	}
+
+	switch {
+	case structure.Options.HasError:
+		diag.Severity = SeverityError
+		diag.Code = report.RuleInvalidMagicComment
+		diag.Message = structure.Error
+	case !structure.Sorted && len(structure.Edits) > 0:
+		diag.Severity = SeverityWarning
+		diag.Code = report.RuleForKind(structure.Kind)
+		diag.Message = report.UnsortedMessage(structure.Kind)
+	default:
+		// Sorted, or left alone because of syntax errors the user is still fixing
+		return Diagnostic{}, false
+	}
+	return diag, true
+}
+
+// codeActions offers to sort the unsorted structures overlapping the
+// requested range, and every structure in the file at once
+func (s *Server) codeActions(params CodeActionParams) (any, *responseError) {
+	doc, rpcErr := s.document(params.TextDocument.URI)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	actions := []CodeAction{}
+	start := toOffset(doc.text, params.Range.Start)
+	end := toOffset(doc.text, params.Range.End)
+
+	if wantsKind(params.Context.Only, CodeActionQuickFix) {
+		for _, structure := range doc.result.Structures {
+			if len(structure.Edits) == 0 {
+				continue
+			}
+			// The block spans from its magic comment to its closing bracket
+			blockStart := min(structure.StartByte, structure.CommentStartByte)
+			if start > structure.EndByte || end < blockStart {
+				continue
+			}
+
+			action := CodeAction{
+				Title:       "Sort this block",
+				Kind:        CodeActionQuickFix,
+				IsPreferred: true,
+				Edit:        &WorkspaceEdit{Changes: map[string][]TextEdit{doc.uri: textEdits(doc, structure.Edits)}},
+			}
+			if diag, ok := diagnosticFor(doc, structure); ok {
+				action.Diagnostics = []Diagnostic{diag}
+			}
+			actions = append(actions, action)
+		}
+	}
+
+	if wantsKind(params.Context.Only, CodeActionSortAll) && doc.result.Changed {
+		actions = append(actions, CodeAction{
+			Title: "Sort all in file",
+			Kind:  CodeActionSortAll,
+			Edit:  &WorkspaceEdit{Changes: map[string][]TextEdit{doc.uri: sortAllEdits(doc)}},
+		})
+	}
+
+	return actions, nil
+}
+
+// wantsKind reports whether a code action kind was requested; kinds are
+// hierarchical, so "source" includes "source.sortAll"
+func wantsKind(only []string, kind string) bool {
+	if len(only) == 0 {
+		return true
+	}
+	for _, requested := range only {
+		if kind == requested || strings.HasPrefix(kind, requested+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// formatting sorts every structure in the document
+func (s *Server) formatting(params DocumentFormattingParams) (any, *responseError) {
+	doc, rpcErr := s.document(params.TextDocument.URI)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	if !doc.result.Changed {
+		return []TextEdit{}, nil
+	}
+	return sortAllEdits(doc), nil
+}
+
+// sortAllEdits returns the edits of the outermost changed structures, which
+// together produce the verified sorted text
+func sortAllEdits(doc *document) []TextEdit {
+	return textEdits(doc, doc.result.Edits)
+}
+
+// textEdits converts the processor's edits into LSP edits of the document
+func textEdits(doc *document, edits []processor.TextEdit) []TextEdit {
+	converted := make([]TextEdit, 0, len(edits))
+	for _, edit := range edits {
+		converted = append(converted, TextEdit{
+			Range:   toRange(doc.text, edit.StartByte, edit.EndByte),
+			NewText: edit.NewText,
+		})
+	}
+	return converted
+}
+
+// logError shows a message in the client's log
+func (s *Server) logError(message string) error {
+	return s.conn.notify("window/logMessage", LogMessageParams{Type: 1, Message: message})
+}
+
+// uriToPath converts a file URI into a file system path. Only the path picks
+// the grammar and the project configuration, so the file need not exist.
+func uriToPath(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", fmt.Errorf("invalid document URI %q: %w", uri, err)
+	}
+	if u.Scheme != "file" {
+		// Unsaved buffers have no location; their name still selects the grammar
+		return filepath.Base(u.Opaque + u.Path), nil
+	}
+
+	path := u.Path
+	if runtime.GOOS == "windows" {
+		// file:///C:/src/a.ts has the path /C:/src/a.ts
+		path = strings.TrimPrefix(path, "/")
+	}
+	return filepath.FromSlash(path), nil
+}
@@ -0,0 +1,388 @@
+package lsp
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClient drives a Server in-process over pipes
+type fakeClient struct {
+	t        *testing.T
+	conn     *conn
+	toServer *io.PipeWriter
+	messages chan *message
+	done     chan error
+	nextID   int
+}
+
+func newFakeClient(t *testing.T, opts Options) *fakeClient {
+	t.Helper()
+
+	serverIn, toServer := io.Pipe()
+	fromServer, serverOut := io.Pipe()
+
+	server, err := NewServer(serverIn, serverOut, opts)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	c := &fakeClient{
+		t:        t,
+		conn:     newConn(fromServer, toServer),
+		toServer: toServer,
+		messages: make(chan *message, 100),
+		done:     make(chan error, 1),
+	}
+
+	go func() {
+		err := server.Run()
+		serverOut.Close()
+		c.done <- err
+	}()
+	go func() {
+		defer close(c.messages)
+		for {
+			msg, err := c.conn.read()
+			if err != nil {
+				return
+			}
+			c.messages <- msg
+		}
+	}()
+
+	t.Cleanup(func() { toServer.Close() })
+	return c
+}
+
+func (c *fakeClient) notify(method string, params any) {
+	c.t.Helper()
+	if err := c.conn.notify(method, params); err != nil {
+		c.t.Fatalf("Failed to send %s: %v", method, err)
+	}
+}
+
+// request sends a request and waits for its response, decoding the result
+// into result. Notifications received meanwhile are dropped.
+func (c *fakeClient) request(method string, params, result any) *responseError {
+	c.t.Helper()
+	c.nextID++
+	id := json.RawMessage(strings.TrimSpace(mustMarshal(c.t, c.nextID)))
+
+	raw, err := json.Marshal(params)
+	if err != nil {
+		c.t.Fatalf("Failed to encode params: %v", err)
+	}
+	if err := c.conn.write(&message{ID: id, Method: method, Params: raw}); err != nil {
+		c.t.Fatalf("Failed to send %s: %v", method, err)
+	}
+
+	for {
+		msg := c.next()
+		if msg.Method != "" || string(msg.ID) != string(id) {
+			continue
+		}
+		if msg.Error != nil {
+			return msg.Error
+		}
+		if result != nil {
+			if err := json.Unmarshal(msg.Result, result); err != nil {
+				c.t.Fatalf("Failed to decode %s result: %v", method, err)
+			}
+		}
+		return nil
+	}
+}
+
+// diagnostics waits for the next diagnostics published for uri
+func (c *fakeClient) diagnostics(uri string) PublishDiagnosticsParams {
+	c.t.Helper()
+	for {
+		msg := c.next()
+		if msg.Method != "textDocument/publishDiagnostics" {
+			continue
+		}
+		var params PublishDiagnosticsParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			c.t.Fatalf("Failed to decode diagnostics: %v", err)
+		}
+		if params.URI == uri {
+			return params
+		}
+	}
+}
+
+func (c *fakeClient) next() *message {
+	c.t.Helper()
+	select {
+	case msg, ok := <-c.messages:
+		if !ok {
+			c.t.Fatal("server closed the connection")
+		}
+		return msg
+	case <-time.After(5 * time.Second):
+		c.t.Fatal("timed out waiting for a message from the server")
+		return nil
+	}
+}
+
+func (c *fakeClient) initialize() InitializeResult {
+	c.t.Helper()
+	var result InitializeResult
+	if err := c.request("initialize", map[string]any{"capabilities": map[string]any{}}, &result); err != nil {
+		c.t.Fatalf("initialize failed: %v", err)
+	}
+	c.notify("initialized", struct{}{})
+	return result
+}
+
+func mustMarshal(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+	return string(data)
+}
+
+// applyTextEdits applies non-overlapping edits to text, as editors do
+func applyTextEdits(text string, edits []TextEdit) string {
+	sorted := append([]TextEdit(nil), edits...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return toOffset([]byte(text), sorted[i].Range.Start) > toOffset([]byte(text), sorted[j].Range.Start)
+	})
+	for _, edit := range sorted {
+		start := toOffset([]byte(text), edit.Range.Start)
+		end := toOffset([]byte(text), edit.Range.End)
+		text = text[:start] + edit.NewText + text[end:]
+	}
+	return text
+}
+
+func fileURI(path string) string {
+	return "file://" + filepath.ToSlash(path)
+}
+
+const unsortedSource = `const config = {
+  /** tree-sorter-ts: keep-sorted **/
+  zebra: "🦓",
+  apple: "🍎",
+};
+
+const sorted = [
+  /** tree-sorter-ts: keep-sorted **/
+  "a",
+  "b",
+];
+
+const ids = [
+  /** tree-sorter-ts: keep-sorted key="id" sort-by-comment **/
+  { id: 2 },
+  { id: 1 },
+];
+`
+
+func TestServerDiagnostics(t *testing.T) {
+	c := newFakeClient(t, Options{Version: "1.2.3"})
+
+	result := c.initialize()
+	if result.ServerInfo.Version != "1.2.3" || !result.Capabilities.DocumentFormattingProvider {
+		t.Errorf("unexpected initialize result: %+v", result)
+	}
+
+	uri := fileURI(filepath.Join(t.TempDir(), "config.ts"))
+	c.notify("textDocument/didOpen", DidOpenTextDocumentParams{
+		TextDocument: TextDocumentItem{URI: uri, LanguageID: "typescript", Version: 1, Text: unsortedSource},
+	})
+
+	published := c.diagnostics(uri)
+	if published.Version == nil || *published.Version != 1 {
+		t.Errorf("diagnostics version = %v, want 1", published.Version)
+	}
+	if len(published.Diagnostics) != 2 {
+		t.Fatalf("got %d diagnostics, want 2: %+v", len(published.Diagnostics), published.Diagnostics)
+	}
+
+	unsorted := published.Diagnostics[0]
+	wantRange := Range{Start: Position{Line: 1, Character: 2}, End: Position{Line: 1, Character: 37}}
+	if unsorted.Range != wantRange || unsorted.Severity != SeverityWarning || unsorted.Code != "unsorted-object" {
+		t.Errorf("unexpected unsorted diagnostic: %+v", unsorted)
+	}
+
+	invalid := published.Diagnostics[1]
+	if invalid.Severity != SeverityError || invalid.Code != "invalid-magic-comment" || invalid.Range.Start.Line != 13 {
+		t.Errorf("unexpected invalid magic comment diagnostic: %+v", invalid)
+	}
+
+	// Fixing the conflicting options leaves the unsorted object
+	fixed := strings.Replace(unsortedSource, ` key="id" sort-by-comment`, ` key="id"`, 1)
+	c.notify("textDocument/didChange", DidChangeTextDocumentParams{
+		TextDocument:   VersionedTextDocumentIdentifier{URI: uri, Version: 2},
+		ContentChanges: []TextDocumentContentChangeEvent{{Text: fixed}},
+	})
+	published = c.diagnostics(uri)
+	if len(published.Diagnostics) != 2 || published.Diagnostics[1].Code != "unsorted-array" {
+		t.Errorf("unexpected diagnostics after change: %+v", published.Diagnostics)
+	}
+
+	// An incremental edit swapping the object's properties sorts it; the
+	// emoji count two UTF-16 code units each
+	c.notify("textDocument/didChange", DidChangeTextDocumentParams{
+		TextDocument: VersionedTextDocumentIdentifier{URI: uri, Version: 3},
+		ContentChanges: []TextDocumentContentChangeEvent{{
+			Range: &Range{Start: Position{Line: 2, Character: 0}, End: Position{Line: 4, Character: 0}},
+			Text:  "  apple: \"🍎\",\n  zebra: \"🦓\",\n",
+		}},
+	})
+	published = c.diagnostics(uri)
+	if len(published.Diagnostics) != 1 || published.Diagnostics[0].Code != "unsorted-array" {
+		t.Errorf("unexpected diagnostics after incremental change: %+v", published.Diagnostics)
+	}
+
+	c.notify("textDocument/didClose", DidCloseTextDocumentParams{TextDocument: TextDocumentIdentifier{URI: uri}})
+	if published = c.diagnostics(uri); len(published.Diagnostics) != 0 {
+		t.Errorf("diagnostics were not cleared on close: %+v", published.Diagnostics)
+	}
+}
+
+func TestServerCodeActionsAndFormatting(t *testing.T) {
+	c := newFakeClient(t, Options{})
+	c.initialize()
+
+	source := strings.Replace(unsortedSource, ` key="id" sort-by-comment`, ` key="id"`, 1)
+	uri := fileURI(filepath.Join(t.TempDir(), "config.ts"))
+	c.notify("textDocument/didOpen", DidOpenTextDocumentParams{
+		TextDocument: TextDocumentItem{URI: uri, Version: 1, Text: source},
+	})
+	c.diagnostics(uri)
+
+	// A cursor inside the object offers to sort it, and the whole file
+	var actions []CodeAction
+	cursor := Range{Start: Position{Line: 3, Character: 4}, End: Position{Line: 3, Character: 4}}
+	if err := c.request("textDocument/codeAction", CodeActionParams{
+		TextDocument: TextDocumentIdentifier{URI: uri},
+		Range:        cursor,
+	}, &actions); err != nil {
+		t.Fatalf("codeAction failed: %v", err)
+	}
+	if len(actions) != 2 {
+		t.Fatalf("got %d actions, want 2: %+v", len(actions), actions)
+	}
+
+	block := actions[0]
+	if block.Title != "Sort this block" || block.Kind != CodeActionQuickFix || len(block.Diagnostics) != 1 {
+		t.Errorf("unexpected block action: %+v", block)
+	}
+	// The block's edits stay inside the object and sort only it
+	sortedObject := applyTextEdits(source, block.Edit.Changes[uri])
+	objectStart, objectEnd := strings.Index(source, "{"), strings.Index(source, "};")+1
+	for _, edit := range block.Edit.Changes[uri] {
+		if toOffset([]byte(source), edit.Range.Start) < objectStart || toOffset([]byte(source), edit.Range.End) > objectEnd {
+			t.Errorf("edit %+v is outside the object", edit)
+		}
+	}
+	if !strings.Contains(sortedObject, "apple: \"🍎\",\n  zebra") || !strings.Contains(sortedObject, "{ id: 2 },\n  { id: 1 }") {
+		t.Errorf("block edits do not sort only the object:\n%s", sortedObject)
+	}
+
+	all := actions[1]
+	if all.Title != "Sort all in file" || all.Kind != CodeActionSortAll {
+		t.Errorf("unexpected sort all action: %+v", all)
+	}
+	allEdits := all.Edit.Changes[uri]
+	sortedAll := applyTextEdits(source, allEdits)
+	if !strings.Contains(sortedAll, "apple: \"🍎\",\n  zebra") || !strings.Contains(sortedAll, "{ id: 1 },\n  { id: 2 }") {
+		t.Errorf("sort all edits do not sort the file:\n%s", sortedAll)
+	}
+
+	// Only source actions, on a range without unsorted structures
+	actions = nil
+	if err := c.request("textDocument/codeAction", CodeActionParams{
+		TextDocument: TextDocumentIdentifier{URI: uri},
+		Range:        Range{Start: Position{Line: 7}, End: Position{Line: 7}},
+		Context:      CodeActionContext{Only: []string{"source"}},
+	}, &actions); err != nil {
+		t.Fatalf("codeAction failed: %v", err)
+	}
+	if len(actions) != 1 || actions[0].Kind != CodeActionSortAll {
+		t.Errorf("unexpected source actions: %+v", actions)
+	}
+
+	var formatting []TextEdit
+	if err := c.request("textDocument/formatting", DocumentFormattingParams{
+		TextDocument: TextDocumentIdentifier{URI: uri},
+	}, &formatting); err != nil {
+		t.Fatalf("formatting failed: %v", err)
+	}
+	if len(formatting) != len(allEdits) || applyTextEdits(source, formatting) != sortedAll {
+		t.Errorf("unexpected formatting edits: %+v", formatting)
+	}
+
+	// Sorted documents need no formatting
+	c.notify("textDocument/didChange", DidChangeTextDocumentParams{
+		TextDocument:   VersionedTextDocumentIdentifier{URI: uri, Version: 2},
+		ContentChanges: []TextDocumentContentChangeEvent{{Text: sortedAll}},
+	})
+	if published := c.diagnostics(uri); len(published.Diagnostics) != 0 {
+		t.Errorf("sorted document has diagnostics: %+v", published.Diagnostics)
+	}
+	formatting = nil
+	if err := c.request("textDocument/formatting", DocumentFormattingParams{
+		TextDocument: TextDocumentIdentifier{URI: uri},
+	}, &formatting); err != nil || len(formatting) != 0 {
+		t.Errorf("formatting a sorted document = %+v, %v", formatting, err)
+	}
+}
+
+func TestServerLifecycle(t *testing.T) {
+	c := newFakeClient(t, Options{})
+
+	if err := c.request("textDocument/formatting", DocumentFormattingParams{}, nil); err == nil || err.Code != codeServerNotInitialized {
+		t.Errorf("request before initialize = %v, want server not initialized", err)
+	}
+
+	c.initialize()
+
+	if err := c.request("workspace/symbol", map[string]string{}, nil); err == nil || err.Code != codeMethodNotFound {
+		t.Errorf("unknown method = %v, want method not found", err)
+	}
+	if err := c.request("textDocument/formatting", DocumentFormattingParams{
+		TextDocument: TextDocumentIdentifier{URI: "file:///not/open.ts"},
+	}, nil); err == nil || err.Code != codeInvalidParams {
+		t.Errorf("formatting a closed document = %v, want invalid params", err)
+	}
+
+	if err := c.request("shutdown", nil, nil); err != nil {
+		t.Fatalf("shutdown failed: %v", err)
+	}
+	c.notify("exit", nil)
+
+	select {
+	case err := <-c.done:
+		if err != nil {
+			t.Errorf("Run returned %v after shutdown and exit", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not stop after exit")
+	}
+}
+
+func TestServerExitWithoutShutdown(t *testing.T) {
+	c := newFakeClient(t, Options{})
+	c.initialize()
+	c.notify("exit", nil)
+
+	select {
+	case err := <-c.done:
+		if !errors.Is(err, ErrExitWithoutShutdown) {
+			t.Errorf("Run returned %v, want ErrExitWithoutShutdown", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not stop after exit")
+	}
+}
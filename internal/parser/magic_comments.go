@@ -5,70 +5,102 @@ import (
 
 	"github.com/evanrichards/tree-sorter-ts/internal/config"
 	"github.com/evanrichards/tree-sorter-ts/internal/sorting/types/arrays"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/types/constructors"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/types/enums"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/types/members"
 	"github.com/evanrichards/tree-sorter-ts/internal/sorting/types/objects"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/types/statements"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/types/typemembers"
 
 	sitter "github.com/smacker/go-tree-sitter"
 )
 
 var (
 	magicCommentRegex = regexp.MustCompile(`(?s)/\*\*?.*?tree-sorter-ts:\s*keep-sorted\b.*?\*/`)
+	anyMarkerRegex    = regexp.MustCompile(`(?s)/\*\*?.*?tree-sorter-ts:\s*(keep-sorted|start-sort|end-sort)\b.*?\*/`)
 )
 
-// FindObjectsWithMagicComments finds all objects containing magic comments
-func FindObjectsWithMagicComments(node *sitter.Node, content []byte) ([]*objects.ObjectSorter, error) {
-	var results []*objects.ObjectSorter
+// HasMagicComment reports whether content mentions a magic comment or a
+// section marker anywhere, which lets files without one skip parsing
+func HasMagicComment(content []byte) bool {
+	return anyMarkerRegex.Match(content)
+}
 
-	var traverse func(*sitter.Node)
-	traverse = func(n *sitter.Node) {
-		if n.Type() == "object" {
-			// Check children for magic comment
-			for i := 0; i < int(n.ChildCount()); i++ {
-				child := n.Child(i)
-				if child.Type() == "comment" {
-					text := content[child.StartByte():child.EndByte()]
-					if magicCommentRegex.Match(text) {
-						cfg := config.ParseSortConfig(text)
-						if err := cfg.Validate(); err != nil {
-							// Skip objects with invalid config
-							continue
-						}
-						results = append(results, objects.NewObjectSorter(n, child, i))
-						break
-					}
-				}
-			}
-		}
+// IsMagicComment reports whether the text of a comment node is a magic comment
+func IsMagicComment(text []byte) bool {
+	return magicCommentRegex.Match(text)
+}
 
-		for i := 0; i < int(n.ChildCount()); i++ {
-			traverse(n.Child(i))
-		}
-	}
+// ParseMagicComment extracts the options of a magic comment. Conflicting
+// options are reported through HasError rather than an error, so that the
+// structure can still be described.
+func ParseMagicComment(text []byte) config.SortConfig {
+	cfg := config.ParseSortConfig(text)
+	_ = cfg.Validate()
+	return cfg
+}
 
-	traverse(node)
-	return results, nil
+// FindObjectsWithMagicComments finds all objects containing magic comments
+func FindObjectsWithMagicComments(node *sitter.Node, content []byte) []*objects.ObjectSorter {
+	return findWithMagicComments(node, content, ofType("object"), objects.NewObjectSorter)
 }
 
 // FindArraysWithMagicComments finds all arrays containing magic comments
-func FindArraysWithMagicComments(node *sitter.Node, content []byte) ([]*arrays.ArraySorter, error) {
-	var results []*arrays.ArraySorter
+func FindArraysWithMagicComments(node *sitter.Node, content []byte) []*arrays.ArraySorter {
+	return findWithMagicComments(node, content, ofType("array"), arrays.NewArraySorter)
+}
+
+// FindConstructorsWithMagicComments finds all parameter lists containing
+// magic comments
+func FindConstructorsWithMagicComments(node *sitter.Node, content []byte) []*constructors.ConstructorSorter {
+	return findWithMagicComments(node, content, ofType("formal_parameters"), constructors.NewConstructorSorter)
+}
+
+// FindStatementsWithMagicComments finds all files, namespaces and module
+// declarations whose statements contain magic comments
+func FindStatementsWithMagicComments(node *sitter.Node, content []byte) []*statements.StatementSorter {
+	return findWithMagicComments(node, content, statements.IsStatementList, statements.NewStatementSorter)
+}
+
+// FindClassesWithMagicComments finds all class bodies containing magic
+// comments
+func FindClassesWithMagicComments(node *sitter.Node, content []byte) []*members.ClassSorter {
+	return findWithMagicComments(node, content, ofType("class_body"), members.NewClassSorter)
+}
+
+// FindTypesWithMagicComments finds all interface bodies and type literals
+// containing magic comments
+func FindTypesWithMagicComments(node *sitter.Node, content []byte) []*typemembers.TypeSorter {
+	return findWithMagicComments(node, content, typemembers.IsTypeBody, typemembers.NewTypeSorter)
+}
+
+// FindEnumsWithMagicComments finds all enum bodies containing magic comments
+func FindEnumsWithMagicComments(node *sitter.Node, content []byte) []*enums.EnumSorter {
+	return findWithMagicComments(node, content, enums.IsEnumBody, enums.NewEnumSorter)
+}
+
+// ofType returns a matcher for nodes of the given type
+func ofType(nodeType string) func(*sitter.Node) bool {
+	return func(n *sitter.Node) bool {
+		return n.Type() == nodeType
+	}
+}
+
+// findWithMagicComments finds the nodes that isContainer accepts and that
+// have a magic comment among their children, in document order, outer nodes
+// first
+func findWithMagicComments[T any](node *sitter.Node, content []byte, isContainer func(*sitter.Node) bool, newSorter func(node, magicComment *sitter.Node, magicIndex int) T) []T {
+	var results []T
 
 	var traverse func(*sitter.Node)
 	traverse = func(n *sitter.Node) {
-		if n.Type() == "array" {
+		if isContainer(n) {
 			// Check children for magic comment
 			for i := 0; i < int(n.ChildCount()); i++ {
 				child := n.Child(i)
-				if child.Type() == "comment" {
-					text := content[child.StartByte():child.EndByte()]
-					if magicCommentRegex.Match(text) {
-						cfg := config.ParseSortConfig(text)
-						if err := cfg.Validate(); err != nil {
-							// Skip arrays with invalid config
-							continue
-						}
-						results = append(results, arrays.NewArraySorter(n, child, i))
-						break
-					}
+				if child.Type() == "comment" && IsMagicComment(content[child.StartByte():child.EndByte()]) {
+					results = append(results, newSorter(n, child, i))
+					break
 				}
 			}
 		}
@@ -79,5 +111,5 @@ func FindArraysWithMagicComments(node *sitter.Node, content []byte) ([]*arrays.A
 	}
 
 	traverse(node)
-	return results, nil
-}
\ No newline at end of file
+	return results
+}
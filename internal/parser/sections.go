@@ -0,0 +1,185 @@
+package parser
+
+import (
+	"regexp"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/types/arrays"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/types/constructors"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/types/enums"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/types/members"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/types/objects"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/types/statements"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/types/typemembers"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+var (
+	startSortRegex = regexp.MustCompile(`(?s)/\*\*?.*?tree-sorter-ts:\s*start-sort\b.*?\*/`)
+	endSortRegex   = regexp.MustCompile(`(?s)/\*\*?.*?tree-sorter-ts:\s*end-sort\b.*?\*/`)
+)
+
+// isSectionContainer reports whether a node can hold start-sort sections
+func isSectionContainer(n *sitter.Node) bool {
+	switch n.Type() {
+	case "array", "class_body", "formal_parameters", "object":
+		return true
+	}
+	return statements.IsStatementList(n) || typemembers.IsTypeBody(n) || enums.IsEnumBody(n)
+}
+
+// UnbalancedMarker is a start-sort marker without a matching end-sort marker,
+// or an end-sort marker without a preceding start-sort marker. A start-sort
+// marker followed by an item on its own line cannot open a section either,
+// since the items of a section are written one per line after the marker.
+type UnbalancedMarker struct {
+	Container *sitter.Node
+	Marker    *sitter.Node
+	IsStart   bool
+	Inline    bool // The marker shares its line with the first item of its section
+}
+
+// section is a balanced pair of markers among a container's children
+type section struct {
+	start      *sitter.Node
+	startIndex int
+	endIndex   int
+}
+
+// FindObjectSections finds the start-sort sections of all objects
+func FindObjectSections(node *sitter.Node, content []byte) []*objects.ObjectSorter {
+	return findSections(node, content, ofType("object"), objects.NewObjectSection)
+}
+
+// FindArraySections finds the start-sort sections of all arrays
+func FindArraySections(node *sitter.Node, content []byte) []*arrays.ArraySorter {
+	return findSections(node, content, ofType("array"), arrays.NewArraySection)
+}
+
+// FindConstructorSections finds the start-sort sections of all parameter lists
+func FindConstructorSections(node *sitter.Node, content []byte) []*constructors.ConstructorSorter {
+	return findSections(node, content, ofType("formal_parameters"), constructors.NewConstructorSection)
+}
+
+// FindStatementSections finds the start-sort sections of all files,
+// namespaces and module declarations
+func FindStatementSections(node *sitter.Node, content []byte) []*statements.StatementSorter {
+	return findSections(node, content, statements.IsStatementList, statements.NewStatementSection)
+}
+
+// FindClassSections finds the start-sort sections of all class bodies
+func FindClassSections(node *sitter.Node, content []byte) []*members.ClassSorter {
+	return findSections(node, content, ofType("class_body"), members.NewClassSection)
+}
+
+// FindTypeSections finds the start-sort sections of all interface bodies and
+// type literals
+func FindTypeSections(node *sitter.Node, content []byte) []*typemembers.TypeSorter {
+	return findSections(node, content, typemembers.IsTypeBody, typemembers.NewTypeSection)
+}
+
+// FindEnumSections finds the start-sort sections of all enum bodies
+func FindEnumSections(node *sitter.Node, content []byte) []*enums.EnumSorter {
+	return findSections(node, content, enums.IsEnumBody, enums.NewEnumSection)
+}
+
+// FindUnbalancedSectionMarkers finds the section markers that do not pair up,
+// in document order
+func FindUnbalancedSectionMarkers(node *sitter.Node, content []byte) []UnbalancedMarker {
+	var results []UnbalancedMarker
+
+	var traverse func(*sitter.Node)
+	traverse = func(n *sitter.Node) {
+		if isSectionContainer(n) {
+			_, unbalanced := scanSections(n, content)
+			results = append(results, unbalanced...)
+		}
+
+		for i := 0; i < int(n.ChildCount()); i++ {
+			traverse(n.Child(i))
+		}
+	}
+
+	traverse(node)
+	return results
+}
+
+// findSections finds the sections of the nodes that isContainer accepts, in
+// document order, outer nodes first
+func findSections[T any](node *sitter.Node, content []byte, isContainer func(*sitter.Node) bool, newSection func(node, startMarker *sitter.Node, startIndex, endIndex int) T) []T {
+	var results []T
+
+	var traverse func(*sitter.Node)
+	traverse = func(n *sitter.Node) {
+		if isContainer(n) {
+			sections, _ := scanSections(n, content)
+			for _, s := range sections {
+				results = append(results, newSection(n, s.start, s.startIndex, s.endIndex))
+			}
+		}
+
+		for i := 0; i < int(n.ChildCount()); i++ {
+			traverse(n.Child(i))
+		}
+	}
+
+	traverse(node)
+	return results
+}
+
+// scanSections pairs up the section markers among a container's children.
+// Containers with a keep-sorted comment are sorted as a whole, so their
+// markers are ignored. Sections do not nest: a start-sort marker inside an
+// open section leaves the earlier one unbalanced.
+func scanSections(n *sitter.Node, content []byte) ([]section, []UnbalancedMarker) {
+	var sections []section
+	var unbalanced []UnbalancedMarker
+
+	open := -1
+	for i := 0; i < int(n.ChildCount()); i++ {
+		child := n.Child(i)
+		if child.Type() != "comment" {
+			continue
+		}
+
+		text := content[child.StartByte():child.EndByte()]
+		switch {
+		case IsMagicComment(text):
+			return nil, nil
+		case startSortRegex.Match(text):
+			if open >= 0 {
+				unbalanced = append(unbalanced, UnbalancedMarker{Container: n, Marker: n.Child(open), IsStart: true})
+			}
+			open = i
+		case endSortRegex.Match(text):
+			if open < 0 {
+				unbalanced = append(unbalanced, UnbalancedMarker{Container: n, Marker: child})
+				continue
+			}
+			if sharesLineWithItem(n, open, i) {
+				unbalanced = append(unbalanced, UnbalancedMarker{Container: n, Marker: n.Child(open), IsStart: true, Inline: true})
+			} else {
+				sections = append(sections, section{start: n.Child(open), startIndex: open, endIndex: i})
+			}
+			open = -1
+		}
+	}
+
+	if open >= 0 {
+		unbalanced = append(unbalanced, UnbalancedMarker{Container: n, Marker: n.Child(open), IsStart: true})
+	}
+	return sections, unbalanced
+}
+
+// sharesLineWithItem reports whether the first item after the start-sort
+// marker at startIndex, before the end-sort marker at endIndex, begins on the
+// line the marker ends on
+func sharesLineWithItem(n *sitter.Node, startIndex, endIndex int) bool {
+	marker := n.Child(startIndex)
+	for i := startIndex + 1; i < endIndex; i++ {
+		if child := n.Child(i); child.Type() != "comment" {
+			return child.StartPoint().Row == marker.EndPoint().Row
+		}
+	}
+	return false
+}
@@ -3,6 +3,8 @@ package processor
 import (
 	"strings"
 	"testing"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/parser"
 )
 
 func TestArraySorting(t *testing.T) {
@@ -354,12 +356,12 @@ const config = [
 				t.Fatalf("failed to parse: %v", err)
 			}
 
-			arrays := findArraysWithMagicCommentsAST(tree, content)
+			arrays := parser.FindArraysWithMagicComments(tree, content)
 			if len(arrays) != 1 {
 				t.Fatalf("expected 1 array, got %d", len(arrays))
 			}
 
-			_, needSort := sortArrayAST(arrays[0], content)
+			_, needSort := sortSortable(arrays[0], content)
 
 			if tt.wantSorted == "" {
 				if needSort {
@@ -376,9 +378,9 @@ const config = [
 			newContent := make([]byte, len(content))
 			copy(newContent, content)
 
-			sortedContent, _ := sortArrayAST(arrays[0], content)
-			start := arrays[0].array.StartByte()
-			end := arrays[0].array.EndByte()
+			sortedContent, _ := sortSortable(arrays[0], content)
+			start := arrays[0].GetNode().StartByte()
+			end := arrays[0].GetNode().EndByte()
 
 			before := newContent[:start]
 			after := newContent[end:]
@@ -487,7 +489,7 @@ const config = [
 				t.Fatalf("failed to parse: %v", err)
 			}
 
-			arrays := findArraysWithMagicCommentsAST(tree, content)
+			arrays := parser.FindArraysWithMagicComments(tree, content)
 			if len(arrays) != tt.wantCount {
 				t.Errorf("expected %d arrays, got %d", tt.wantCount, len(arrays))
 			}
@@ -655,13 +657,13 @@ const items = [
 				t.Fatalf("failed to parse: %v", err)
 			}
 
-			arrays := findArraysWithMagicCommentsAST(tree, content)
+			arrays := parser.FindArraysWithMagicComments(tree, content)
 			if len(arrays) != 1 {
 				t.Fatalf("expected 1 array, got %d", len(arrays))
 			}
 
 			arr := arrays[0]
-			sortedContent, changed := sortArrayAST(arr, content)
+			sortedContent, changed := sortSortable(arr, content)
 
 			if tt.wantSorted == "" {
 				// Expecting no change
@@ -678,8 +680,8 @@ const items = [
 				newContent := make([]byte, len(content))
 				copy(newContent, content)
 
-				start := arr.array.StartByte()
-				end := arr.array.EndByte()
+				start := arr.GetNode().StartByte()
+				end := arr.GetNode().EndByte()
 
 				before := newContent[:start]
 				after := newContent[end:]
@@ -720,13 +722,13 @@ const items = [
 				t.Fatalf("failed to parse: %v", err)
 			}
 
-			arrays := findArraysWithMagicCommentsAST(tree, content)
+			arrays := parser.FindArraysWithMagicComments(tree, content)
 			if len(arrays) != 1 {
 				t.Fatalf("expected 1 array, got %d", len(arrays))
 			}
 
 			arr := arrays[0]
-			if !arr.sortConfig.HasError {
+			if !extractConfig(arr, content).HasError {
 				t.Errorf("expected configuration error, but none was detected")
 			}
 		})
@@ -792,7 +794,7 @@ func TestObjectCommentDuplicationBug(t *testing.T) {
 		t.Fatalf("failed to parse: %v", err)
 	}
 
-	objects := findObjectsWithMagicCommentsAST(tree, contentBytes)
+	objects := parser.FindObjectsWithMagicComments(tree, contentBytes)
 	if len(objects) != 1 {
 		t.Fatalf("expected 1 object, got %d", len(objects))
 	}
@@ -801,9 +803,9 @@ func TestObjectCommentDuplicationBug(t *testing.T) {
 	newContent := make([]byte, len(contentBytes))
 	copy(newContent, contentBytes)
 
-	sortedContent, _ := sortObjectAST(objects[0], contentBytes)
-	start := objects[0].object.StartByte()
-	end := objects[0].object.EndByte()
+	sortedContent, _ := sortSortable(objects[0], contentBytes)
+	start := objects[0].GetNode().StartByte()
+	end := objects[0].GetNode().EndByte()
 
 	before := newContent[:start]
 	after := newContent[end:]
@@ -874,7 +876,7 @@ func TestArrayCommentSortingWorks(t *testing.T) {
 		t.Fatalf("failed to parse: %v", err)
 	}
 
-	arrays := findArraysWithMagicCommentsAST(tree, contentBytes)
+	arrays := parser.FindArraysWithMagicComments(tree, contentBytes)
 	if len(arrays) != 1 {
 		t.Fatalf("expected 1 array, got %d", len(arrays))
 	}
@@ -883,9 +885,9 @@ func TestArrayCommentSortingWorks(t *testing.T) {
 	newContent := make([]byte, len(contentBytes))
 	copy(newContent, contentBytes)
 
-	sortedContent, _ := sortArrayAST(arrays[0], contentBytes)
-	start := arrays[0].array.StartByte()
-	end := arrays[0].array.EndByte()
+	sortedContent, _ := sortSortable(arrays[0], contentBytes)
+	start := arrays[0].GetNode().StartByte()
+	end := arrays[0].GetNode().EndByte()
 
 	before := newContent[:start]
 	after := newContent[end:]
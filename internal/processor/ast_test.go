@@ -5,12 +5,15 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/evanrichards/tree-sorter-ts/internal/parser"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/types/objects"
+
 	sitter "github.com/smacker/go-tree-sitter"
 )
 
 func parseTypeScript(content string) (*sitter.Node, []byte, error) {
-	parser := parserPool.Get().(*sitter.Parser)
-	defer parserPool.Put(parser)
+	parser := getParser(LanguageTypeScript)
+	defer putParser(LanguageTypeScript, parser)
 
 	contentBytes := []byte(content)
 	tree, err := parser.ParseCtx(context.Background(), nil, contentBytes)
@@ -89,16 +92,16 @@ const b = {
 				t.Fatalf("Failed to parse: %v", err)
 			}
 
-			objects := findObjectsWithMagicCommentsAST(root, content)
+			objects := parser.FindObjectsWithMagicComments(root, content)
 
 			if len(objects) != tt.wantCount {
 				t.Errorf("Found %d objects, want %d", len(objects), tt.wantCount)
 			}
 
 			for i, obj := range objects {
-				if i < len(tt.wantIndices) && obj.magicIndex != tt.wantIndices[i] {
+				if i < len(tt.wantIndices) && obj.GetMagicCommentIndex() != tt.wantIndices[i] {
 					t.Errorf("Object %d: magic comment at index %d, want %d",
-						i, obj.magicIndex, tt.wantIndices[i])
+						i, obj.GetMagicCommentIndex(), tt.wantIndices[i])
 				}
 			}
 		})
@@ -292,12 +295,12 @@ const b = {
 				t.Fatalf("Failed to parse: %v", err)
 			}
 
-			objects := findObjectsWithMagicCommentsAST(root, contentBytes)
+			objects := parser.FindObjectsWithMagicComments(root, contentBytes)
 			result.ObjectsFound = len(objects)
 
 			// Count how many need sorting
 			for _, obj := range objects {
-				_, needsSort := sortObjectAST(obj, contentBytes)
+				_, needsSort := sortSortable(obj, contentBytes)
 				if needsSort {
 					result.ObjectsNeedSort++
 				}
@@ -315,10 +318,10 @@ const b = {
 
 				// Sort from end to beginning
 				for i := len(objects) - 1; i >= 0; i-- {
-					sortedContent, needsSort := sortObjectAST(objects[i], newContent)
+					sortedContent, needsSort := sortSortable(objects[i], newContent)
 					if needsSort {
-						start := objects[i].object.StartByte()
-						end := objects[i].object.EndByte()
+						start := objects[i].GetNode().StartByte()
+						end := objects[i].GetNode().EndByte()
 
 						before := newContent[:start]
 						after := newContent[end:]
@@ -388,20 +391,24 @@ func TestExtractKeyAST(t *testing.T) {
 				t.Fatalf("Failed to parse: %v", err)
 			}
 
-			objects := findObjectsWithMagicCommentsAST(root, contentBytes)
-			if len(objects) != 1 {
-				t.Fatalf("Expected 1 object, got %d", len(objects))
+			found := parser.FindObjectsWithMagicComments(root, contentBytes)
+			if len(found) != 1 {
+				t.Fatalf("Expected 1 object, got %d", len(found))
 			}
 
-			properties := extractPropertiesAST(objects[0], contentBytes)
+			properties, err := found[0].Extract(found[0].GetNode(), contentBytes)
+			if err != nil {
+				t.Fatalf("Failed to extract properties: %v", err)
+			}
 
 			if len(properties) != len(tt.want) {
 				t.Errorf("Got %d properties, want %d", len(properties), len(tt.want))
 			}
 
-			for i, prop := range properties {
-				if i < len(tt.want) && prop.key != tt.want[i] {
-					t.Errorf("Property %d: key = %q, want %q", i, prop.key, tt.want[i])
+			for i, item := range properties {
+				prop := item.(*objects.Property)
+				if i < len(tt.want) && prop.Key != tt.want[i] {
+					t.Errorf("Property %d: key = %q, want %q", i, prop.Key, tt.want[i])
 				}
 			}
 		})
@@ -547,13 +554,13 @@ const items = {
 				t.Fatalf("failed to parse: %v", err)
 			}
 
-			objects := findObjectsWithMagicCommentsAST(tree, content)
+			objects := parser.FindObjectsWithMagicComments(tree, content)
 			if len(objects) != 1 {
 				t.Fatalf("expected 1 object, got %d", len(objects))
 			}
 
 			obj := objects[0]
-			sortedContent, changed := sortObjectAST(obj, content)
+			sortedContent, changed := sortSortable(obj, content)
 
 			if tt.wantSorted == "" {
 				// Expecting no change
@@ -570,8 +577,8 @@ const items = {
 				newContent := make([]byte, len(content))
 				copy(newContent, content)
 
-				start := obj.object.StartByte()
-				end := obj.object.EndByte()
+				start := obj.GetNode().StartByte()
+				end := obj.GetNode().EndByte()
 
 				before := newContent[:start]
 				after := newContent[end:]
@@ -2,6 +2,8 @@ package processor
 
 import (
 	"testing"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/parser"
 )
 
 func BenchmarkProcessFileAST(b *testing.B) {
@@ -10,7 +12,7 @@ func BenchmarkProcessFileAST(b *testing.B) {
 		Write:      false,
 		Recursive:  true,
 		Extensions: []string{".ts", ".tsx"},
-		Path:       "../../testdata/fixtures/basic.ts",
+		Paths:      []string{"../../testdata/fixtures/basic.ts"},
 		Workers:    1,
 	}
 
@@ -19,21 +21,6 @@ func BenchmarkProcessFileAST(b *testing.B) {
 	}
 }
 
-func BenchmarkProcessFileSimple(b *testing.B) {
-	config := Config{
-		Check:      true,
-		Write:      false,
-		Recursive:  true,
-		Extensions: []string{".ts", ".tsx"},
-		Path:       "../../testdata/fixtures/basic.ts",
-		Workers:    1,
-	}
-
-	for i := 0; i < b.N; i++ {
-		processFileSimple("../../testdata/fixtures/basic.ts", config)
-	}
-}
-
 func BenchmarkSortObjectAST(b *testing.B) {
 	const testContent = `const config = {
   /** tree-sorter-ts: keep-sorted **/
@@ -47,7 +34,7 @@ func BenchmarkSortObjectAST(b *testing.B) {
 		b.Fatal(err)
 	}
 
-	objects := findObjectsWithMagicCommentsAST(rootNode, content)
+	objects := parser.FindObjectsWithMagicComments(rootNode, content)
 
 	if len(objects) == 0 {
 		b.Fatal("no objects found")
@@ -55,6 +42,6 @@ func BenchmarkSortObjectAST(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		sortObjectAST(objects[0], content)
+		sortSortable(objects[0], content)
 	}
 }
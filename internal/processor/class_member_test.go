@@ -0,0 +1,323 @@
+package processor
+
+import (
+	"testing"
+)
+
+func TestProcessContentASTClassMembers(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name: "fields and methods by name",
+			input: `class UserService {
+  /** tree-sorter-ts: keep-sorted **/
+  save(user: User) {}
+  private cache = new Map();
+  load(id: string) {}
+  baseUrl: string;
+}
+`,
+			expected: `class UserService {
+  /** tree-sorter-ts: keep-sorted **/
+  baseUrl: string;
+  private cache = new Map();
+  load(id: string) {}
+  save(user: User) {}
+}
+`,
+		},
+		{
+			name: "decorators and JSDoc move with their member",
+			input: `class APIClient {
+  /** tree-sorter-ts: keep-sorted **/
+  @observable()
+  isLoading: boolean;
+
+  /** The HTTP client */
+  @inject()
+  httpClient: HttpClient;
+
+  @HostListener("click")
+  onClick() {}
+
+  apiKey: string;
+}
+`,
+			expected: `class APIClient {
+  /** tree-sorter-ts: keep-sorted **/
+  apiKey: string;
+  /** The HTTP client */
+  @inject()
+  httpClient: HttpClient;
+  @observable()
+  isLoading: boolean;
+  @HostListener("click")
+  onClick() {}
+}
+`,
+		},
+		{
+			name: "overload signatures stay with their implementation",
+			input: `class Parser {
+  /** tree-sorter-ts: keep-sorted **/
+  parse(input: string): Node;
+  parse(input: Buffer): Node;
+  parse(input: string | Buffer): Node {
+    return toNode(input);
+  }
+  format(node: Node): string {
+    return String(node);
+  }
+}
+`,
+			expected: `class Parser {
+  /** tree-sorter-ts: keep-sorted **/
+  format(node: Node): string {
+    return String(node);
+  }
+  parse(input: string): Node;
+  parse(input: Buffer): Node;
+  parse(input: string | Buffer): Node {
+    return toNode(input);
+  }
+}
+`,
+		},
+		{
+			name: "getters and setters keep their order",
+			input: `class Box {
+  /** tree-sorter-ts: keep-sorted **/
+  set value(v: number) {
+    this.v = v;
+  }
+  get value(): number {
+    return this.v;
+  }
+  [key: string]: unknown;
+  clear() {}
+}
+`,
+			expected: `class Box {
+  /** tree-sorter-ts: keep-sorted **/
+  [key: string]: unknown;
+  clear() {}
+  set value(v: number) {
+    this.v = v;
+  }
+  get value(): number {
+    return this.v;
+  }
+}
+`,
+		},
+		{
+			name: "deprecated members at the end",
+			input: `class Api {
+  /** tree-sorter-ts: keep-sorted deprecated-at-end **/
+  /** @deprecated use fetch */
+  get() {}
+  @deprecated()
+  legacyEndpoint: string;
+  fetch() {}
+  endpoint: string;
+}
+`,
+			expected: `class Api {
+  /** tree-sorter-ts: keep-sorted deprecated-at-end **/
+  endpoint: string;
+  fetch() {}
+  /** @deprecated use fetch */
+  get() {}
+  @deprecated()
+  legacyEndpoint: string;
+}
+`,
+		},
+		{
+			name: "group by kind",
+			input: `class Store {
+  /** tree-sorter-ts: keep-sorted group-by-kind with-new-line **/
+  reset() {}
+  items: Item[] = [];
+  constructor(private readonly api: Api) {}
+  static instance: Store;
+  static create(): Store {
+    return new Store(api);
+  }
+  count = 0;
+  static version = 1;
+}
+`,
+			expected: `class Store {
+  /** tree-sorter-ts: keep-sorted group-by-kind with-new-line **/
+  static instance: Store;
+
+  static version = 1;
+
+  count = 0;
+
+  items: Item[] = [];
+
+  constructor(private readonly api: Api) {}
+
+  static create(): Store {
+    return new Store(api);
+  }
+
+  reset() {}
+}
+`,
+		},
+		{
+			name: "reads that only run later",
+			input: `class Button {
+  /** tree-sorter-ts: keep-sorted **/
+  static z = 1;
+  onClick = () => this.label;
+  label = "ok";
+  static a = () => Button.z;
+  render() {
+    return this.label;
+  }
+}
+`,
+			expected: `class Button {
+  /** tree-sorter-ts: keep-sorted **/
+  static a = () => Button.z;
+  label = "ok";
+  onClick = () => this.label;
+  render() {
+    return this.label;
+  }
+  static z = 1;
+}
+`,
+		},
+		{
+			name: "section of a class body",
+			input: `class Component {
+  constructor() {}
+
+  /** tree-sorter-ts: start-sort **/
+  onResize() {}
+  onClick() {} // handles clicks
+  /** tree-sorter-ts: end-sort **/
+
+  render() {}
+}
+`,
+			expected: `class Component {
+  constructor() {}
+
+  /** tree-sorter-ts: start-sort **/
+  onClick() {} // handles clicks
+  onResize() {}
+  /** tree-sorter-ts: end-sort **/
+
+  render() {}
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertSorted(t, "members.ts", tt.input, tt.expected, KindMembers)
+		})
+	}
+}
+
+func TestProcessContentASTUnsafeClassMembers(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		error string
+	}{
+		{
+			name: "field initializer reading another sorted field",
+			input: `class Counter {
+  /** tree-sorter-ts: keep-sorted **/
+  b = this.a + 1;
+  a = 1;
+}
+`,
+			error: `field "b" uses field "a"`,
+		},
+		{
+			name: "private field reading a field declared before it",
+			input: `class Counter {
+  /** tree-sorter-ts: keep-sorted **/
+  old = 1;
+  private b = this.old + 1;
+}
+`,
+			error: `field "b" uses field "old"`,
+		},
+		{
+			name: "static field reading another static field through the class",
+			input: `class Config {
+  /** tree-sorter-ts: keep-sorted **/
+  static z = 1;
+  static a = Config.z * 2;
+}
+`,
+			error: `field "a" uses field "z"`,
+		},
+		{
+			name: "static block",
+			input: `class K {
+  /** tree-sorter-ts: keep-sorted group-by-kind **/
+  static z = 1;
+  static { K.a = K.z + 1; }
+  static a: number;
+}
+`,
+			error: "static block at line 4",
+		},
+		{
+			name: "empty member",
+			input: `class K {
+  /** tree-sorter-ts: keep-sorted **/
+  z = 1;
+  ;
+  a = 2;
+}
+`,
+			error: "empty member at line 4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertRefused(t, "members.ts", tt.input, ErrUnsafeClassMembers, tt.error)
+		})
+	}
+}
+
+func TestProcessContentASTGroupByKindOnlyForClasses(t *testing.T) {
+	input := `const config = {
+  /** tree-sorter-ts: keep-sorted **/
+  b: 2, // first
+  a: 1, // second
+};
+`
+	result, err := ProcessContentAST("members.ts", []byte(input), Config{
+		SortDefaults: SortConfig{GroupByKind: true, SortByComment: true},
+	})
+	if err != nil {
+		t.Fatalf("ProcessContentAST failed: %v", err)
+	}
+	if len(result.Structures) != 1 {
+		t.Fatalf("Expected 1 structure, got %d", len(result.Structures))
+	}
+	structure := result.Structures[0]
+	if structure.Options.GroupByKind || structure.Strategy != "comment-content" {
+		t.Errorf("Expected group-by-kind to be dropped for objects, got %+v with strategy %q", structure.Options, structure.Strategy)
+	}
+	if result.Changed {
+		t.Errorf("Expected object sorted by comment to be left alone:\n%s", string(result.Sorted))
+	}
+}
@@ -3,6 +3,8 @@ package processor
 import (
 	"strings"
 	"testing"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/parser"
 )
 
 func TestParseSortConfigDeprecated(t *testing.T) {
@@ -85,12 +87,12 @@ func TestParseSortConfigDeprecated(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := parseSortConfig([]byte(tt.comment))
+			got := parser.ParseMagicComment([]byte(tt.comment))
 			if got.WithNewLine != tt.want.WithNewLine {
-				t.Errorf("parseSortConfig() WithNewLine = %v, want %v", got.WithNewLine, tt.want.WithNewLine)
+				t.Errorf("ParseMagicComment() WithNewLine = %v, want %v", got.WithNewLine, tt.want.WithNewLine)
 			}
 			if got.DeprecatedAtEnd != tt.want.DeprecatedAtEnd {
-				t.Errorf("parseSortConfig() DeprecatedAtEnd = %v, want %v", got.DeprecatedAtEnd, tt.want.DeprecatedAtEnd)
+				t.Errorf("ParseMagicComment() DeprecatedAtEnd = %v, want %v", got.DeprecatedAtEnd, tt.want.DeprecatedAtEnd)
 			}
 		})
 	}
@@ -328,12 +330,12 @@ func TestDeprecatedAtEnd(t *testing.T) {
 				t.Fatalf("Failed to parse: %v", err)
 			}
 
-			objects := findObjectsWithMagicCommentsAST(root, contentBytes)
+			objects := parser.FindObjectsWithMagicComments(root, contentBytes)
 			result.ObjectsFound = len(objects)
 
 			// Count how many need sorting
 			for _, obj := range objects {
-				_, needsSort := sortObjectAST(obj, contentBytes)
+				_, needsSort := sortSortable(obj, contentBytes)
 				if needsSort {
 					result.ObjectsNeedSort++
 				}
@@ -351,10 +353,10 @@ func TestDeprecatedAtEnd(t *testing.T) {
 
 				// Sort from end to beginning
 				for i := len(objects) - 1; i >= 0; i-- {
-					sortedContent, needsSort := sortObjectAST(objects[i], newContent)
+					sortedContent, needsSort := sortSortable(objects[i], newContent)
 					if needsSort {
-						start := objects[i].object.StartByte()
-						end := objects[i].object.EndByte()
+						start := objects[i].GetNode().StartByte()
+						end := objects[i].GetNode().EndByte()
 
 						before := newContent[:start]
 						after := newContent[end:]
@@ -0,0 +1,120 @@
+package processor
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+)
+
+// TextEdit replaces content[StartByte:EndByte] with NewText. The points are
+// positions in the content before any edit is applied.
+type TextEdit struct {
+	StartByte  int
+	EndByte    int
+	StartPoint Position
+	EndPoint   Position
+	NewText    string
+}
+
+// OverlapError reports edits that cannot be applied together because they
+// touch the same bytes, or insert at the same offset
+type OverlapError struct {
+	First  TextEdit
+	Second TextEdit
+}
+
+func (e *OverlapError) Error() string {
+	return fmt.Sprintf("overlapping edits at line %d, column %d and line %d, column %d",
+		e.First.StartPoint.Line, e.First.StartPoint.Column, e.Second.StartPoint.Line, e.Second.StartPoint.Column)
+}
+
+// ApplyEdits returns content with the edits applied; content itself is not
+// modified. Edits may be given in any order but must not overlap.
+func ApplyEdits(content []byte, edits []TextEdit) ([]byte, error) {
+	ordered := append([]TextEdit(nil), edits...)
+	SortEdits(ordered)
+
+	size := len(content)
+	for i, edit := range ordered {
+		if edit.StartByte < 0 || edit.StartByte > edit.EndByte || edit.EndByte > len(content) {
+			return nil, fmt.Errorf("edit %d-%d is outside the content (%d bytes)", edit.StartByte, edit.EndByte, len(content))
+		}
+		if i > 0 {
+			prev := ordered[i-1]
+			bothInsert := prev.StartByte == prev.EndByte && edit.StartByte == edit.EndByte
+			if prev.EndByte > edit.StartByte || (bothInsert && prev.StartByte == edit.StartByte) {
+				return nil, &OverlapError{First: prev, Second: edit}
+			}
+		}
+		size += len(edit.NewText) - (edit.EndByte - edit.StartByte)
+	}
+
+	out := make([]byte, 0, size)
+	last := 0
+	for _, edit := range ordered {
+		out = append(out, content[last:edit.StartByte]...)
+		out = append(out, edit.NewText...)
+		last = edit.EndByte
+	}
+	return append(out, content[last:]...), nil
+}
+
+// SortEdits orders edits by position, insertions before replacements
+// starting at the same offset
+func SortEdits(edits []TextEdit) {
+	sort.SliceStable(edits, func(i, j int) bool {
+		if edits[i].StartByte != edits[j].StartByte {
+			return edits[i].StartByte < edits[j].StartByte
+		}
+		return edits[i].EndByte < edits[j].EndByte
+	})
+}
+
+// minimalEdits returns the edits turning content[start:end] into
+// replacement, trimmed to the bytes that differ without splitting a character
+func minimalEdits(content []byte, start, end int, replacement []byte) []TextEdit {
+	original := content[start:end]
+	if bytes.Equal(original, replacement) {
+		return nil
+	}
+
+	prefix := 0
+	for prefix < len(original) && prefix < len(replacement) && original[prefix] == replacement[prefix] {
+		prefix++
+	}
+	for prefix < len(original) && !utf8.RuneStart(original[prefix]) {
+		prefix--
+	}
+
+	suffix := 0
+	for suffix < len(original)-prefix && suffix < len(replacement)-prefix &&
+		original[len(original)-1-suffix] == replacement[len(replacement)-1-suffix] {
+		suffix++
+	}
+	for suffix > 0 && !utf8.RuneStart(original[len(original)-suffix]) {
+		suffix--
+	}
+
+	return []TextEdit{newTextEdit(content, start+prefix, end-suffix, string(replacement[prefix:len(replacement)-suffix]))}
+}
+
+func newTextEdit(content []byte, start, end int, newText string) TextEdit {
+	return TextEdit{
+		StartByte:  start,
+		EndByte:    end,
+		StartPoint: positionAtByte(content, start),
+		EndPoint:   positionAtByte(content, end),
+		NewText:    newText,
+	}
+}
+
+// positionAtByte converts a byte offset into a Position whose column counts
+// characters
+func positionAtByte(content []byte, offset int) Position {
+	lineStart := bytes.LastIndexByte(content[:offset], '\n') + 1
+	return Position{
+		Line:   bytes.Count(content[:lineStart], []byte("\n")) + 1,
+		Column: utf8.RuneCount(content[lineStart:offset]) + 1,
+	}
+}
@@ -0,0 +1,114 @@
+package processor
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestApplyEdits(t *testing.T) {
+	content := []byte("héllo\nworld\n")
+
+	tests := []struct {
+		name    string
+		edits   []TextEdit
+		want    string
+		overlap bool
+		wantErr bool
+	}{
+		{
+			name: "no edits",
+			want: "héllo\nworld\n",
+		},
+		{
+			name: "out of order",
+			edits: []TextEdit{
+				{StartByte: 7, EndByte: 12, NewText: "there"},
+				{StartByte: 0, EndByte: 1, NewText: "j"},
+			},
+			want: "jéllo\nthere\n",
+		},
+		{
+			name: "insertion before replacement at the same offset",
+			edits: []TextEdit{
+				{StartByte: 7, EndByte: 12, NewText: "there"},
+				{StartByte: 7, EndByte: 7, NewText: "hi "},
+			},
+			want: "héllo\nhi there\n",
+		},
+		{
+			name: "adjacent",
+			edits: []TextEdit{
+				{StartByte: 0, EndByte: 6, NewText: "a"},
+				{StartByte: 6, EndByte: 7, NewText: "b"},
+			},
+			want: "abworld\n",
+		},
+		{
+			name: "overlapping",
+			edits: []TextEdit{
+				{StartByte: 0, EndByte: 8, NewText: "a"},
+				{StartByte: 7, EndByte: 9, NewText: "b"},
+			},
+			overlap: true,
+		},
+		{
+			name: "two insertions at the same offset",
+			edits: []TextEdit{
+				{StartByte: 3, EndByte: 3, NewText: "a"},
+				{StartByte: 3, EndByte: 3, NewText: "b"},
+			},
+			overlap: true,
+		},
+		{
+			name:    "out of bounds",
+			edits:   []TextEdit{{StartByte: 10, EndByte: 20, NewText: "a"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyEdits(content, tt.edits)
+			var overlapErr *OverlapError
+			switch {
+			case tt.overlap:
+				if !errors.As(err, &overlapErr) {
+					t.Errorf("expected an OverlapError, got %v", err)
+				}
+			case tt.wantErr:
+				if err == nil {
+					t.Errorf("expected an error, got %q", got)
+				}
+			case err != nil:
+				t.Fatalf("Failed to apply edits: %v", err)
+			case string(got) != tt.want:
+				t.Errorf("ApplyEdits() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if string(content) != "héllo\nworld\n" {
+		t.Errorf("ApplyEdits modified its input: %q", content)
+	}
+}
+
+func TestMinimalEdits(t *testing.T) {
+	content := []byte("x = [\n  \"é\",\n  \"è\",\n];\n")
+
+	// Only the differing characters are replaced, without splitting them
+	edits := minimalEdits(content, 4, 23, []byte("[\n  \"è\",\n  \"é\",\n]"))
+	if len(edits) != 1 {
+		t.Fatalf("got %d edits, want 1", len(edits))
+	}
+	edit := edits[0]
+	if edit.StartByte != 9 || edit.EndByte != 19 || edit.NewText != "è\",\n  \"é" {
+		t.Errorf("unexpected edit: %+v", edit)
+	}
+	if edit.StartPoint != (Position{Line: 2, Column: 4}) || edit.EndPoint != (Position{Line: 3, Column: 5}) {
+		t.Errorf("unexpected edit points: %+v %+v", edit.StartPoint, edit.EndPoint)
+	}
+
+	if edits := minimalEdits(content, 4, 23, content[4:23]); len(edits) != 0 {
+		t.Errorf("expected no edits for unchanged text, got %+v", edits)
+	}
+}
@@ -0,0 +1,249 @@
+package processor
+
+import (
+	"testing"
+)
+
+func TestProcessContentASTEnums(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name: "string enum by name",
+			input: `enum Color {
+  /** tree-sorter-ts: keep-sorted **/
+  Red = "red",
+  // The color of grass
+  Green = "green",
+  Blue = "blue", // the sky
+}
+`,
+			expected: `enum Color {
+  /** tree-sorter-ts: keep-sorted **/
+  Blue = "blue", // the sky
+  // The color of grass
+  Green = "green",
+  Red = "red",
+}
+`,
+		},
+		{
+			name: "numeric enum by value",
+			input: `enum Level {
+  /** tree-sorter-ts: keep-sorted sort-by-value **/
+  High = 30,
+  Low = 0x0a,
+  Off = -1,
+  Mid = 20
+}
+`,
+			expected: `enum Level {
+  /** tree-sorter-ts: keep-sorted sort-by-value **/
+  Off = -1,
+  Low = 0x0a,
+  Mid = 20,
+  High = 30
+}
+`,
+		},
+		{
+			name: "string enum by value",
+			input: `enum Method {
+  /** tree-sorter-ts: keep-sorted sort-by-value **/
+  Post = "POST",
+  Delete = "DELETE",
+  Get = "GET",
+}
+`,
+			expected: `enum Method {
+  /** tree-sorter-ts: keep-sorted sort-by-value **/
+  Delete = "DELETE",
+  Get = "GET",
+  Post = "POST",
+}
+`,
+		},
+		{
+			name: "materialized implicit values",
+			input: `enum Direction {
+  /** tree-sorter-ts: keep-sorted materialize-values **/
+  Up = 1,
+  Down,
+  Left,
+  Right,
+}
+`,
+			expected: `enum Direction {
+  /** tree-sorter-ts: keep-sorted materialize-values **/
+  Down = 2,
+  Left = 3,
+  Right = 4,
+  Up = 1,
+}
+`,
+		},
+		{
+			name: "materialized references to other members",
+			input: `const enum Flags {
+  /** tree-sorter-ts: keep-sorted materialize-values **/
+  Write = 1 << 1,
+  Read = 1 << 0,
+  All = Read | Flags.Write,
+}
+`,
+			expected: `const enum Flags {
+  /** tree-sorter-ts: keep-sorted materialize-values **/
+  All = 3,
+  Read = 1 << 0,
+  Write = 1 << 1,
+}
+`,
+		},
+		{
+			name: "materialized strings with characters that need escapes",
+			input: "enum Text {\n" +
+				"  /** tree-sorter-ts: keep-sorted materialize-values **/\n" +
+				"  Raw = \"a\ab\tc\U000E0001\",\n" +
+				"  Copy = Raw,\n" +
+				"}\n",
+			expected: "enum Text {\n" +
+				"  /** tree-sorter-ts: keep-sorted materialize-values **/\n" +
+				"  Copy = \"a\\u0007b\\tc\\udb40\\udc01\",\n" +
+				"  Raw = \"a\ab\tc\U000E0001\",\n" +
+				"}\n",
+		},
+		{
+			name: "deprecated members at the end",
+			input: `enum Status {
+  /** tree-sorter-ts: keep-sorted deprecated-at-end **/
+  /** @deprecated use Inactive */
+  Disabled = "disabled",
+  Inactive = "inactive",
+  Active = "active",
+}
+`,
+			expected: `enum Status {
+  /** tree-sorter-ts: keep-sorted deprecated-at-end **/
+  Active = "active",
+  Inactive = "inactive",
+  /** @deprecated use Inactive */
+  Disabled = "disabled",
+}
+`,
+		},
+		{
+			name: "section of an enum",
+			input: `enum Code {
+  Unknown = 0,
+  /** tree-sorter-ts: start-sort **/
+  NotFound = 404,
+  BadRequest = 400,
+  /** tree-sorter-ts: end-sort **/
+  Internal = 500,
+}
+`,
+			expected: `enum Code {
+  Unknown = 0,
+  /** tree-sorter-ts: start-sort **/
+  BadRequest = 400,
+  NotFound = 404,
+  /** tree-sorter-ts: end-sort **/
+  Internal = 500,
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertSorted(t, "enums.ts", tt.input, tt.expected, KindEnumMembers)
+		})
+	}
+}
+
+func TestProcessContentASTUnsafeEnums(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		error string
+	}{
+		{
+			name: "implicit values",
+			input: `enum Direction {
+  /** tree-sorter-ts: keep-sorted **/
+  Up,
+  Down,
+}
+`,
+			error: `enum member "Up" has an implicit value`,
+		},
+		{
+			name: "reference to another sorted member",
+			input: `const enum Flags {
+  /** tree-sorter-ts: keep-sorted **/
+  Write = 2,
+  Read = 1,
+  All = Read | Write,
+}
+`,
+			error: `enum member "All" refers to member "Read"`,
+		},
+		{
+			name: "value that cannot be written out",
+			input: `enum Sizes {
+  /** tree-sorter-ts: keep-sorted materialize-values **/
+  Large = compute(),
+  Small,
+}
+`,
+			error: `enum member "Small" has an implicit value that cannot be written out`,
+		},
+		{
+			name: "implicit value after a section",
+			input: `enum Code {
+  /** tree-sorter-ts: start-sort **/
+  NotFound = 404,
+  BadRequest = 400,
+  /** tree-sorter-ts: end-sort **/
+  Next,
+}
+`,
+			error: `enum member "Next" after the section has an implicit value`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertRefused(t, "enums.ts", tt.input, ErrUnsafeEnum, tt.error)
+		})
+	}
+}
+
+func TestProcessContentASTEnumValuesOnlyForEnums(t *testing.T) {
+	input := `const sizes = {
+  /** tree-sorter-ts: keep-sorted **/
+  small: 1,
+  large: 3,
+};
+`
+	result, err := ProcessContentAST("enums.ts", []byte(input), Config{
+		SortDefaults: SortConfig{SortByValue: true},
+	})
+	if err != nil {
+		t.Fatalf("ProcessContentAST failed: %v", err)
+	}
+	if len(result.Structures) != 1 || result.Structures[0].Options.SortByValue {
+		t.Fatalf("Expected sort-by-value to be dropped for objects, got %+v", result.Structures)
+	}
+	expected := `const sizes = {
+  /** tree-sorter-ts: keep-sorted **/
+  large: 3,
+  small: 1,
+};
+`
+	if string(result.Sorted) != expected {
+		t.Errorf("Expected object to be sorted by name:\n%s", string(result.Sorted))
+	}
+}
@@ -0,0 +1,82 @@
+package processor
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/typescript/tsx"
+	"github.com/smacker/go-tree-sitter/typescript/typescript"
+)
+
+// Language identifies the tree-sitter grammar used to parse a file
+type Language int
+
+const (
+	// LanguageTypeScript is the plain TypeScript grammar (.ts, .mts, .cts)
+	LanguageTypeScript Language = iota
+	// LanguageTSX is the TypeScript grammar with JSX support (.tsx)
+	LanguageTSX
+)
+
+// LanguageForPath selects the grammar for a file based on its extension.
+// Anything that is not a .tsx file is parsed as plain TypeScript, since the
+// TSX grammar rejects angle-bracket type assertions like `<T>value`.
+func LanguageForPath(path string) Language {
+	if strings.EqualFold(filepath.Ext(path), ".tsx") {
+		return LanguageTSX
+	}
+	return LanguageTypeScript
+}
+
+// String returns the human readable name of the language
+func (l Language) String() string {
+	switch l {
+	case LanguageTSX:
+		return "tsx"
+	default:
+		return "typescript"
+	}
+}
+
+// grammar returns the tree-sitter language definition for l
+func (l Language) grammar() *sitter.Language {
+	switch l {
+	case LanguageTSX:
+		return tsx.GetLanguage()
+	default:
+		return typescript.GetLanguage()
+	}
+}
+
+// parserPools avoid recreating parsers, one per grammar
+var parserPools = map[Language]*sync.Pool{
+	LanguageTypeScript: newParserPool(LanguageTypeScript),
+	LanguageTSX:        newParserPool(LanguageTSX),
+}
+
+// newParser creates a parser configured for the given language
+func newParser(lang Language) *sitter.Parser {
+	parser := sitter.NewParser()
+	parser.SetLanguage(lang.grammar())
+	return parser
+}
+
+func newParserPool(lang Language) *sync.Pool {
+	return &sync.Pool{
+		New: func() interface{} {
+			return newParser(lang)
+		},
+	}
+}
+
+// getParser takes a parser for the given language from its pool
+func getParser(lang Language) *sitter.Parser {
+	return parserPools[lang].Get().(*sitter.Parser)
+}
+
+// putParser returns a parser obtained from getParser to its pool
+func putParser(lang Language, parser *sitter.Parser) {
+	parserPools[lang].Put(parser)
+}
@@ -0,0 +1,64 @@
+package processor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// LineRange is an inclusive range of 1-based line numbers
+type LineRange struct {
+	Start int
+	End   int
+}
+
+// ParseLineRange parses "start-end" or a single line number
+func ParseLineRange(s string) (LineRange, error) {
+	startText, endText, found := strings.Cut(s, "-")
+	if !found {
+		endText = startText
+	}
+
+	start, err := strconv.Atoi(strings.TrimSpace(startText))
+	if err != nil || start < 1 {
+		return LineRange{}, fmt.Errorf("invalid line range %q: start must be a positive line number", s)
+	}
+	end, err := strconv.Atoi(strings.TrimSpace(endText))
+	if err != nil || end < start {
+		return LineRange{}, fmt.Errorf("invalid line range %q: end must be a line number not before start", s)
+	}
+	return LineRange{Start: start, End: end}, nil
+}
+
+// overlapsLines reports whether the lines from the start of first to the end
+// of last overlap one of the ranges
+func overlapsLines(first, last *sitter.Node, ranges []LineRange) bool {
+	start := int(first.StartPoint().Row) + 1
+	end := int(last.EndPoint().Row) + 1
+
+	for _, r := range ranges {
+		if r.Start <= end && start <= r.End {
+			return true
+		}
+	}
+	return false
+}
+
+// withinLines keeps the structures overlapping the ranges. A nil ranges slice
+// means the whole file is in scope.
+func withinLines[T any](structures []T, ranges []LineRange, rangeOf func(T) (first, last *sitter.Node)) []T {
+	if ranges == nil {
+		return structures
+	}
+
+	kept := structures[:0:0]
+	for _, s := range structures {
+		first, last := rangeOf(s)
+		if overlapsLines(first, last, ranges) {
+			kept = append(kept, s)
+		}
+	}
+	return kept
+}
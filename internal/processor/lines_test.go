@@ -0,0 +1,107 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLineRange(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    LineRange
+		wantErr bool
+	}{
+		{input: "3-7", want: LineRange{Start: 3, End: 7}},
+		{input: "12", want: LineRange{Start: 12, End: 12}},
+		{input: "5-5", want: LineRange{Start: 5, End: 5}},
+		{input: "0-3", wantErr: true},
+		{input: "7-3", wantErr: true},
+		{input: "a-b", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseLineRange(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseLineRange(%q) = %+v, want an error", tt.input, got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ParseLineRange(%q) = %+v, %v; want %+v", tt.input, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessContentASTLines(t *testing.T) {
+	input := `const first = {
+  /** tree-sorter-ts: keep-sorted **/
+  b: 1,
+  a: 2,
+};
+
+const second = [
+  /** tree-sorter-ts: keep-sorted **/
+  "d",
+  "c",
+];
+
+class Service {
+  constructor(
+    /** tree-sorter-ts: keep-sorted **/
+    private readonly z: string,
+    private readonly y: string,
+  ) {}
+}
+`
+
+	tests := []struct {
+		name      string
+		lines     []LineRange
+		wantFound int
+		wantFirst bool // first object sorted in the output
+		wantArray bool // array sorted in the output
+	}{
+		{name: "whole_file", lines: nil, wantFound: 3, wantFirst: true, wantArray: true},
+		{name: "no_lines", lines: []LineRange{}, wantFound: 0},
+		{name: "inside_object", lines: []LineRange{{Start: 4, End: 4}}, wantFound: 1, wantFirst: true},
+		{name: "magic_comment_line", lines: []LineRange{{Start: 8, End: 8}}, wantFound: 1, wantArray: true},
+		{name: "closing_bracket", lines: []LineRange{{Start: 11, End: 12}}, wantFound: 1, wantArray: true},
+		{name: "between_structures", lines: []LineRange{{Start: 6, End: 6}}, wantFound: 0},
+		{name: "constructor", lines: []LineRange{{Start: 17, End: 17}}, wantFound: 1},
+		{name: "spanning", lines: []LineRange{{Start: 1, End: 9}}, wantFound: 2, wantFirst: true, wantArray: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ProcessContentAST("scoped.ts", []byte(input), Config{Lines: tt.lines})
+			if err != nil {
+				t.Fatalf("Failed to process content: %v", err)
+			}
+
+			if result.ObjectsFound != tt.wantFound {
+				t.Errorf("ObjectsFound = %d, want %d", result.ObjectsFound, tt.wantFound)
+			}
+			if len(result.Structures) != tt.wantFound {
+				t.Errorf("got %d structures, want %d", len(result.Structures), tt.wantFound)
+			}
+			if result.ObjectsNeedSort != tt.wantFound {
+				t.Errorf("ObjectsNeedSort = %d, want %d", result.ObjectsNeedSort, tt.wantFound)
+			}
+
+			output := string(result.Sorted)
+			if !result.Changed {
+				output = input
+			}
+			if got := strings.Index(output, "a: 2") < strings.Index(output, "b: 1"); got != tt.wantFirst {
+				t.Errorf("first object sorted = %v, want %v", got, tt.wantFirst)
+			}
+			if got := strings.Index(output, `"c"`) < strings.Index(output, `"d"`); got != tt.wantArray {
+				t.Errorf("array sorted = %v, want %v", got, tt.wantArray)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/interfaces"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// pendingStructure is a structure whose sorted form is not known yet, with
+// its byte range in the content of the current round
+type pendingStructure struct {
+	structure  *StructureResult
+	start, end int
+}
+
+// sortNested sets the edits of the structures that need sorting. A container
+// moves the structures nested in it verbatim, so structures are sorted
+// innermost first: each round sorts the structures that changed and contain
+// no other changed structure, applies their edits and parses the result
+// again, so that their containers are sorted in a later round against
+// content that already carries the inner sorts. The edits of every
+// structure are relative to the original content and include the sorts of
+// the structures nested in it.
+func (p *Processor) sortNested(tsParser *sitter.Parser, content []byte, sortables []interfaces.Sortable, structureAt map[structureKey]*StructureResult) error {
+	pending := make(map[structureKey]*pendingStructure, len(sortables))
+	for _, s := range sortables {
+		key := keyOf(s)
+		structure := structureAt[key]
+		if structure.Options.HasError {
+			continue
+		}
+		pending[key] = &pendingStructure{structure: structure, start: structure.StartByte, end: structure.EndByte}
+	}
+
+	type change struct {
+		pending *pendingStructure
+		sorted  []byte
+	}
+
+	current := content
+	for len(pending) > 0 {
+		var changes []change
+		for _, s := range sortables {
+			ps := pending[keyOf(s)]
+			if ps == nil {
+				continue
+			}
+			sorted, changed, err := p.sortStructure(s, ps.structure.Options, current)
+			if err != nil {
+				return err
+			}
+			if changed && !bytes.Equal(sorted, current[ps.start:ps.end]) {
+				changes = append(changes, change{pending: ps, sorted: sorted})
+			}
+		}
+
+		contains := func(ps *pendingStructure, other *pendingStructure) bool {
+			return ps != other && ps.start <= other.start && other.end <= ps.end
+		}
+		containsChange := func(ps *pendingStructure) bool {
+			for _, c := range changes {
+				if contains(ps, c.pending) {
+					return true
+				}
+			}
+			return false
+		}
+
+		// Structures that are sorted and contain no change stay as they are
+		for key, ps := range pending {
+			if !containsChange(ps) {
+				delete(pending, key)
+			}
+		}
+
+		var edits []TextEdit
+		for _, c := range changes {
+			if containsChange(c.pending) {
+				continue
+			}
+			structure := c.pending.structure
+			structure.Sorted = false
+			structure.Edits = minimalEdits(content, structure.StartByte, structure.EndByte, c.sorted)
+			edits = append(edits, minimalEdits(current, c.pending.start, c.pending.end, c.sorted)...)
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+
+		next, err := ApplyEdits(current, edits)
+		if err != nil {
+			return err
+		}
+		moved := make(map[structureKey]*pendingStructure, len(pending))
+		for _, ps := range pending {
+			ps.start = shiftedOffset(ps.start, edits)
+			ps.end = shiftedOffset(ps.end, edits)
+			moved[structureKey{start: ps.start, end: ps.end, kind: ps.structure.Kind}] = ps
+		}
+		pending = moved
+		current = next
+
+		tree, err := tsParser.ParseCtx(context.Background(), nil, current)
+		if err != nil {
+			return fmt.Errorf("parsing nested sort result: %w", err)
+		}
+		sortables = findSortables(tree.RootNode(), current)
+	}
+	return nil
+}
+
+// shiftedOffset maps an offset outside of the edits to the content with the
+// edits applied
+func shiftedOffset(offset int, edits []TextEdit) int {
+	shifted := offset
+	for _, edit := range edits {
+		if edit.EndByte <= offset {
+			shifted += len(edit.NewText) - (edit.EndByte - edit.StartByte)
+		}
+	}
+	return shifted
+}
+
+// outermostEdits returns the edits of the structures that are not nested in
+// another changed structure, whose edits already include theirs
+func outermostEdits(structures []StructureResult) []TextEdit {
+	var edits []TextEdit
+	for i, s := range structures {
+		if len(s.Edits) == 0 {
+			continue
+		}
+		nested := false
+		for j, other := range structures {
+			if i != j && len(other.Edits) > 0 && other.StartByte <= s.StartByte && s.EndByte <= other.EndByte {
+				nested = true
+				break
+			}
+		}
+		if !nested {
+			edits = append(edits, s.Edits...)
+		}
+	}
+	SortEdits(edits)
+	return edits
+}
@@ -0,0 +1,190 @@
+package processor
+
+import (
+	"testing"
+)
+
+func TestProcessContentASTNestedStructures(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		needSort int
+	}{
+		{
+			name: "object in array",
+			input: `const routes = [
+  /** tree-sorter-ts: keep-sorted key="path" **/
+  {
+    /** tree-sorter-ts: keep-sorted **/
+    path: "/users",
+    handler: listUsers,
+  },
+  { path: "/teams", handler: listTeams },
+];
+`,
+			expected: `const routes = [
+  /** tree-sorter-ts: keep-sorted key="path" **/
+  { path: "/teams", handler: listTeams },
+  {
+    /** tree-sorter-ts: keep-sorted **/
+    handler: listUsers,
+    path: "/users",
+  },
+];
+`,
+			needSort: 2,
+		},
+		{
+			name: "array in object",
+			input: `const config = {
+  /** tree-sorter-ts: keep-sorted **/
+  zebra: true,
+  tags: [
+    /** tree-sorter-ts: keep-sorted **/
+    "beta",
+    "alpha",
+  ],
+  apple: 1,
+};
+`,
+			expected: `const config = {
+  /** tree-sorter-ts: keep-sorted **/
+  apple: 1,
+  tags: [
+    /** tree-sorter-ts: keep-sorted **/
+    "alpha",
+    "beta",
+  ],
+  zebra: true,
+};
+`,
+			needSort: 2,
+		},
+		{
+			name: "parameters with object defaults",
+			input: `class Client {
+  constructor(
+    /** tree-sorter-ts: keep-sorted **/
+    private timeouts = {
+      /** tree-sorter-ts: keep-sorted **/
+      write: 10,
+      read: 5,
+    },
+    private endpoint = "/",
+  ) {}
+}
+`,
+			expected: `class Client {
+  constructor(
+    /** tree-sorter-ts: keep-sorted **/
+    private endpoint = "/",
+    private timeouts = {
+      /** tree-sorter-ts: keep-sorted **/
+      read: 5,
+      write: 10,
+    },
+  ) {}
+}
+`,
+			needSort: 2,
+		},
+		{
+			name: "only the inner structure needs sorting",
+			input: `const config = {
+  /** tree-sorter-ts: keep-sorted **/
+  apple: 1,
+  tags: [
+    /** tree-sorter-ts: keep-sorted **/
+    "beta",
+    "alpha",
+  ],
+};
+`,
+			expected: `const config = {
+  /** tree-sorter-ts: keep-sorted **/
+  apple: 1,
+  tags: [
+    /** tree-sorter-ts: keep-sorted **/
+    "alpha",
+    "beta",
+  ],
+};
+`,
+			needSort: 1,
+		},
+		{
+			name: "outer order depends on the sorted inner text",
+			input: `const matrix = [
+  /** tree-sorter-ts: keep-sorted **/
+  [
+    /** tree-sorter-ts: keep-sorted **/
+    "b",
+  ],
+  [
+    /** tree-sorter-ts: keep-sorted **/
+    "c",
+    "a",
+  ],
+];
+`,
+			expected: `const matrix = [
+  /** tree-sorter-ts: keep-sorted **/
+  [
+    /** tree-sorter-ts: keep-sorted **/
+    "a",
+    "c",
+  ],
+  [
+    /** tree-sorter-ts: keep-sorted **/
+    "b",
+  ],
+];
+`,
+			needSort: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ProcessContentAST("nested.ts", []byte(tt.input), Config{})
+			if err != nil {
+				t.Fatalf("ProcessContentAST failed: %v", err)
+			}
+			if result.ObjectsNeedSort != tt.needSort {
+				t.Errorf("ObjectsNeedSort = %d, want %d", result.ObjectsNeedSort, tt.needSort)
+			}
+			if string(result.Sorted) != tt.expected {
+				t.Errorf("Expected:\n%s\nGot:\n%s", tt.expected, string(result.Sorted))
+			}
+
+			// Each structure's edits sort it together with what is nested in it
+			for _, structure := range result.Structures {
+				if len(structure.Edits) == 0 {
+					continue
+				}
+				spliced, err := ApplyEdits([]byte(tt.input), structure.Edits)
+				if err != nil {
+					t.Fatalf("Failed to apply structure edits: %v", err)
+				}
+				again, err := ProcessContentAST("nested.ts", spliced, Config{})
+				if err != nil {
+					t.Fatalf("ProcessContentAST failed on spliced content: %v", err)
+				}
+				for _, s := range again.Structures {
+					if s.StartByte == structure.StartByte && !s.Sorted {
+						t.Errorf("structure at line %d is not sorted by its own edits:\n%s", structure.Start.Line, spliced)
+					}
+				}
+			}
+
+			again, err := ProcessContentAST("nested.ts", result.Sorted, Config{})
+			if err != nil {
+				t.Fatalf("ProcessContentAST failed on sorted content: %v", err)
+			}
+			if again.Changed {
+				t.Errorf("sorted content is not stable:\n%s", again.Sorted)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package processor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseErrorGate(t *testing.T) {
+	tests := []struct {
+		name             string
+		input            string
+		allowParseErrors bool
+		want             string
+		wantChanged      bool
+		wantSkipped      int
+		wantErrLine      int // 0 means no error expected
+		wantErrColumn    int
+	}{
+		{
+			name: "error_outside_structure_refused",
+			input: `const config = {
+  /** tree-sorter-ts: keep-sorted **/
+  zebra: 1,
+  alpha: 2,
+};
+
+function broken( {
+  return 1;
+}`,
+			want: `const config = {
+  /** tree-sorter-ts: keep-sorted **/
+  zebra: 1,
+  alpha: 2,
+};
+
+function broken( {
+  return 1;
+}`,
+			wantErrLine:   7,
+			wantErrColumn: 1,
+		},
+		{
+			name: "error_outside_structure_allowed",
+			input: `const config = {
+  /** tree-sorter-ts: keep-sorted **/
+  zebra: 1,
+  alpha: 2,
+};
+
+function broken( {
+  return 1;
+}`,
+			allowParseErrors: true,
+			want: `const config = {
+  /** tree-sorter-ts: keep-sorted **/
+  alpha: 2,
+  zebra: 1,
+};
+
+function broken( {
+  return 1;
+}`,
+			wantChanged: true,
+		},
+		{
+			name: "error_inside_structure_skipped",
+			input: `const broken = {
+  /** tree-sorter-ts: keep-sorted **/
+  zebra: 1 +,
+  alpha: 2,
+};
+
+const fine = {
+  /** tree-sorter-ts: keep-sorted **/
+  beta: 1,
+  alpha: 2,
+};`,
+			allowParseErrors: true,
+			want: `const broken = {
+  /** tree-sorter-ts: keep-sorted **/
+  zebra: 1 +,
+  alpha: 2,
+};
+
+const fine = {
+  /** tree-sorter-ts: keep-sorted **/
+  alpha: 2,
+  beta: 1,
+};`,
+			wantChanged: true,
+			wantSkipped: 1,
+		},
+		{
+			name: "missing_token_reported",
+			input: `const items = [
+  /** tree-sorter-ts: keep-sorted **/
+  "b",
+  "a",
+];
+const value = (1 + 2;`,
+			want: `const items = [
+  /** tree-sorter-ts: keep-sorted **/
+  "b",
+  "a",
+];
+const value = (1 + 2;`,
+			wantErrLine: 6,
+		},
+	}
+
+	tempDir := t.TempDir()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testFile := filepath.Join(tempDir, tt.name+".ts")
+			if err := os.WriteFile(testFile, []byte(tt.input), 0o644); err != nil {
+				t.Fatalf("Failed to write test file: %v", err)
+			}
+
+			result, err := ProcessFileAST(testFile, Config{Write: true, AllowParseErrors: tt.allowParseErrors})
+
+			if tt.wantErrLine > 0 {
+				var parseErr *ParseError
+				if !errors.As(err, &parseErr) {
+					t.Fatalf("expected ParseError, got %v", err)
+				}
+				if parseErr.Line != tt.wantErrLine {
+					t.Errorf("ParseError.Line = %d, want %d", parseErr.Line, tt.wantErrLine)
+				}
+				if tt.wantErrColumn > 0 && parseErr.Column != tt.wantErrColumn {
+					t.Errorf("ParseError.Column = %d, want %d", parseErr.Column, tt.wantErrColumn)
+				}
+			} else if err != nil {
+				t.Fatalf("ProcessFileAST failed: %v", err)
+			}
+
+			if result.Changed != tt.wantChanged {
+				t.Errorf("Changed = %v, want %v", result.Changed, tt.wantChanged)
+			}
+			if result.ObjectsSkipped != tt.wantSkipped {
+				t.Errorf("ObjectsSkipped = %d, want %d", result.ObjectsSkipped, tt.wantSkipped)
+			}
+
+			got, err := os.ReadFile(testFile)
+			if err != nil {
+				t.Fatalf("Failed to read file: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Content mismatch:\ngot:\n%s\n\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
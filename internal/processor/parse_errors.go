@@ -0,0 +1,93 @@
+package processor
+
+import (
+	"fmt"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// ParseError reports a syntax error found in a file's parse tree. Files with
+// syntax errors are never rewritten because tree-sitter's error recovery can
+// produce structures that do not match what the author wrote.
+type ParseError struct {
+	Line    int    // 1-based line of the first syntax error
+	Column  int    // 1-based byte column of the first syntax error
+	Missing string // Node type tree-sitter expected but did not find, if any
+	Snippet string // Unexpected source text, if any
+}
+
+func (e *ParseError) Error() string {
+	detail := "unexpected input"
+	switch {
+	case e.Missing != "":
+		detail = fmt.Sprintf("missing %q", e.Missing)
+	case e.Snippet != "":
+		detail = fmt.Sprintf("unexpected %q", e.Snippet)
+	}
+	return fmt.Sprintf("syntax error at line %d, column %d (%s); file left unchanged", e.Line, e.Column, detail)
+}
+
+// newParseError describes the first ERROR or MISSING node under root
+func newParseError(root *sitter.Node, content []byte) *ParseError {
+	errNode := firstSyntaxError(root)
+	if errNode == nil {
+		// HasError was true but no node was flagged; point at the start of the file
+		return &ParseError{Line: 1, Column: 1}
+	}
+
+	parseErr := &ParseError{
+		Line:   int(errNode.StartPoint().Row) + 1,
+		Column: int(errNode.StartPoint().Column) + 1,
+	}
+	if errNode.IsMissing() {
+		parseErr.Missing = errNode.Type()
+	} else {
+		parseErr.Snippet = errorSnippet(content[errNode.StartByte():errNode.EndByte()])
+	}
+	return parseErr
+}
+
+// firstSyntaxError returns the first ERROR or MISSING node in document order
+func firstSyntaxError(node *sitter.Node) *sitter.Node {
+	if node.IsError() || node.IsMissing() {
+		return node
+	}
+	if !node.HasError() {
+		return nil
+	}
+	for i := 0; i < int(node.ChildCount()); i++ {
+		if errNode := firstSyntaxError(node.Child(i)); errNode != nil {
+			return errNode
+		}
+	}
+	return nil
+}
+
+// errorSnippet shortens the text of an ERROR node to its first line
+func errorSnippet(text []byte) string {
+	const maxSnippetLen = 40
+
+	snippet := strings.TrimSpace(string(text))
+	if idx := strings.IndexByte(snippet, '\n'); idx >= 0 {
+		snippet = strings.TrimSpace(snippet[:idx])
+	}
+	if runes := []rune(snippet); len(runes) > maxSnippetLen {
+		snippet = string(runes[:maxSnippetLen]) + "..."
+	}
+	return snippet
+}
+
+// withoutSyntaxErrors drops structures whose subtree contains a syntax error and
+// reports how many were skipped
+func withoutSyntaxErrors[T any](structures []T, nodeOf func(T) *sitter.Node) (kept []T, skipped int) {
+	kept = structures[:0:0]
+	for _, s := range structures {
+		if nodeOf(s).HasError() {
+			skipped++
+			continue
+		}
+		kept = append(kept, s)
+	}
+	return kept, skipped
+}
@@ -2,156 +2,467 @@ package processor
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 
 	"github.com/evanrichards/tree-sorter-ts/internal/config"
 	"github.com/evanrichards/tree-sorter-ts/internal/parser"
 	"github.com/evanrichards/tree-sorter-ts/internal/reconstruction"
 	"github.com/evanrichards/tree-sorter-ts/internal/sorting/interfaces"
 	"github.com/evanrichards/tree-sorter-ts/internal/sorting/strategies"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/types/enums"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/types/members"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/types/statements"
 
 	sitter "github.com/smacker/go-tree-sitter"
-	"github.com/smacker/go-tree-sitter/typescript/typescript"
 )
 
+// SortConfig contains configuration options from the magic comment
+type SortConfig = config.SortConfig
+
+// Config holds the configuration for processing files
+type Config struct {
+	Check      bool
+	Write      bool
+	Recursive  bool
+	Extensions []string
+	Paths      []string // Files, directories or glob patterns to process
+	Workers    int
+	Verbose    bool
+	// AllowParseErrors sorts structures in files with syntax errors as long as
+	// the structure's own subtree parsed cleanly
+	AllowParseErrors bool
+	Diff             bool                   // Print a unified diff of the changes
+	DiffContext      int                    // Number of context lines around each diff hunk
+	Color            bool                   // Colorize diff output
+	Stdin            bool                   // Read content from stdin and write the result to stdout
+	StdinFilepath    string                 // Virtual path of the stdin content, used to pick the grammar
+	Format           string                 // Output format of the run report
+	ConfigPath       string                 // Explicit project configuration file
+	Include          []string               // Only process files matching one of these patterns
+	Exclude          []string               // Skip files and directories matching one of these patterns
+	Hidden           bool                   // Search dot-directories
+	NoIgnore         bool                   // Do not read .gitignore and .treesorterignore files
+	Lines            []LineRange            // Only check and sort structures overlapping these lines; nil means all
+	FileLines        map[string][]LineRange // Line ranges per cleaned file path from --lines; "" applies to --stdin
+	ChangedSince     string                 // Only process files changed since the merge-base with this git revision
+	DiffRangesFrom   string                 // Only process structures on lines changed since the merge-base with this git revision
+	Staged           bool                   // Only process staged files, sorting their staged content
+	Watch            bool                   // Keep running and sort files again when they change
+	// SortDefaults are default magic comment options, usually from the
+	// project configuration file
+	SortDefaults config.SortConfig
+}
+
+// ErrInvalidMagicComment is reported for magic comments combining conflicting options
+var ErrInvalidMagicComment = errors.New("invalid configuration: cannot use both 'key' and 'sort-by-comment' options together")
+
+// ErrUnbalancedSection is reported for start-sort and end-sort markers that do not pair up
+var ErrUnbalancedSection = errors.New("unbalanced section markers")
+
+// ErrUnsafeEnum is reported for enums whose member values depend on the
+// order of the members
+var ErrUnsafeEnum = errors.New("enum members cannot be sorted safely")
+
+// ErrUnsafeStatements is reported for statement lists whose statements run
+// code that depends on their order
+var ErrUnsafeStatements = errors.New("statements cannot be sorted safely")
+
+// ErrUnsafeClassMembers is reported for class bodies whose field initializers
+// and static blocks depend on the order of the members
+var ErrUnsafeClassMembers = errors.New("class members cannot be sorted safely")
+
+// ProcessResult contains the result of processing a file
+type ProcessResult struct {
+	Changed         bool
+	ObjectsFound    int
+	ObjectsNeedSort int
+	ObjectsSkipped  int    // Structures left alone because they contain syntax errors
+	Original        []byte // Content before sorting
+	Sorted          []byte // Sorted file content, only set when Changed
+	Structures      []StructureResult
+	// Edits turn Original into Sorted; they are the edits of every structure
+	// not nested in another changed one, ordered by position
+	Edits []TextEdit
+}
+
 // Processor handles the complete sorting workflow for TypeScript/TSX files
 type Processor struct {
-	astParser             *sitter.Parser
 	strategyFactory       *strategies.Factory
 	reconstructionFactory *reconstruction.Factory
 }
 
 // NewProcessor creates a new processor with all dependencies
 func NewProcessor() *Processor {
-	astParser := sitter.NewParser()
-	astParser.SetLanguage(typescript.GetLanguage())
-
 	return &Processor{
-		astParser:             astParser,
 		strategyFactory:       strategies.NewFactory(),
 		reconstructionFactory: reconstruction.NewFactory(),
 	}
 }
 
-// ProcessContent processes TypeScript/TSX content and returns sorted result
+// defaultProcessor backs the package level entry points
+var defaultProcessor = NewProcessor()
+
+// ProcessFileAST processes a file using full AST analysis
+func ProcessFileAST(filePath string, config Config) (ProcessResult, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return ProcessResult{}, fmt.Errorf("reading file: %w", err)
+	}
+
+	result, err := ProcessContentAST(filePath, content, config)
+	if err != nil {
+		return result, err
+	}
+
+	if result.Changed && config.Write {
+		err = os.WriteFile(filePath, result.Sorted, 0o600)
+		if err != nil {
+			return result, fmt.Errorf("writing file: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// ProcessContentAST sorts content in memory without touching the file system.
+// filePath only selects the grammar and need not exist.
+func ProcessContentAST(filePath string, content []byte, config Config) (ProcessResult, error) {
+	return defaultProcessor.Process(content, LanguageForPath(filePath), config)
+}
+
+// ProcessContent processes TypeScript content and returns sorted result
 func (p *Processor) ProcessContent(content []byte) ([]byte, error) {
-	// Parse AST
-	tree, err := p.astParser.ParseCtx(context.Background(), nil, content)
+	return p.ProcessContentAs(content, LanguageTypeScript)
+}
+
+// ProcessContentAs processes content parsed with the given grammar and returns sorted result
+func (p *Processor) ProcessContentAs(content []byte, lang Language) ([]byte, error) {
+	result, err := p.Process(content, lang, Config{})
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse content: %w", err)
+		return nil, err
+	}
+	if !result.Changed {
+		return content, nil
+	}
+	return result.Sorted, nil
+}
+
+// ProcessFile is a convenience method that processes content using the grammar
+// matching the file's extension
+func (p *Processor) ProcessFile(filename string, content []byte) ([]byte, error) {
+	return p.ProcessContentAs(content, LanguageForPath(filename))
+}
+
+// Process sorts every keep-sorted structure of content, parsed with the given
+// grammar, and describes what it found
+func (p *Processor) Process(content []byte, lang Language, config Config) (ProcessResult, error) {
+	result := ProcessResult{Original: content}
+
+	// Early exit if no magic comment found
+	if !parser.HasMagicComment(content) {
+		return result, nil
 	}
 
-	// Start with original content
-	result := make([]byte, len(content))
-	copy(result, content)
+	// Get parser for the file's grammar from pool
+	tsParser := getParser(lang)
+	defer putParser(lang, tsParser)
 
-	// Process all objects with magic comments
-	objects, err := parser.FindObjectsWithMagicComments(tree.RootNode(), content)
+	tree, err := tsParser.ParseCtx(context.Background(), nil, content)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find objects: %w", err)
+		return result, fmt.Errorf("parsing file: %w", err)
 	}
 
-	for _, objectSorter := range objects {
-		if updated, err := p.processSortable(objectSorter, result); err != nil {
-			return nil, fmt.Errorf("failed to process object: %w", err)
-		} else {
-			result = updated
+	rootNode := tree.RootNode()
+
+	// Refuse to touch files that did not parse cleanly unless asked to
+	if rootNode.HasError() && !config.AllowParseErrors {
+		return result, newParseError(rootNode, content)
+	}
+
+	// Find all objects, arrays, and constructors containing magic comments,
+	// and the sections of the ones that have none
+	sortables := findSortables(rootNode, content)
+	unbalanced := parser.FindUnbalancedSectionMarkers(rootNode, content)
+
+	// With line ranges, structures that were not touched are left alone
+	sortables = withinLines(sortables, config.Lines, sortedRange)
+	unbalanced = withinLines(unbalanced, config.Lines, func(m parser.UnbalancedMarker) (*sitter.Node, *sitter.Node) {
+		return m.Marker, m.Marker
+	})
+
+	// Describe every structure, including the ones that cannot be sorted.
+	// Options missing from the magic comments come from the configured
+	// defaults. Enums and statements whose meaning depends on their order are
+	// reported like invalid magic comments.
+	var orderErr error
+	for _, sortable := range sortables {
+		node := sortable.GetNode()
+		first, last := sortedRange(sortable)
+		kind := kindOf(sortable)
+		sortConfig := extractConfig(sortable, content).WithDefaults(config.SortDefaults)
+		// Only class members have kinds to group by, and only enum members
+		// have values
+		if kind != KindMembers {
+			sortConfig.GroupByKind = false
+		}
+		if kind != KindEnumMembers {
+			sortConfig.SortByValue = false
+			sortConfig.MaterializeValues = false
 		}
+		structure := newStructureResult(kind, first, last, sortable.GetMagicComment(), sortConfig, content)
+		switch {
+		case rootNode.HasError() && node.HasError():
+			structure.Error = "structure contains syntax errors; left unchanged"
+			structure.Sorted = false
+		case sortConfig.HasError:
+			structure.Error = ErrInvalidMagicComment.Error()
+			structure.Sorted = false
+		default:
+			if unsafe, err := checkOrder(sortable, sortConfig, content); err != nil {
+				structure.Options.HasError = true
+				structure.Error = err.Error()
+				structure.Sorted = false
+				if orderErr == nil {
+					orderErr = fmt.Errorf("%w: %s at line %d", unsafe, structure.Error, structure.Comment.Line)
+				}
+			}
+		}
+		result.Structures = append(result.Structures, structure)
 	}
 
-	// Process all arrays with magic comments
-	arrays, err := parser.FindArraysWithMagicComments(tree.RootNode(), content)
-	if err != nil {
-		return nil, fmt.Errorf("failed to find arrays: %w", err)
+	// Markers that do not pair up are reported like invalid magic comments,
+	// after the other structures were sorted
+	var sectionErr error
+	for _, m := range unbalanced {
+		structure := unbalancedStructureResult(m, content)
+		if sectionErr == nil {
+			sectionErr = fmt.Errorf("%w: %s at line %d", ErrUnbalancedSection, structure.Error, structure.Comment.Line)
+		}
+		result.Structures = append(result.Structures, structure)
+	}
+	sortStructures(result.Structures)
+	structureAt := make(map[structureKey]*StructureResult, len(result.Structures))
+	for i := range result.Structures {
+		s := &result.Structures[i]
+		structureAt[structureKey{start: s.StartByte, end: s.EndByte, kind: s.Kind}] = s
+	}
+
+	if rootNode.HasError() {
+		// Only sort structures whose own subtree is free of syntax errors
+		sortables, result.ObjectsSkipped = withoutSyntaxErrors(sortables, interfaces.Sortable.GetNode)
+	}
+
+	if len(sortables) == 0 {
+		return result, sectionErr
+	}
+
+	// Check for configuration errors; they fail the file after the other
+	// structures were checked, so reports still describe those
+	var configErr error
+	for _, sortable := range sortables {
+		if structureAt[keyOf(sortable)].Error == ErrInvalidMagicComment.Error() {
+			configErr = ErrInvalidMagicComment
+		}
+	}
+	if configErr == nil {
+		configErr = orderErr
+	}
+	if configErr == nil {
+		configErr = sectionErr
 	}
 
-	for _, arraySorter := range arrays {
-		if updated, err := p.processSortable(arraySorter, result); err != nil {
-			return nil, fmt.Errorf("failed to process array: %w", err)
-		} else {
-			result = updated
+	result.ObjectsFound = len(sortables)
+
+	// Sort every structure, nested ones before their containers, keeping the
+	// minimal edits that turn it into its sorted form
+	if err := p.sortNested(tsParser, content, sortables, structureAt); err != nil {
+		return result, fmt.Errorf("sorting nested structures: %w", err)
+	}
+	for _, structure := range result.Structures {
+		if len(structure.Edits) > 0 {
+			result.ObjectsNeedSort++
 		}
 	}
 
+	if configErr != nil {
+		return result, configErr
+	}
+	if result.ObjectsNeedSort == 0 {
+		return result, nil
+	}
+
+	result.Changed = true
+	result.Edits = outermostEdits(result.Structures)
+
+	newContent, err := ApplyEdits(content, result.Edits)
+	if err != nil {
+		return result, fmt.Errorf("combining sorted structures: %w", err)
+	}
+
+	// Make sure the rewritten file is still the same program before anyone sees it
+	if err := verifySortedContent(lang, rootNode, content, newContent); err != nil {
+		return result, err
+	}
+	result.Sorted = newContent
+
 	return result, nil
 }
 
-// processSortable handles the complete sorting workflow for a single sortable structure
-func (p *Processor) processSortable(sortable interfaces.Sortable, content []byte) ([]byte, error) {
-	// Extract the magic comment to get configuration
-	cfg, err := p.extractConfig(sortable, content)
-	if err != nil {
-		return nil, fmt.Errorf("failed to extract config: %w", err)
+// findSortables finds every keep-sorted structure and section of a parse
+// tree: objects, then arrays, then parameter lists, then statement lists,
+// then class bodies, then type bodies, then enum bodies, each in document
+// order with sections after the structures sorted as a whole
+func findSortables(root *sitter.Node, content []byte) []interfaces.Sortable {
+	var sortables []interfaces.Sortable
+	for _, objectSorter := range parser.FindObjectsWithMagicComments(root, content) {
+		sortables = append(sortables, objectSorter)
+	}
+	for _, objectSorter := range parser.FindObjectSections(root, content) {
+		sortables = append(sortables, objectSorter)
+	}
+	for _, arraySorter := range parser.FindArraysWithMagicComments(root, content) {
+		sortables = append(sortables, arraySorter)
+	}
+	for _, arraySorter := range parser.FindArraySections(root, content) {
+		sortables = append(sortables, arraySorter)
+	}
+	for _, constructorSorter := range parser.FindConstructorsWithMagicComments(root, content) {
+		sortables = append(sortables, constructorSorter)
+	}
+	for _, constructorSorter := range parser.FindConstructorSections(root, content) {
+		sortables = append(sortables, constructorSorter)
+	}
+	for _, statementSorter := range parser.FindStatementsWithMagicComments(root, content) {
+		sortables = append(sortables, statementSorter)
+	}
+	for _, statementSorter := range parser.FindStatementSections(root, content) {
+		sortables = append(sortables, statementSorter)
+	}
+	for _, classSorter := range parser.FindClassesWithMagicComments(root, content) {
+		sortables = append(sortables, classSorter)
+	}
+	for _, classSorter := range parser.FindClassSections(root, content) {
+		sortables = append(sortables, classSorter)
+	}
+	for _, typeSorter := range parser.FindTypesWithMagicComments(root, content) {
+		sortables = append(sortables, typeSorter)
+	}
+	for _, typeSorter := range parser.FindTypeSections(root, content) {
+		sortables = append(sortables, typeSorter)
+	}
+	for _, enumSorter := range parser.FindEnumsWithMagicComments(root, content) {
+		sortables = append(sortables, enumSorter)
+	}
+	for _, enumSorter := range parser.FindEnumSections(root, content) {
+		sortables = append(sortables, enumSorter)
 	}
+	return sortables
+}
+
+// checkOrder returns why sorting an enum, statement list or class body would
+// change what it does, along with the error to report it with, or nil when
+// it would not
+func checkOrder(sortable interfaces.Sortable, cfg config.SortConfig, content []byte) (error, error) {
+	switch s := sortable.(type) {
+	case *enums.EnumSorter:
+		if err := s.CheckValues(content, cfg.MaterializeValues); err != nil {
+			return ErrUnsafeEnum, err
+		}
+	case *statements.StatementSorter:
+		if err := s.CheckOrder(content); err != nil {
+			return ErrUnsafeStatements, err
+		}
+	case *members.ClassSorter:
+		if err := s.CheckOrder(content); err != nil {
+			return ErrUnsafeClassMembers, err
+		}
+	}
+	return nil, nil
+}
 
-	// Validate configuration
-	if err := cfg.Validate(); err != nil {
-		return nil, fmt.Errorf("invalid configuration: %w", err)
+// structureKey identifies a structure by its range and kind, since
+// structures of different kinds can start at the same byte
+type structureKey struct {
+	start, end int
+	kind       string
+}
+
+// keyOf returns the key of the structure a sortable sorts
+func keyOf(sortable interfaces.Sortable) structureKey {
+	first, last := sortedRange(sortable)
+	return structureKey{start: int(first.StartByte()), end: int(last.EndByte()), kind: kindOf(sortable)}
+}
+
+// sortedRange returns the first and last node of the text a sortable
+// rewrites: the markers of a section, the magic comment through the last
+// statement of a statement list, which may be a whole file, otherwise its
+// whole node
+func sortedRange(sortable interfaces.Sortable) (first, last *sitter.Node) {
+	node := sortable.GetNode()
+	if endIndex := sortable.GetEndIndex(); endIndex < int(node.ChildCount()) {
+		return sortable.GetMagicComment(), node.Child(endIndex)
+	}
+	if _, ok := sortable.(*statements.StatementSorter); ok {
+		return sortable.GetMagicComment(), node.Child(int(node.ChildCount()) - 1)
 	}
+	return node, node
+}
 
+// sortStructure returns the new text of a sortable's sorted range, and
+// whether its items need reordering or respacing at all
+func (p *Processor) sortStructure(sortable interfaces.Sortable, cfg config.SortConfig, content []byte) ([]byte, bool, error) {
 	// Extract sortable items
 	items, err := sortable.Extract(sortable.GetNode(), content)
 	if err != nil {
-		return nil, fmt.Errorf("failed to extract items: %w", err)
+		return nil, false, fmt.Errorf("failed to extract items: %w", err)
+	}
+	if len(items) <= 1 {
+		return nil, false, nil
 	}
 
 	// Get appropriate sorting strategy
 	strategy, err := p.strategyFactory.CreateStrategy(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create strategy: %w", err)
+		return nil, false, fmt.Errorf("failed to create strategy: %w", err)
 	}
 
-	// Check if already sorted
-	if sortable.CheckIfSorted(items, strategy, cfg.DeprecatedAtEnd, content) {
-		// Already sorted, no changes needed
-		return content, nil
+	// Even if already sorted, the spacing may not match the options
+	if sortable.CheckIfSorted(items, strategy, cfg.DeprecatedAtEnd, content) &&
+		!sortable.NeedsFormatting(items, cfg.WithNewLine, content) {
+		return nil, false, nil
 	}
 
 	// Sort the items
 	sortedItems, err := sortable.Sort(items, strategy, cfg.DeprecatedAtEnd, content)
 	if err != nil {
-		return nil, fmt.Errorf("failed to sort items: %w", err)
+		return nil, false, fmt.Errorf("failed to sort items: %w", err)
 	}
 
 	// Get appropriate reconstructor
 	reconstructor, err := p.reconstructionFactory.CreateReconstructor(sortable)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create reconstructor: %w", err)
+		return nil, false, fmt.Errorf("failed to create reconstructor: %w", err)
 	}
 
 	// Reconstruct the content
 	reconstructed, err := reconstructor.Reconstruct(sortable, sortedItems, cfg, content)
 	if err != nil {
-		return nil, fmt.Errorf("failed to reconstruct content: %w", err)
+		return nil, false, fmt.Errorf("failed to reconstruct content: %w", err)
 	}
 
-	return reconstructed, nil
-}
-
-// extractConfig extracts configuration from the magic comment
-func (p *Processor) extractConfig(sortable interfaces.Sortable, content []byte) (config.SortConfig, error) {
-	// Find the magic comment node
+	// Sections only rewrite the text between their markers, which the
+	// reconstructor copies along with the rest of the node
 	node := sortable.GetNode()
-	magicIndex := sortable.GetMagicCommentIndex()
-
-	if magicIndex < 0 || magicIndex >= int(node.ChildCount()) {
-		return config.SortConfig{}, fmt.Errorf("invalid magic comment index: %d", magicIndex)
-	}
+	first, last := sortedRange(sortable)
+	reconstructed = reconstructed[first.StartByte()-node.StartByte() : len(reconstructed)-int(node.EndByte()-last.EndByte())]
 
-	magicComment := node.Child(magicIndex)
-	if magicComment.Type() != "comment" {
-		return config.SortConfig{}, fmt.Errorf("expected comment node, got %s", magicComment.Type())
-	}
-
-	// Extract comment text
-	commentText := content[magicComment.StartByte():magicComment.EndByte()]
-
-	// Parse configuration
-	cfg := config.ParseSortConfig(commentText)
-	return cfg, nil
+	return reconstructed, true, nil
 }
 
-// ProcessFile is a convenience method that reads, processes, and could write back a file
-func (p *Processor) ProcessFile(filename string, content []byte) ([]byte, error) {
-	return p.ProcessContent(content)
-}
\ No newline at end of file
+// extractConfig extracts configuration from the magic comment
+func extractConfig(sortable interfaces.Sortable, content []byte) config.SortConfig {
+	magicComment := sortable.GetMagicComment()
+	return parser.ParseMagicComment(content[magicComment.StartByte():magicComment.EndByte()])
+}
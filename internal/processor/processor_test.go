@@ -0,0 +1,159 @@
+package processor
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/interfaces"
+)
+
+// sortSortable sorts a single structure with the options of its magic
+// comment, returning the new text of its node and whether it changed
+func sortSortable(sortable interfaces.Sortable, content []byte) ([]byte, bool) {
+	sorted, changed, err := NewProcessor().sortStructure(sortable, extractConfig(sortable, content), content)
+	if err != nil {
+		return nil, false
+	}
+	return sorted, changed
+}
+
+// assertSorted checks that sorting input yields expected, that every
+// structure found has the given kind unless kind is empty, and that sorting
+// the result again changes nothing
+func assertSorted(t *testing.T, path, input, expected, kind string) {
+	t.Helper()
+	result, err := ProcessContentAST(path, []byte(input), Config{})
+	if err != nil {
+		t.Fatalf("ProcessContentAST failed: %v", err)
+	}
+	if !result.Changed {
+		t.Fatalf("Expected content to change")
+	}
+	if string(result.Sorted) != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, string(result.Sorted))
+	}
+	for _, structure := range result.Structures {
+		if kind != "" && structure.Kind != kind {
+			t.Errorf("Expected kind %q, got %q", kind, structure.Kind)
+		}
+	}
+
+	again, err := ProcessContentAST(path, result.Sorted, Config{})
+	if err != nil {
+		t.Fatalf("ProcessContentAST failed on sorted content: %v", err)
+	}
+	if again.Changed {
+		t.Errorf("Sorted content changed again:\n%s", string(again.Sorted))
+	}
+}
+
+// assertRefused checks that sorting input fails with target, leaving the
+// content unchanged and reporting its only structure with an error
+// containing message
+func assertRefused(t *testing.T, path, input string, target error, message string) {
+	t.Helper()
+	result, err := ProcessContentAST(path, []byte(input), Config{})
+	if !errors.Is(err, target) {
+		t.Fatalf("Expected %v, got %v", target, err)
+	}
+	if result.Changed {
+		t.Errorf("Expected content to be left unchanged")
+	}
+	if len(result.Structures) != 1 {
+		t.Fatalf("Expected 1 structure, got %d", len(result.Structures))
+	}
+	structure := result.Structures[0]
+	if !structure.Options.HasError || len(structure.Edits) > 0 || !strings.Contains(structure.Error, message) {
+		t.Errorf("Expected the structure to be reported with %q, got %+v", message, structure)
+	}
+}
+
+func TestProcessorProcessContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name: "sorts objects arrays and parameters",
+			content: `class Service {
+  constructor(
+    /** tree-sorter-ts: keep-sorted **/
+    private readonly zeta: string,
+    private readonly alpha: string,
+  ) {}
+}
+
+const config = {
+  /** tree-sorter-ts: keep-sorted **/
+  zebra: 1,
+  apple: 2,
+};
+
+const items = [
+  /** tree-sorter-ts: keep-sorted **/
+  "charlie",
+  "alpha",
+];`,
+			want: `class Service {
+  constructor(
+    /** tree-sorter-ts: keep-sorted **/
+    private readonly alpha: string,
+    private readonly zeta: string,
+  ) {}
+}
+
+const config = {
+  /** tree-sorter-ts: keep-sorted **/
+  apple: 2,
+  zebra: 1,
+};
+
+const items = [
+  /** tree-sorter-ts: keep-sorted **/
+  "alpha",
+  "charlie",
+];`,
+		},
+		{
+			name: "only fixes the spacing of sorted items",
+			content: `const config = {
+  /** tree-sorter-ts: keep-sorted with-new-line **/
+  apple: 2,
+  zebra: 1,
+};`,
+			want: `const config = {
+  /** tree-sorter-ts: keep-sorted with-new-line **/
+  apple: 2,
+
+  zebra: 1,
+};`,
+		},
+		{
+			name: "leaves sorted content alone",
+			content: `const config = {
+  /** tree-sorter-ts: keep-sorted **/
+  apple: 2,
+  zebra: 1,
+};`,
+			want: `const config = {
+  /** tree-sorter-ts: keep-sorted **/
+  apple: 2,
+  zebra: 1,
+};`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewProcessor().ProcessContent([]byte(tt.content))
+			if err != nil {
+				t.Fatalf("Failed to process content: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("ProcessContent() mismatch\ngot:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package processor
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestProcessContentASTSections(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name: "object section leaves other properties alone",
+			input: `const config = {
+  zebra: true,
+  /** tree-sorter-ts: start-sort **/
+  mango: 3,
+  // The best fruit
+  banana: 2,
+  apple: 1,
+  /** tree-sorter-ts: end-sort **/
+  aardvark: false,
+};
+`,
+			expected: `const config = {
+  zebra: true,
+  /** tree-sorter-ts: start-sort **/
+  apple: 1,
+  // The best fruit
+  banana: 2,
+  mango: 3,
+  /** tree-sorter-ts: end-sort **/
+  aardvark: false,
+};
+`,
+		},
+		{
+			name: "multiple sections in one object",
+			input: `const config = {
+  /** tree-sorter-ts: start-sort **/
+  b: 2,
+  a: 1,
+  /** tree-sorter-ts: end-sort **/
+  z: 26,
+  /** tree-sorter-ts: start-sort **/
+  y: 25,
+  x: 24,
+  /** tree-sorter-ts: end-sort **/
+};
+`,
+			expected: `const config = {
+  /** tree-sorter-ts: start-sort **/
+  a: 1,
+  b: 2,
+  /** tree-sorter-ts: end-sort **/
+  z: 26,
+  /** tree-sorter-ts: start-sort **/
+  x: 24,
+  y: 25,
+  /** tree-sorter-ts: end-sort **/
+};
+`,
+		},
+		{
+			name: "array section",
+			input: `const plugins = [
+  first,
+  /** tree-sorter-ts: start-sort **/
+  "zod",
+  "react",
+  "lodash",
+  /** tree-sorter-ts: end-sort **/
+  last,
+];
+`,
+			expected: `const plugins = [
+  first,
+  /** tree-sorter-ts: start-sort **/
+  "lodash",
+  "react",
+  "zod",
+  /** tree-sorter-ts: end-sort **/
+  last,
+];
+`,
+		},
+		{
+			name: "parameter section",
+			input: `class Service {
+  constructor(
+    private readonly logger: Logger,
+    /** tree-sorter-ts: start-sort **/
+    private readonly users: UserRepository,
+    private readonly teams: TeamRepository,
+    /** tree-sorter-ts: end-sort **/
+  ) {}
+}
+`,
+			expected: `class Service {
+  constructor(
+    private readonly logger: Logger,
+    /** tree-sorter-ts: start-sort **/
+    private readonly teams: TeamRepository,
+    private readonly users: UserRepository,
+    /** tree-sorter-ts: end-sort **/
+  ) {}
+}
+`,
+		},
+		{
+			name: "options on the start marker",
+			input: `const config = {
+  first: 0,
+  /** tree-sorter-ts: start-sort with-new-line **/
+  b: 2,
+  a: 1,
+  /** tree-sorter-ts: end-sort **/
+};
+`,
+			expected: `const config = {
+  first: 0,
+  /** tree-sorter-ts: start-sort with-new-line **/
+  a: 1,
+
+  b: 2,
+  /** tree-sorter-ts: end-sort **/
+};
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertSorted(t, "sections.ts", tt.input, tt.expected, "")
+		})
+	}
+}
+
+func TestProcessContentASTSectionStructures(t *testing.T) {
+	input := `const config = {
+  zebra: true,
+  /** tree-sorter-ts: start-sort **/
+  b: 2,
+  a: 1,
+  /** tree-sorter-ts: end-sort **/
+};
+`
+	result, err := ProcessContentAST("sections.ts", []byte(input), Config{})
+	if err != nil {
+		t.Fatalf("ProcessContentAST failed: %v", err)
+	}
+	if len(result.Structures) != 1 {
+		t.Fatalf("Expected 1 structure, got %d", len(result.Structures))
+	}
+
+	structure := result.Structures[0]
+	if structure.Kind != KindObject || structure.Start.Line != 3 || structure.End.Line != 6 {
+		t.Errorf("Expected an object section from line 3 to 6, got %s from line %d to %d", structure.Kind, structure.Start.Line, structure.End.Line)
+	}
+	for _, edit := range structure.Edits {
+		if edit.StartByte < structure.StartByte || edit.EndByte > structure.EndByte {
+			t.Errorf("Edit %d-%d is outside of the section", edit.StartByte, edit.EndByte)
+		}
+	}
+}
+
+func TestProcessContentASTUnbalancedSections(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		line  int
+	}{
+		{
+			name: "start without end",
+			input: `const config = {
+  /** tree-sorter-ts: start-sort **/
+  b: 2,
+  a: 1,
+};
+`,
+			line: 2,
+		},
+		{
+			name: "end without start",
+			input: `const list = [
+  "b",
+  "a",
+  /** tree-sorter-ts: end-sort **/
+];
+`,
+			line: 4,
+		},
+		{
+			name:  "section on a single line",
+			input: "const config = { /** tree-sorter-ts: start-sort **/ z: 1, a: 2, /** tree-sorter-ts: end-sort **/ q: 3 };\n",
+			line:  1,
+		},
+		{
+			name: "item on the line of the start marker",
+			input: `const config = {
+  /** tree-sorter-ts: start-sort **/ z: 1,
+  a: 2,
+  /** tree-sorter-ts: end-sort **/
+  q: 3,
+};
+`,
+			line: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ProcessContentAST("sections.ts", []byte(tt.input), Config{})
+			if !errors.Is(err, ErrUnbalancedSection) {
+				t.Fatalf("Expected ErrUnbalancedSection, got %v", err)
+			}
+			if result.Changed {
+				t.Errorf("Expected content to be left unchanged")
+			}
+			if len(result.Structures) != 1 {
+				t.Fatalf("Expected 1 structure, got %d", len(result.Structures))
+			}
+			structure := result.Structures[0]
+			if !structure.Options.HasError || structure.Error == "" {
+				t.Errorf("Expected the marker to be reported as invalid, got %+v", structure)
+			}
+			if structure.Comment.Line != tt.line {
+				t.Errorf("Expected the marker at line %d, got %d", tt.line, structure.Comment.Line)
+			}
+		})
+	}
+}
+
+func TestProcessContentASTKeepSortedIgnoresSectionMarkers(t *testing.T) {
+	input := `const config = {
+  /** tree-sorter-ts: keep-sorted **/
+  b: 2,
+  /** tree-sorter-ts: start-sort **/
+  a: 1,
+};
+`
+	result, err := ProcessContentAST("sections.ts", []byte(input), Config{})
+	if err != nil {
+		t.Fatalf("ProcessContentAST failed: %v", err)
+	}
+	if len(result.Structures) != 1 || result.Structures[0].Start.Line != 1 {
+		t.Errorf("Expected only the keep-sorted object, got %+v", result.Structures)
+	}
+}
@@ -0,0 +1,337 @@
+package processor
+
+import (
+	"testing"
+)
+
+func TestProcessContentASTStatements(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name: "exports first, then by declared name",
+			input: `import { helperDep } from "./dep";
+
+/** tree-sorter-ts: keep-sorted **/
+const helper = () => helperDep;
+export function processData() {}
+export const CONFIG = { debug: false };
+const cache = new Map();
+export const API_KEY = "key";
+`,
+			expected: `import { helperDep } from "./dep";
+
+/** tree-sorter-ts: keep-sorted **/
+export const API_KEY = "key";
+export const CONFIG = { debug: false };
+export function processData() {}
+const cache = new Map();
+const helper = () => helperDep;
+`,
+		},
+		{
+			name: "JSDoc and decorators move with their statement",
+			input: `/** tree-sorter-ts: keep-sorted **/
+/** Handles users */
+@injectable()
+export class UserService {}
+/** Handles teams */
+@injectable()
+export class TeamService {}
+`,
+			expected: `/** tree-sorter-ts: keep-sorted **/
+/** Handles teams */
+@injectable()
+export class TeamService {}
+/** Handles users */
+@injectable()
+export class UserService {}
+`,
+		},
+		{
+			name: "overloads keep their order",
+			input: `/** tree-sorter-ts: keep-sorted **/
+export function parse(input: string): Node;
+export function parse(input: Buffer): Node;
+export function parse(input: string | Buffer): Node {
+  return toNode(input);
+}
+export function format(node: Node): string {
+  return String(node);
+}
+`,
+			expected: `/** tree-sorter-ts: keep-sorted **/
+export function format(node: Node): string {
+  return String(node);
+}
+export function parse(input: string): Node;
+export function parse(input: Buffer): Node;
+export function parse(input: string | Buffer): Node {
+  return toNode(input);
+}
+`,
+		},
+		{
+			name: "namespace body",
+			input: `namespace Colors {
+  /** tree-sorter-ts: keep-sorted with-new-line **/
+  export const red = "#f00";
+  export const blue = "#00f";
+}
+`,
+			expected: `namespace Colors {
+  /** tree-sorter-ts: keep-sorted with-new-line **/
+  export const blue = "#00f";
+
+  export const red = "#f00";
+}
+`,
+		},
+		{
+			name: "module declaration body",
+			input: `declare module "config" {
+  /** tree-sorter-ts: keep-sorted **/
+  export const timeout: number;
+  export function load(): void;
+  export interface Settings {}
+}
+`,
+			expected: `declare module "config" {
+  /** tree-sorter-ts: keep-sorted **/
+  export interface Settings {}
+  export function load(): void;
+  export const timeout: number;
+}
+`,
+		},
+		{
+			name: "deprecated statements at the end",
+			input: `/** tree-sorter-ts: keep-sorted deprecated-at-end **/
+/** @deprecated use fetchUser */
+export function getUser() {}
+export function fetchUser() {}
+const local = 1;
+`,
+			expected: `/** tree-sorter-ts: keep-sorted deprecated-at-end **/
+export function fetchUser() {}
+const local = 1;
+/** @deprecated use fetchUser */
+export function getUser() {}
+`,
+		},
+		{
+			name: "section between statements that must stay in place",
+			input: `import "./polyfills";
+
+/** tree-sorter-ts: start-sort **/
+export const zeta = 26;
+export const alpha = 1; // first
+/** tree-sorter-ts: end-sort **/
+
+main();
+`,
+			expected: `import "./polyfills";
+
+/** tree-sorter-ts: start-sort **/
+export const alpha = 1; // first
+export const zeta = 26;
+/** tree-sorter-ts: end-sort **/
+
+main();
+`,
+		},
+		{
+			name: "references that only run later",
+			input: `/** tree-sorter-ts: keep-sorted **/
+const load = () => registry.get(name);
+class Registry {
+  items = defaults;
+  get(key: string) {
+    return defaults[key];
+  }
+}
+const registry: Registry = createRegistry();
+const defaults = {};
+`,
+			expected: `/** tree-sorter-ts: keep-sorted **/
+class Registry {
+  items = defaults;
+  get(key: string) {
+    return defaults[key];
+  }
+}
+const defaults = {};
+const load = () => registry.get(name);
+const registry: Registry = createRegistry();
+`,
+		},
+		{
+			name: "trailing comments stay at the end of the file",
+			input: `/** tree-sorter-ts: keep-sorted **/
+const b = 2;
+const a = 1;
+
+// End of generated constants
+`,
+			expected: `/** tree-sorter-ts: keep-sorted **/
+const a = 1;
+const b = 2;
+
+// End of generated constants
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertSorted(t, "statements.ts", tt.input, tt.expected, KindStatements)
+		})
+	}
+}
+
+func TestProcessContentASTUnsafeStatements(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		error string
+	}{
+		{
+			name: "calls",
+			input: `/** tree-sorter-ts: keep-sorted **/
+registerPlugin(zed);
+registerPlugin(alpha);
+`,
+			error: `statement "registerPlugin(zed);" declares nothing`,
+		},
+		{
+			name: "side-effect imports",
+			input: `/** tree-sorter-ts: keep-sorted **/
+import "./polyfill";
+import "./app";
+`,
+			error: `statement "import \"./polyfill\";" declares nothing`,
+		},
+		{
+			name: "re-exports",
+			input: `/** tree-sorter-ts: keep-sorted **/
+export * from "./zed";
+export { alpha } from "./alpha";
+`,
+			error: `statement "export * from \"./zed\";" declares nothing`,
+		},
+		{
+			name: "initializer using another sorted declaration",
+			input: `/** tree-sorter-ts: keep-sorted **/
+const zed = 1;
+const alpha = zed + 1;
+`,
+			error: `"alpha" uses "zed", which sorting may move after it`,
+		},
+		{
+			name: "class extending another sorted class",
+			input: `/** tree-sorter-ts: keep-sorted **/
+export class Base {}
+export class Admin extends Base {}
+`,
+			error: `"Admin" uses "Base"`,
+		},
+		{
+			name: "function called right away",
+			input: `/** tree-sorter-ts: keep-sorted **/
+const { port = 80 } = loadSettings();
+const address = (() => port)();
+`,
+			error: `"address" uses "port"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertRefused(t, "statements.ts", tt.input, ErrUnsafeStatements, tt.error)
+		})
+	}
+}
+
+func TestProcessContentASTFunctionBodiesNotSorted(t *testing.T) {
+	input := `function setup() {
+  /** tree-sorter-ts: keep-sorted **/
+  const b = init();
+  const a = b.child();
+}
+`
+	result, err := ProcessContentAST("statements.ts", []byte(input), Config{})
+	if err != nil {
+		t.Fatalf("ProcessContentAST failed: %v", err)
+	}
+	if result.Changed || len(result.Structures) != 0 {
+		t.Errorf("Expected function body to be left alone, got %d structures", len(result.Structures))
+	}
+}
+
+func TestProcessContentASTStatementsAfterArrayAtFileStart(t *testing.T) {
+	input := `[
+  /** tree-sorter-ts: keep-sorted **/
+  3,
+  1,
+].forEach(register);
+
+/** tree-sorter-ts: keep-sorted **/
+const b = 2;
+const a = 1;
+`
+	expected := `[
+  /** tree-sorter-ts: keep-sorted **/
+  1,
+  3,
+].forEach(register);
+
+/** tree-sorter-ts: keep-sorted **/
+const a = 1;
+const b = 2;
+`
+	result, err := ProcessContentAST("statements.ts", []byte(input), Config{})
+	if err != nil {
+		t.Fatalf("ProcessContentAST failed: %v", err)
+	}
+	if string(result.Sorted) != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, string(result.Sorted))
+	}
+	if len(result.Structures) != 2 {
+		t.Fatalf("Expected 2 structures, got %d", len(result.Structures))
+	}
+	for _, structure := range result.Structures {
+		if structure.Sorted || len(structure.Edits) == 0 {
+			t.Errorf("Expected the %s to be reported unsorted, got %+v", structure.Kind, structure)
+		}
+	}
+}
+
+func TestProcessContentASTStatementsRangeStartsAtMarker(t *testing.T) {
+	input := `import { helper } from "./helper";
+
+/** tree-sorter-ts: keep-sorted **/
+const b = 2;
+const a = 1;
+`
+	result, err := ProcessContentAST("statements.ts", []byte(input), Config{})
+	if err != nil {
+		t.Fatalf("ProcessContentAST failed: %v", err)
+	}
+	if len(result.Structures) != 1 {
+		t.Fatalf("Expected 1 structure, got %d", len(result.Structures))
+	}
+	structure := result.Structures[0]
+	if structure.Start.Line != 3 || structure.StartByte != structure.CommentStartByte || structure.End.Line != 5 {
+		t.Errorf("Expected the statements from the marker at line 3 to line 5, got %+v", structure)
+	}
+
+	above, err := ProcessContentAST("statements.ts", []byte(input), Config{Lines: []LineRange{{Start: 1, End: 1}}})
+	if err != nil {
+		t.Fatalf("ProcessContentAST failed: %v", err)
+	}
+	if len(above.Structures) != 0 {
+		t.Errorf("Expected lines above the marker to select no structure, got %+v", above.Structures)
+	}
+}
@@ -0,0 +1,168 @@
+package processor
+
+import (
+	"bytes"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/parser"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/interfaces"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/strategies"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/types/arrays"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/types/constructors"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/types/enums"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/types/members"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/types/statements"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/types/typemembers"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// Kinds of keep-sorted structures reported in StructureResult.Kind
+const (
+	KindObject      = "object"
+	KindArray       = "array"
+	KindParameters  = "parameters"
+	KindStatements  = "statements"
+	KindMembers     = "members"
+	KindTypeMembers = "type-members"
+	KindEnumMembers = "enum-members"
+)
+
+// Position is a 1-based line and column; columns count characters, not bytes
+type Position struct {
+	Line   int
+	Column int
+}
+
+// StructureResult describes one keep-sorted structure found in a file
+type StructureResult struct {
+	Kind             string
+	Start            Position
+	End              Position
+	Comment          Position // Start of the magic comment
+	StartByte        int
+	EndByte          int
+	CommentStartByte int
+	CommentEndByte   int
+	Options          SortConfig
+	Strategy         string // Name of the sorting strategy selected by the options
+	Sorted           bool   // Whether the structure already was in sorted order
+	Error            string // Why the structure could not be sorted, if it could not
+	// Edits sort the structure along with the structures nested in it,
+	// touching only the bytes between StartByte and EndByte that change;
+	// empty when the structure is sorted
+	Edits []TextEdit
+}
+
+// newStructureResult describes a structure spanning from first to last before
+// its sort state is known
+func newStructureResult(kind string, first, last, magicComment *sitter.Node, sortConfig SortConfig, content []byte) StructureResult {
+	return StructureResult{
+		Kind:             kind,
+		Start:            positionAt(content, first.StartPoint()),
+		End:              positionAt(content, last.EndPoint()),
+		Comment:          positionAt(content, magicComment.StartPoint()),
+		StartByte:        int(first.StartByte()),
+		EndByte:          int(last.EndByte()),
+		CommentStartByte: int(magicComment.StartByte()),
+		CommentEndByte:   int(magicComment.EndByte()),
+		Options:          sortConfig,
+		Strategy:         strategyName(sortConfig),
+		Sorted:           true,
+	}
+}
+
+// unbalancedStructureResult describes a section marker without a partner as
+// a structure spanning the marker, with an invalid magic comment
+func unbalancedStructureResult(m parser.UnbalancedMarker, content []byte) StructureResult {
+	sortConfig := parser.ParseMagicComment(content[m.Marker.StartByte():m.Marker.EndByte()])
+	sortConfig.HasError = true
+
+	structure := newStructureResult(containerKind(m.Container), m.Marker, m.Marker, m.Marker, sortConfig, content)
+	structure.Sorted = false
+	switch {
+	case m.Inline:
+		structure.Error = "start-sort marker must be on its own line, before the items of its section"
+	case m.IsStart:
+		structure.Error = "start-sort marker has no matching end-sort marker"
+	default:
+		structure.Error = "end-sort marker has no matching start-sort marker"
+	}
+	return structure
+}
+
+// containerKind returns the kind of structure a container node is
+func containerKind(node *sitter.Node) string {
+	switch node.Type() {
+	case "array":
+		return KindArray
+	case "formal_parameters":
+		return KindParameters
+	case "program", "statement_block":
+		return KindStatements
+	case "class_body":
+		return KindMembers
+	case "interface_body", "object_type":
+		return KindTypeMembers
+	case "enum_body":
+		return KindEnumMembers
+	default:
+		return KindObject
+	}
+}
+
+// kindOf returns the kind of structure a sortable sorts
+func kindOf(sortable interfaces.Sortable) string {
+	switch sortable.(type) {
+	case *arrays.ArraySorter:
+		return KindArray
+	case *constructors.ConstructorSorter:
+		return KindParameters
+	case *statements.StatementSorter:
+		return KindStatements
+	case *members.ClassSorter:
+		return KindMembers
+	case *typemembers.TypeSorter:
+		return KindTypeMembers
+	case *enums.EnumSorter:
+		return KindEnumMembers
+	default:
+		return KindObject
+	}
+}
+
+// strategyName returns the name of the strategy picked for the options
+func strategyName(sortConfig SortConfig) string {
+	strategy, err := strategies.NewFactory().CreateStrategy(sortConfig)
+	if err != nil {
+		return ""
+	}
+	return strategy.GetName()
+}
+
+// positionAt converts a tree-sitter point, whose column counts bytes, into a
+// Position whose column counts characters
+func positionAt(content []byte, point sitter.Point) Position {
+	lineStart := 0
+	for line := uint32(0); line < point.Row && lineStart < len(content); line++ {
+		next := bytes.IndexByte(content[lineStart:], '\n')
+		if next < 0 {
+			break
+		}
+		lineStart += next + 1
+	}
+
+	lineEnd := min(lineStart+int(point.Column), len(content))
+	return Position{
+		Line:   int(point.Row) + 1,
+		Column: utf8.RuneCount(content[lineStart:lineEnd]) + 1,
+	}
+}
+
+// sortStructures orders structures by their position in the file
+func sortStructures(structures []StructureResult) {
+	sort.Slice(structures, func(i, j int) bool {
+		return structures[i].StartByte < structures[j].StartByte
+	})
+}
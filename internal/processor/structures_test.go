@@ -0,0 +1,161 @@
+package processor
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/config"
+)
+
+func TestProcessContentASTStructures(t *testing.T) {
+	content := []byte(`const label = "héllo"; const config = {
+  /** tree-sorter-ts: keep-sorted with-new-line **/
+  alpha: 1,
+
+  beta: 2,
+};
+
+const items = [
+  /** tree-sorter-ts: keep-sorted key="id" **/
+  { id: "b" },
+  { id: "a" },
+];
+
+class Service {
+  constructor(
+    /** tree-sorter-ts: keep-sorted **/
+    private zebra: string,
+    private alpha: string,
+  ) {}
+}
+`)
+
+	result, err := ProcessContentAST("structures.ts", content, Config{})
+	if err != nil {
+		t.Fatalf("ProcessContentAST failed: %v", err)
+	}
+
+	want := []struct {
+		kind     string
+		start    Position
+		end      Position
+		strategy string
+		sorted   bool
+	}{
+		// The object starts after a multi-byte character, so columns count characters
+		{kind: KindObject, start: Position{Line: 1, Column: 39}, end: Position{Line: 6, Column: 2}, strategy: "property-name", sorted: true},
+		{kind: KindArray, start: Position{Line: 8, Column: 15}, end: Position{Line: 12, Column: 2}, strategy: "array-key[id]", sorted: false},
+		{kind: KindParameters, start: Position{Line: 15, Column: 14}, end: Position{Line: 19, Column: 4}, strategy: "property-name", sorted: false},
+	}
+
+	if len(result.Structures) != len(want) {
+		t.Fatalf("got %d structures, want %d: %+v", len(result.Structures), len(want), result.Structures)
+	}
+	for i, w := range want {
+		got := result.Structures[i]
+		if got.Kind != w.kind || got.Start != w.start || got.End != w.end || got.Strategy != w.strategy || got.Sorted != w.sorted {
+			t.Errorf("structure %d = {%s %v %v %s sorted=%v}, want {%s %v %v %s sorted=%v}",
+				i, got.Kind, got.Start, got.End, got.Strategy, got.Sorted,
+				w.kind, w.start, w.end, w.strategy, w.sorted)
+		}
+		if got.Error != "" {
+			t.Errorf("structure %d has unexpected error %q", i, got.Error)
+		}
+	}
+
+	// Unsorted structures carry the edits that sort them in the sorted output
+	if len(result.Structures[0].Edits) != 0 {
+		t.Errorf("sorted structure should have no edits, got %+v", result.Structures[0].Edits)
+	}
+	array := result.Structures[1]
+	for _, edit := range array.Edits {
+		if edit.StartByte < array.StartByte || edit.EndByte > array.EndByte {
+			t.Errorf("edit %+v is outside the array (%d-%d)", edit, array.StartByte, array.EndByte)
+		}
+	}
+	spliced, err := ApplyEdits(content, array.Edits)
+	if err != nil {
+		t.Fatalf("Failed to apply array edits: %v", err)
+	}
+	sortedArray := "[\n  /** tree-sorter-ts: keep-sorted key=\"id\" **/\n  { id: \"a\" },\n  { id: \"b\" },\n]"
+	if !strings.Contains(string(result.Sorted), sortedArray) || !strings.Contains(string(spliced), sortedArray) {
+		t.Errorf("edits do not match the sorted output:\n%s", spliced)
+	}
+	if combined, err := ApplyEdits(content, result.Edits); err != nil || !bytes.Equal(combined, result.Sorted) {
+		t.Errorf("result edits do not produce the sorted output (err %v):\n%s", err, combined)
+	}
+
+	if comment := result.Structures[0].Comment; comment != (Position{Line: 2, Column: 3}) {
+		t.Errorf("object magic comment at %v, want line 2, column 3", comment)
+	}
+
+	if !result.Structures[0].Options.WithNewLine {
+		t.Errorf("expected with-new-line option on the object")
+	}
+	if result.Structures[1].Options.Key != "id" {
+		t.Errorf("expected key option on the array, got %q", result.Structures[1].Options.Key)
+	}
+}
+
+func TestProcessContentASTStructureErrors(t *testing.T) {
+	content := []byte(`const items = [
+  /** tree-sorter-ts: keep-sorted key="id" sort-by-comment **/
+  { id: "b" },
+  { id: "a" },
+];
+`)
+
+	result, err := ProcessContentAST("invalid.ts", content, Config{})
+	if err == nil {
+		t.Fatal("expected configuration error")
+	}
+	if len(result.Structures) != 1 {
+		t.Fatalf("got %d structures, want 1", len(result.Structures))
+	}
+	if got := result.Structures[0].Error; got != err.Error() {
+		t.Errorf("structure error = %q, want %q", got, err.Error())
+	}
+	if result.Structures[0].Sorted {
+		t.Errorf("structure with invalid options must not be reported as sorted")
+	}
+}
+
+func TestProcessContentASTSortDefaults(t *testing.T) {
+	content := []byte(`const config = {
+  /** tree-sorter-ts: keep-sorted **/
+  /** @deprecated */
+  alpha: 1,
+  beta: 2,
+};
+`)
+	expected := `const config = {
+  /** tree-sorter-ts: keep-sorted **/
+  beta: 2,
+  /** @deprecated */
+  alpha: 1,
+};
+`
+
+	// Without defaults the object is already sorted
+	result, err := ProcessContentAST("defaults.ts", content, Config{})
+	if err != nil {
+		t.Fatalf("ProcessContentAST failed: %v", err)
+	}
+	if result.Changed {
+		t.Fatalf("expected no changes without defaults, got:\n%s", result.Sorted)
+	}
+
+	result, err = ProcessContentAST("defaults.ts", content, Config{
+		SortDefaults: config.SortConfig{DeprecatedAtEnd: true},
+	})
+	if err != nil {
+		t.Fatalf("ProcessContentAST failed: %v", err)
+	}
+	if string(result.Sorted) != expected {
+		t.Errorf("default options not applied\nGot:\n%s\nExpected:\n%s", result.Sorted, expected)
+	}
+	if !result.Structures[0].Options.DeprecatedAtEnd {
+		t.Errorf("structure options should include the defaults, got %+v", result.Structures[0].Options)
+	}
+}
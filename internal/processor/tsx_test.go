@@ -0,0 +1,181 @@
+package processor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const tsxFixturesDir = "../../testdata/fixtures/tsx"
+
+func TestLanguageForPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want Language
+	}{
+		{path: "src/config.ts", want: LanguageTypeScript},
+		{path: "src/types.d.ts", want: LanguageTypeScript},
+		{path: "src/module.mts", want: LanguageTypeScript},
+		{path: "src/App.tsx", want: LanguageTSX},
+		{path: "src/Legacy.TSX", want: LanguageTSX},
+		{path: "src/no-extension", want: LanguageTypeScript},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := LanguageForPath(tt.path); got != tt.want {
+				t.Errorf("LanguageForPath(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTSXFixturesParseWithoutErrors(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join(tsxFixturesDir, "*.tsx"))
+	if err != nil {
+		t.Fatalf("Failed to list fixtures: %v", err)
+	}
+	if len(inputs) == 0 {
+		t.Fatal("no TSX fixtures found")
+	}
+
+	for _, input := range inputs {
+		t.Run(filepath.Base(input), func(t *testing.T) {
+			content, err := os.ReadFile(input)
+			if err != nil {
+				t.Fatalf("Failed to read fixture: %v", err)
+			}
+
+			parser := getParser(LanguageTSX)
+			defer putParser(LanguageTSX, parser)
+
+			tree, err := parser.ParseCtx(context.Background(), nil, content)
+			if err != nil {
+				t.Fatalf("Failed to parse: %v", err)
+			}
+			if tree.RootNode().HasError() {
+				t.Errorf("TSX grammar produced a tree with errors for %s", input)
+			}
+		})
+	}
+}
+
+func TestTSXFixturesRoundTrip(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join(tsxFixturesDir, "*.tsx"))
+	if err != nil {
+		t.Fatalf("Failed to list fixtures: %v", err)
+	}
+
+	tempDir := t.TempDir()
+
+	for _, input := range inputs {
+		if strings.HasSuffix(input, ".expected.tsx") {
+			continue
+		}
+		name := strings.TrimSuffix(filepath.Base(input), ".tsx")
+
+		t.Run(name, func(t *testing.T) {
+			original, err := os.ReadFile(input)
+			if err != nil {
+				t.Fatalf("Failed to read fixture: %v", err)
+			}
+			want, err := os.ReadFile(filepath.Join(tsxFixturesDir, name+".expected.tsx"))
+			if err != nil {
+				t.Fatalf("Failed to read expected output: %v", err)
+			}
+
+			testFile := filepath.Join(tempDir, name+".tsx")
+			if err := os.WriteFile(testFile, original, 0o644); err != nil {
+				t.Fatalf("Failed to write test file: %v", err)
+			}
+
+			result, err := ProcessFileAST(testFile, Config{Write: true})
+			if err != nil {
+				t.Fatalf("ProcessFileAST failed: %v", err)
+			}
+			wantChanged := string(original) != string(want)
+			if result.Changed != wantChanged {
+				t.Errorf("Changed = %v, want %v", result.Changed, wantChanged)
+			}
+
+			got, err := os.ReadFile(testFile)
+			if err != nil {
+				t.Fatalf("Failed to read file: %v", err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("Content mismatch:\ngot:\n%s\n\nwant:\n%s", got, want)
+			}
+
+			// Sorting the result again must be a no-op
+			result, err = ProcessFileAST(testFile, Config{Write: true})
+			if err != nil {
+				t.Fatalf("ProcessFileAST failed on second pass: %v", err)
+			}
+			if result.Changed {
+				t.Errorf("Second pass reported changes for already sorted output")
+			}
+		})
+	}
+}
+
+func TestProcessorUsesTSXGrammar(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join(tsxFixturesDir, "component.tsx"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+
+	p := NewProcessor()
+	got, err := p.ProcessFile("component.tsx", content)
+	if err != nil {
+		t.Fatalf("ProcessFile failed: %v", err)
+	}
+
+	// The JSX markup outside the sorted structures must survive untouched
+	for _, fragment := range []string{
+		`<section style={theme} className="dashboard">`,
+		`<Row key={item.id} {...item}>`,
+		`export const Empty = () => <div className="empty">Nothing here</div>;`,
+	} {
+		if !strings.Contains(string(got), fragment) {
+			t.Errorf("output lost JSX fragment %q", fragment)
+		}
+	}
+}
+
+func TestProcessContentASTUsesVirtualPath(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join(tsxFixturesDir, "component.tsx"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	expected, err := os.ReadFile(filepath.Join(tsxFixturesDir, "component.expected.tsx"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+
+	// The path does not exist; it only selects the TSX grammar
+	virtualPath := filepath.Join(t.TempDir(), "src", "component.tsx")
+	result, err := ProcessContentAST(virtualPath, content, Config{Write: true})
+	if err != nil {
+		t.Fatalf("ProcessContentAST failed: %v", err)
+	}
+	if !result.Changed {
+		t.Fatal("expected content to need sorting")
+	}
+	if string(result.Sorted) != string(expected) {
+		t.Errorf("sorted content mismatch\nGot:\n%s\nExpected:\n%s", result.Sorted, expected)
+	}
+	if _, err := os.Stat(virtualPath); !os.IsNotExist(err) {
+		t.Errorf("ProcessContentAST must not write files, stat returned %v", err)
+	}
+
+	// Sorted content is left alone
+	result, err = ProcessContentAST(virtualPath, expected, Config{})
+	if err != nil {
+		t.Fatalf("ProcessContentAST failed: %v", err)
+	}
+	if result.Changed || result.Sorted != nil {
+		t.Errorf("expected no changes for sorted content, got Changed=%v", result.Changed)
+	}
+}
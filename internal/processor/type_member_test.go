@@ -0,0 +1,178 @@
+package processor
+
+import (
+	"testing"
+)
+
+func TestProcessContentASTTypeMembers(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name: "interface members by name",
+			input: `interface User {
+  /** tree-sorter-ts: keep-sorted **/
+  name: string;
+  readonly id: string;
+  email?: string; // optional
+  greet(other: User): string;
+}
+`,
+			expected: `interface User {
+  /** tree-sorter-ts: keep-sorted **/
+  email?: string; // optional
+  greet(other: User): string;
+  readonly id: string;
+  name: string;
+}
+`,
+		},
+		{
+			name: "type literal with comma separators",
+			input: `type Options = {
+  /** tree-sorter-ts: keep-sorted **/
+  timeout: number,
+  // Retries before giving up
+  retries: number,
+  debug: boolean,
+};
+`,
+			expected: `type Options = {
+  /** tree-sorter-ts: keep-sorted **/
+  debug: boolean,
+  // Retries before giving up
+  retries: number,
+  timeout: number,
+};
+`,
+		},
+		{
+			name: "members without separators get one when moved",
+			input: `interface Point {
+  /** tree-sorter-ts: keep-sorted **/
+  y: number
+  x: number;
+}
+`,
+			expected: `interface Point {
+  /** tree-sorter-ts: keep-sorted **/
+  x: number;
+  y: number;
+}
+`,
+		},
+		{
+			name: "call, construct and index signatures",
+			input: `interface Factory {
+  /** tree-sorter-ts: keep-sorted **/
+  version: string;
+  new (config: Config): Widget;
+  [key: string]: unknown;
+  (config: Config): Widget;
+}
+`,
+			expected: `interface Factory {
+  /** tree-sorter-ts: keep-sorted **/
+  (config: Config): Widget;
+  [key: string]: unknown;
+  new (config: Config): Widget;
+  version: string;
+}
+`,
+		},
+		{
+			name: "deprecated members at the end",
+			input: `interface Api {
+  /** tree-sorter-ts: keep-sorted deprecated-at-end **/
+  /** @deprecated use fetch */
+  get(): void;
+  fetch(): void;
+  endpoint: string;
+}
+`,
+			expected: `interface Api {
+  /** tree-sorter-ts: keep-sorted deprecated-at-end **/
+  endpoint: string;
+  fetch(): void;
+  /** @deprecated use fetch */
+  get(): void;
+}
+`,
+		},
+		{
+			name: "with new line",
+			input: `interface Theme {
+  /** tree-sorter-ts: keep-sorted with-new-line **/
+  secondary: string;
+  primary: string;
+}
+`,
+			expected: `interface Theme {
+  /** tree-sorter-ts: keep-sorted with-new-line **/
+  primary: string;
+
+  secondary: string;
+}
+`,
+		},
+		{
+			name: "sort by comment",
+			input: `interface Flags {
+  /** tree-sorter-ts: keep-sorted sort-by-comment **/
+  alpha: boolean; // b
+  zeta: boolean; // a
+}
+`,
+			expected: `interface Flags {
+  /** tree-sorter-ts: keep-sorted sort-by-comment **/
+  zeta: boolean; // a
+  alpha: boolean; // b
+}
+`,
+		},
+		{
+			name: "type literal in a parameter",
+			input: `function configure(options: {
+  /** tree-sorter-ts: keep-sorted **/
+  verbose: boolean;
+  level: number;
+}) {}
+`,
+			expected: `function configure(options: {
+  /** tree-sorter-ts: keep-sorted **/
+  level: number;
+  verbose: boolean;
+}) {}
+`,
+		},
+		{
+			name: "section of an interface",
+			input: `interface Events {
+  ready(): void;
+  /** tree-sorter-ts: start-sort **/
+  resize(width: number): void;
+  click(x: number, y: number): void;
+  /** tree-sorter-ts: end-sort **/
+  close(): void;
+}
+`,
+			expected: `interface Events {
+  ready(): void;
+  /** tree-sorter-ts: start-sort **/
+  click(x: number, y: number): void;
+  resize(width: number): void;
+  /** tree-sorter-ts: end-sort **/
+  close(): void;
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertSorted(t, "types.ts", tt.input, tt.expected, KindTypeMembers)
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/types/enums"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+const issueTrackerURL = "https://github.com/evanrichards/tree-sorter-ts/issues"
+
+// VerificationError reports that the sorted output of a file failed the
+// post-sort verification pass. This always indicates a bug in tree-sorter-ts;
+// the file is left untouched.
+type VerificationError struct {
+	Reason  string
+	Details []string
+}
+
+func (e *VerificationError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "internal error: sorted output failed verification (%s); file left unchanged", e.Reason)
+	for _, detail := range e.Details {
+		sb.WriteString("\n    ")
+		sb.WriteString(detail)
+	}
+	fmt.Fprintf(&sb, "\n    Please report this at %s and include the file that triggered it", issueTrackerURL)
+	return sb.String()
+}
+
+// containerSnapshot records the items of a sorted container so the container
+// can be compared before and after sorting
+type containerSnapshot struct {
+	kind  string
+	line  int
+	items map[string]int // Multiset of item and comment texts
+}
+
+// verifySortedContent reparses the sorted content and checks that it introduced
+// no syntax errors and that every keep-sorted container still holds exactly
+// the same items and comments as before
+func verifySortedContent(lang Language, originalRoot *sitter.Node, original, sorted []byte) error {
+	parser := getParser(lang)
+	defer putParser(lang, parser)
+
+	tree, err := parser.ParseCtx(context.Background(), nil, sorted)
+	if err != nil {
+		return &VerificationError{Reason: fmt.Sprintf("reparsing sorted output: %v", err)}
+	}
+	sortedRoot := tree.RootNode()
+
+	if originalErrors, sortedErrors := countSyntaxErrors(originalRoot), countSyntaxErrors(sortedRoot); sortedErrors > originalErrors {
+		parseErr := newParseError(sortedRoot, sorted)
+		return &VerificationError{
+			Reason: "sorted output has new syntax errors",
+			Details: []string{
+				fmt.Sprintf("first error in sorted output at line %d, column %d", parseErr.Line, parseErr.Column),
+			},
+		}
+	}
+
+	before := snapshotContainers(originalRoot, original)
+	after := snapshotContainers(sortedRoot, sorted)
+	if len(before) != len(after) {
+		return &VerificationError{
+			Reason: fmt.Sprintf("found %d keep-sorted structures before sorting but %d after", len(before), len(after)),
+		}
+	}
+
+	var details []string
+	for i := range before {
+		if before[i].kind != after[i].kind {
+			details = append(details, fmt.Sprintf("%s at line %d became a %s", before[i].kind, before[i].line, after[i].kind))
+			continue
+		}
+		for _, text := range multisetDifference(before[i].items, after[i].items) {
+			details = append(details, fmt.Sprintf("%s at line %d lost %q", before[i].kind, before[i].line, text))
+		}
+		for _, text := range multisetDifference(after[i].items, before[i].items) {
+			details = append(details, fmt.Sprintf("%s at line %d gained %q", before[i].kind, before[i].line, text))
+		}
+	}
+	if len(details) > 0 {
+		return &VerificationError{Reason: "sorted structures do not contain the same items", Details: details}
+	}
+
+	return nil
+}
+
+// snapshotContainers records the items of every keep-sorted container that
+// is not nested in another one, in document order per kind
+func snapshotContainers(root *sitter.Node, content []byte) []containerSnapshot {
+	type container struct {
+		kind string
+		node *sitter.Node
+	}
+	var containers []container
+	seen := make(map[containerKey]bool)
+	for _, sortable := range findSortables(root, content) {
+		// Sections of the same container share its node
+		node := sortable.GetNode()
+		if seen[containerKeyOf(node)] {
+			continue
+		}
+		seen[containerKeyOf(node)] = true
+		containers = append(containers, container{kind: kindOf(sortable), node: node})
+	}
+
+	nodes := make(map[containerKey]*sitter.Node, len(containers))
+	for _, c := range containers {
+		nodes[containerKeyOf(c.node)] = c.node
+	}
+
+	snapshots := make([]containerSnapshot, 0, len(containers))
+	for _, c := range containers {
+		if nestedInAny(c.node, nodes) {
+			// Its items are checked as part of the items of its container,
+			// which can move it
+			continue
+		}
+		snapshot := containerSnapshot{
+			kind:  c.kind,
+			line:  int(c.node.StartPoint().Row) + 1,
+			items: make(map[string]int),
+		}
+		for _, item := range containerItems(c.node, content, nodes) {
+			snapshot.items[item]++
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots
+}
+
+// containerKey identifies a container node, since a file and the array it
+// starts with share their start
+type containerKey struct {
+	start, end uint32
+	nodeType   string
+}
+
+// containerKeyOf returns the key of a container node
+func containerKeyOf(node *sitter.Node) containerKey {
+	return containerKey{start: node.StartByte(), end: node.EndByte(), nodeType: node.Type()}
+}
+
+// nestedInAny reports whether node lies inside one of the containers
+func nestedInAny(node *sitter.Node, containers map[containerKey]*sitter.Node) bool {
+	for _, c := range containers {
+		if c != node && c.StartByte() <= node.StartByte() && node.EndByte() <= c.EndByte() {
+			return true
+		}
+	}
+	return false
+}
+
+// containerItems returns the texts of the items and comments of a container.
+// Enum members are compared by value, since sorting may write their values
+// out.
+func containerItems(node *sitter.Node, content []byte, containers map[containerKey]*sitter.Node) []string {
+	var items []string
+	if enums.IsEnumBody(node) {
+		items = enums.MemberValues(node, content)
+		for i := 0; i < int(node.ChildCount()); i++ {
+			if child := node.Child(i); child.Type() == "comment" {
+				items = append(items, child.Content(content))
+			}
+		}
+		return items
+	}
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		switch child.Type() {
+		case "{", "}", "[", "]", "(", ")", ",", ";":
+			// Delimiters and separators may legitimately be added or dropped
+			continue
+		}
+		items = append(items, itemText(child, content, containers))
+	}
+	return items
+}
+
+// itemText returns the text of an item with the keep-sorted containers nested
+// in it written as their items in sorted order, so that sorting those does
+// not change the item
+func itemText(node *sitter.Node, content []byte, containers map[containerKey]*sitter.Node) string {
+	var sb strings.Builder
+	last := node.StartByte()
+	var walk func(n *sitter.Node)
+	walk = func(n *sitter.Node) {
+		if _, ok := containers[containerKeyOf(n)]; ok {
+			items := containerItems(n, content, containers)
+			sort.Strings(items)
+			sb.Write(content[last:n.StartByte()])
+			sb.WriteString("<" + strings.Join(items, ", ") + ">")
+			last = n.EndByte()
+			return
+		}
+		for i := 0; i < int(n.ChildCount()); i++ {
+			walk(n.Child(i))
+		}
+	}
+	walk(node)
+	sb.Write(content[last:node.EndByte()])
+	return sb.String()
+}
+
+// multisetDifference returns the texts that occur more often in a than in b
+func multisetDifference(a, b map[string]int) []string {
+	var diff []string
+	for text, count := range a {
+		for i := b[text]; i < count; i++ {
+			diff = append(diff, text)
+		}
+	}
+	sort.Strings(diff)
+	return diff
+}
+
+// countSyntaxErrors counts the ERROR and MISSING nodes in a tree
+func countSyntaxErrors(node *sitter.Node) int {
+	if node.IsError() || node.IsMissing() {
+		return 1
+	}
+	if !node.HasError() {
+		return 0
+	}
+	count := 0
+	for i := 0; i < int(node.ChildCount()); i++ {
+		count += countSyntaxErrors(node.Child(i))
+	}
+	return count
+}
@@ -0,0 +1,257 @@
+package processor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVerifySortedContent(t *testing.T) {
+	const original = `const config = {
+  /** tree-sorter-ts: keep-sorted **/
+  zebra: 1, // last
+  alpha: 2,
+};
+
+const items = [
+  /** tree-sorter-ts: keep-sorted **/
+  "b",
+  "a",
+];`
+
+	tests := []struct {
+		name       string
+		sorted     string
+		wantReason string // empty means verification should pass
+		wantDetail string
+	}{
+		{
+			name: "valid_sort",
+			sorted: `const config = {
+  /** tree-sorter-ts: keep-sorted **/
+  alpha: 2,
+  zebra: 1, // last
+};
+
+const items = [
+  /** tree-sorter-ts: keep-sorted **/
+  "a",
+  "b",
+];`,
+		},
+		{
+			name: "dropped_property",
+			sorted: `const config = {
+  /** tree-sorter-ts: keep-sorted **/
+  alpha: 2,
+};
+
+const items = [
+  /** tree-sorter-ts: keep-sorted **/
+  "a",
+  "b",
+];`,
+			wantReason: "do not contain the same items",
+			wantDetail: `object at line 1 lost "zebra: 1"`,
+		},
+		{
+			name: "duplicated_comment",
+			sorted: `const config = {
+  /** tree-sorter-ts: keep-sorted **/
+  alpha: 2, // last
+  zebra: 1, // last
+};
+
+const items = [
+  /** tree-sorter-ts: keep-sorted **/
+  "a",
+  "b",
+];`,
+			wantReason: "do not contain the same items",
+			wantDetail: `object at line 1 gained "// last"`,
+		},
+		{
+			name: "new_syntax_error",
+			sorted: `const config = {
+  /** tree-sorter-ts: keep-sorted **/
+  alpha: 2
+  zebra: 1, // last
+};
+
+const items = [
+  /** tree-sorter-ts: keep-sorted **/
+  "a",
+  "b",
+];`,
+			wantReason: "new syntax errors",
+		},
+		{
+			name: "structure_lost",
+			sorted: `const config = {
+  /** tree-sorter-ts: keep-sorted **/
+  alpha: 2,
+  zebra: 1, // last
+};
+
+const items = ["a", "b"];`,
+			wantReason: "found 2 keep-sorted structures before sorting but 1 after",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, content, err := parseTypeScript(original)
+			if err != nil {
+				t.Fatalf("Failed to parse: %v", err)
+			}
+
+			err = verifySortedContent(LanguageTypeScript, root, content, []byte(tt.sorted))
+			if tt.wantReason == "" {
+				if err != nil {
+					t.Fatalf("verification failed unexpectedly: %v", err)
+				}
+				return
+			}
+
+			var verifyErr *VerificationError
+			if !errors.As(err, &verifyErr) {
+				t.Fatalf("expected VerificationError, got %v", err)
+			}
+			if !strings.Contains(verifyErr.Reason, tt.wantReason) {
+				t.Errorf("Reason = %q, want it to contain %q", verifyErr.Reason, tt.wantReason)
+			}
+			if tt.wantDetail != "" && !strings.Contains(strings.Join(verifyErr.Details, "\n"), tt.wantDetail) {
+				t.Errorf("Details = %q, want them to contain %q", verifyErr.Details, tt.wantDetail)
+			}
+		})
+	}
+}
+
+func TestVerifySortedContentNested(t *testing.T) {
+	const original = `const routes = [
+  /** tree-sorter-ts: keep-sorted **/
+  {
+    /** tree-sorter-ts: keep-sorted **/
+    path: "/users",
+    handler: listUsers,
+  },
+  "/teams",
+];`
+
+	tests := []struct {
+		name       string
+		sorted     string
+		wantDetail string // empty means verification should pass
+	}{
+		{
+			name: "inner_sorted_and_moved",
+			sorted: `const routes = [
+  /** tree-sorter-ts: keep-sorted **/
+  "/teams",
+  {
+    /** tree-sorter-ts: keep-sorted **/
+    handler: listUsers,
+    path: "/users",
+  },
+];`,
+		},
+		{
+			name: "inner_property_dropped",
+			sorted: `const routes = [
+  /** tree-sorter-ts: keep-sorted **/
+  "/teams",
+  {
+    /** tree-sorter-ts: keep-sorted **/
+    path: "/users",
+  },
+];`,
+			wantDetail: `array at line 1 lost`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, content, err := parseTypeScript(original)
+			if err != nil {
+				t.Fatalf("Failed to parse: %v", err)
+			}
+
+			err = verifySortedContent(LanguageTypeScript, root, content, []byte(tt.sorted))
+			if tt.wantDetail == "" {
+				if err != nil {
+					t.Fatalf("verification failed unexpectedly: %v", err)
+				}
+				return
+			}
+
+			var verifyErr *VerificationError
+			if !errors.As(err, &verifyErr) {
+				t.Fatalf("expected VerificationError, got %v", err)
+			}
+			if !strings.Contains(strings.Join(verifyErr.Details, "\n"), tt.wantDetail) {
+				t.Errorf("Details = %q, want them to contain %q", verifyErr.Details, tt.wantDetail)
+			}
+		})
+	}
+}
+
+func TestVerificationLeavesFileUntouched(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{
+			// Shorthand properties and spreads are not extracted as sortable
+			// items, so reconstructing the object would drop them
+			name: "shorthand_and_spread_properties",
+			input: `const config = {
+  /** tree-sorter-ts: keep-sorted **/
+  beta: 1,
+  alpha,
+  delta: 2,
+  ...rest,
+};`,
+		},
+		{
+			// Known bug: the inline comment of the last property is repeated
+			name: "object_sort_by_comment_duplication",
+			input: `const user = {
+  /** tree-sorter-ts: keep-sorted sort-by-comment */
+  id: "u_123", // Unique identifier
+  name: "John Doe", // Display name
+  email: "user@example.com", // Contact info
+};`,
+		},
+	}
+
+	tempDir := t.TempDir()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testFile := filepath.Join(tempDir, tt.name+".ts")
+			if err := os.WriteFile(testFile, []byte(tt.input), 0o644); err != nil {
+				t.Fatalf("Failed to write test file: %v", err)
+			}
+
+			_, err := ProcessFileAST(testFile, Config{Write: true})
+
+			var verifyErr *VerificationError
+			if !errors.As(err, &verifyErr) {
+				t.Fatalf("expected VerificationError, got %v", err)
+			}
+			if !strings.Contains(err.Error(), issueTrackerURL) {
+				t.Errorf("error report should point at the issue tracker, got:\n%s", err)
+			}
+
+			got, err := os.ReadFile(testFile)
+			if err != nil {
+				t.Fatalf("Failed to read file: %v", err)
+			}
+			if string(got) != tt.input {
+				t.Errorf("file was modified despite failed verification:\n%s", got)
+			}
+		})
+	}
+}
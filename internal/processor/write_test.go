@@ -64,17 +64,17 @@ func TestWriteFunctionality(t *testing.T) {
 			name: "multiline_values_write",
 			initialContent: `const messages = {
   /** tree-sorter-ts: keep-sorted **/
-  error: \` + "`" + `This is
+  error: ` + "`" + `This is
 a multiline
-error\` + "`" + `,
+error` + "`" + `,
   alert: "Alert!",
 };`,
 			expectedContent: `const messages = {
   /** tree-sorter-ts: keep-sorted **/
   alert: "Alert!",
-  error: \` + "`" + `This is
+  error: ` + "`" + `This is
 a multiline
-error\` + "`" + `,
+error` + "`" + `,
 };`,
 			shouldChange: true,
 		},
@@ -19,133 +19,107 @@ func NewArrayReconstructor() *ArrayReconstructor {
 	return &ArrayReconstructor{}
 }
 
-// Reconstruct generates new content with sorted array elements
-func (r *ArrayReconstructor) Reconstruct(sortable interfaces.Sortable, sortedItems []interfaces.SortableItem, configInterface interface{}, content []byte) ([]byte, error) {
+// isElement reports whether an array child is an element
+func isElement(node *sitter.Node) bool {
+	return node.Type() != "," && node.Type() != "comment" && node.Type() != "]"
+}
+
+// Reconstruct returns the array's text with its elements in sorted order
+func (r *ArrayReconstructor) Reconstruct(sortable interfaces.Sortable, sortedItems []interfaces.SortableItem, cfg config.SortConfig, content []byte) ([]byte, error) {
 	arraySorter, ok := sortable.(*arrays.ArraySorter)
 	if !ok {
 		return nil, fmt.Errorf("expected ArraySorter, got %T", sortable)
 	}
-	
-	cfg, ok := configInterface.(config.SortConfig)
-	if !ok {
-		return nil, fmt.Errorf("expected config.SortConfig, got %T", configInterface)
-	}
 
 	arrayNode := arraySorter.GetNode()
 	magicIndex := arraySorter.GetMagicCommentIndex()
+	endIndex := arraySorter.GetEndIndex()
 
 	var result bytes.Buffer
 
-	// Write content before the array
-	result.Write(content[:arrayNode.StartByte()])
+	// Single-line arrays (all elements on the same line) stay on one line
+	isSingleLine := len(sortedItems) > 0 &&
+		sortedItems[0].GetNode().StartPoint().Row == sortedItems[len(sortedItems)-1].GetNode().EndPoint().Row
 
-	// Write opening bracket and up to magic comment
-	result.WriteByte('[')
+	// Use the indentation of the first original element for all elements
+	commonIndent := firstChildIndent(arrayNode, magicIndex, endIndex, isElement, content)
 
-	// Find and write content up to magic comment
-	for i := 0; i <= magicIndex; i++ {
-		child := arrayNode.Child(i)
-		if i == 0 && child.Type() == "[" {
-			continue // Skip opening bracket, already written
-		}
-		
-		// Add whitespace/newlines before child if needed
-		if i > 0 {
-			prevChild := arrayNode.Child(i - 1)
-			r.writeWhitespaceBetween(prevChild, child, content, &result)
-		} else {
-			// First child after opening bracket
-			r.writeWhitespaceAfter(arrayNode, child, content, &result)
-		}
-		
-		// Write the child content
-		result.Write(content[child.StartByte():child.EndByte()])
+	writeThroughMagicComment(&result, arrayNode, magicIndex, content)
+
+	// Write newline after magic comment (unless single line)
+	if magicIndex+1 < int(arrayNode.ChildCount()) && !isSingleLine {
+		result.WriteByte('\n')
 	}
 
-	// Write sorted elements
+	hadTrailingComma := lastItemHasComma(arrayNode, magicIndex, endIndex, isElement)
+
 	for i, item := range sortedItems {
-		elem := item.(*arrays.Element)
-		
-		// Add appropriate spacing/newlines
-		if i == 0 {
-			// First element - check if we need newline after magic comment
-			if cfg.WithNewLine {
+		elem, ok := item.(*arrays.Element)
+		if !ok {
+			return nil, fmt.Errorf("expected Element, got %T", item)
+		}
+
+		if isSingleLine {
+			// For single-line arrays, write minimal spacing
+			if i == 0 {
 				result.WriteByte('\n')
-			} else {
-				result.WriteByte(' ')
+				result.WriteString(commonIndent)
 			}
 		} else {
-			// Subsequent elements - use original spacing pattern
-			result.WriteByte('\n')
+			writeBeforeComments(&result, elem.BeforeNodes, content)
+			result.WriteString(commonIndent)
 		}
 
-		// Write before comments (if any)
-		for _, comment := range elem.BeforeNodes {
-			r.writeIndentation(&result)
-			result.Write(content[comment.StartByte():comment.EndByte()])
-			result.WriteByte('\n')
-		}
-
-		// Write the element itself with proper indentation
-		r.writeIndentation(&result)
 		result.Write(content[elem.Node.StartByte():elem.Node.EndByte()])
 
-		// Write comma if present
-		if elem.HasComma {
+		if i < len(sortedItems)-1 {
+			writeSeparator(&result, elem.CommaNode, content)
+			if isSingleLine {
+				result.WriteByte(' ')
+			}
+		} else if hadTrailingComma {
 			result.WriteByte(',')
 		}
 
-		// Write after comment if present (inline comment)
-		if elem.AfterNode != nil {
-			result.WriteByte(' ')
-			result.Write(content[elem.AfterNode.StartByte():elem.AfterNode.EndByte()])
-		}
-	}
+		writeAfterComment(&result, elem.AfterNode, content)
 
-	// Find closing bracket and write final content
-	for i := int(arrayNode.ChildCount()) - 1; i >= 0; i-- {
-		child := arrayNode.Child(i)
-		if child.Type() == "]" {
-			// Add newline before closing bracket if we have elements
-			if len(sortedItems) > 0 {
+		if !isSingleLine && i < len(sortedItems)-1 {
+			result.WriteByte('\n')
+			// Add extra newline if with-new-line option is set
+			if cfg.WithNewLine {
 				result.WriteByte('\n')
 			}
-			result.Write(content[child.StartByte():child.EndByte()])
-			break
 		}
 	}
 
-	// Write content after the array
-	result.Write(content[arrayNode.EndByte():])
+	if closing := closingNode(arrayNode, endIndex, "]"); closing != nil {
+		result.Write(closingSpacing(arrayNode, closing, content))
+		result.Write(content[closing.StartByte():arrayNode.EndByte()])
+	}
 
 	return result.Bytes(), nil
 }
 
-// writeWhitespaceBetween writes whitespace/newlines between two nodes
-func (r *ArrayReconstructor) writeWhitespaceBetween(prev, current *sitter.Node, content []byte, result *bytes.Buffer) {
-	if prev.EndByte() < current.StartByte() {
-		whitespace := content[prev.EndByte():current.StartByte()]
-		result.Write(whitespace)
+// closingSpacing returns the whitespace to write before the closing bracket
+// or end-sort marker. Only the whitespace directly before it is reused, since
+// inline comments are written along with their elements.
+func closingSpacing(arrayNode, closing *sitter.Node, content []byte) []byte {
+	end := closing.StartByte()
+	start := end
+	for start > arrayNode.StartByte() && (content[start-1] == ' ' || content[start-1] == '\t' || content[start-1] == '\n' || content[start-1] == '\r') {
+		start--
 	}
-}
 
-// writeWhitespaceAfter writes whitespace after opening bracket to first child
-func (r *ArrayReconstructor) writeWhitespaceAfter(parent, child *sitter.Node, content []byte, result *bytes.Buffer) {
-	// Find opening bracket
-	openBracketEnd := parent.StartByte() + 1 // Assume '[' is one byte
-	if openBracketEnd < child.StartByte() {
-		whitespace := content[openBracketEnd:child.StartByte()]
-		result.Write(whitespace)
+	spacing := content[start:end]
+	if !bytes.ContainsRune(spacing, '\n') {
+		return []byte("\n")
 	}
-}
-
-// writeIndentation writes proper indentation (2 spaces)
-func (r *ArrayReconstructor) writeIndentation(result *bytes.Buffer) {
-	result.WriteString("  ")
+	// Drop any blank lines, keeping only the indentation of the closing line
+	return spacing[bytes.LastIndexByte(spacing, '\n'):]
 }
 
 // CanHandle returns true if this reconstructor can handle the given sortable
 func (r *ArrayReconstructor) CanHandle(sortable interfaces.Sortable) bool {
 	_, ok := sortable.(*arrays.ArraySorter)
 	return ok
-}
\ No newline at end of file
+}
@@ -0,0 +1,86 @@
+package reconstruction
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/config"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/interfaces"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/types/members"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// ClassReconstructor rebuilds class bodies with sorted members
+type ClassReconstructor struct{}
+
+// NewClassReconstructor creates a new class reconstructor
+func NewClassReconstructor() *ClassReconstructor {
+	return &ClassReconstructor{}
+}
+
+// Reconstruct returns the class body's text with its members in sorted order
+func (r *ClassReconstructor) Reconstruct(sortable interfaces.Sortable, sortedItems []interfaces.SortableItem, cfg config.SortConfig, content []byte) ([]byte, error) {
+	classSorter, ok := sortable.(*members.ClassSorter)
+	if !ok {
+		return nil, fmt.Errorf("expected ClassSorter, got %T", sortable)
+	}
+
+	bodyNode := classSorter.GetNode()
+	magicIndex := classSorter.GetMagicCommentIndex()
+	endIndex := classSorter.GetEndIndex()
+
+	var result bytes.Buffer
+
+	// Use the indentation of the first original member for all members
+	commonIndent := firstChildIndent(bodyNode, magicIndex, endIndex, members.IsMemberNode, content)
+
+	writeThroughMagicComment(&result, bodyNode, magicIndex, content)
+
+	// Write newline after magic comment
+	if magicIndex+1 < int(bodyNode.ChildCount()) {
+		result.WriteByte('\n')
+	}
+
+	for i, item := range sortedItems {
+		member, ok := item.(*members.Member)
+		if !ok {
+			return nil, fmt.Errorf("expected Member, got %T", item)
+		}
+
+		writeBeforeComments(&result, member.BeforeNodes, content)
+
+		// Decorators and overload signatures are written along with the
+		// member, as they were
+		result.WriteString(commonIndent)
+		result.Write(content[member.FirstNode.StartByte():member.LastNode.EndByte()])
+
+		writeAfterComment(&result, member.AfterNode, content)
+
+		if i < len(sortedItems)-1 {
+			result.WriteByte('\n')
+			// Add extra newline if with-new-line option is set
+			if cfg.WithNewLine {
+				result.WriteByte('\n')
+			}
+		}
+	}
+
+	// Comments after the last member stay at the end, unless they are the
+	// inline comment of the member, which moved with it
+	writeClosing(&result, bodyNode, magicIndex, endIndex, "}", func(n *sitter.Node) bool {
+		if n.Type() == "comment" {
+			prev := n.PrevSibling()
+			return prev != nil && prev.Type() != "comment" && prev.EndPoint().Row == n.StartPoint().Row
+		}
+		return members.IsMemberNode(n) || n.Type() == ";"
+	}, content)
+
+	return result.Bytes(), nil
+}
+
+// CanHandle returns true if this reconstructor can handle the given sortable
+func (r *ClassReconstructor) CanHandle(sortable interfaces.Sortable) bool {
+	_, ok := sortable.(*members.ClassSorter)
+	return ok
+}
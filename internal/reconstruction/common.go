@@ -0,0 +1,141 @@
+package reconstruction
+
+import (
+	"bytes"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// lineIndent returns the text between the start of the line holding offset
+// and offset itself, normally the indentation of whatever starts there
+func lineIndent(content []byte, offset uint32) string {
+	lineStart := offset
+	for lineStart > 0 && content[lineStart-1] != '\n' {
+		lineStart--
+	}
+	return string(content[lineStart:offset])
+}
+
+// firstChildIndent returns the indentation of the first item that isItem
+// accepts between the magic comment and endIndex
+func firstChildIndent(node *sitter.Node, magicIndex, endIndex int, isItem func(*sitter.Node) bool, content []byte) string {
+	for i := magicIndex + 1; i < endIndex; i++ {
+		child := node.Child(i)
+		if isItem(child) {
+			return lineIndent(content, child.StartByte())
+		}
+	}
+	return ""
+}
+
+// writeThroughMagicComment writes the node's children up to and including the
+// magic comment, along with the original text between them
+func writeThroughMagicComment(result *bytes.Buffer, node *sitter.Node, magicIndex int, content []byte) {
+	for i := 0; i <= magicIndex; i++ {
+		child := node.Child(i)
+
+		// Write any whitespace before this child
+		if i == 0 {
+			result.Write(content[node.StartByte():child.StartByte()])
+		} else {
+			prevChild := node.Child(i - 1)
+			result.Write(content[prevChild.EndByte():child.StartByte()])
+		}
+
+		// Write the child itself
+		result.Write(content[child.StartByte():child.EndByte()])
+	}
+}
+
+// writeBeforeComments writes the comments leading an item, each on its own
+// line with its original indentation
+func writeBeforeComments(result *bytes.Buffer, comments []*sitter.Node, content []byte) {
+	for _, commentNode := range comments {
+		result.WriteString(lineIndent(content, commentNode.StartByte()))
+		result.Write(content[commentNode.StartByte():commentNode.EndByte()])
+		result.WriteByte('\n')
+	}
+}
+
+// writeSeparator writes the comma following an item that is not the last,
+// keeping the original comma when there is one
+func writeSeparator(result *bytes.Buffer, commaNode *sitter.Node, content []byte) {
+	if commaNode != nil {
+		result.Write(content[commaNode.StartByte():commaNode.EndByte()])
+	} else {
+		// Add comma if missing
+		result.WriteByte(',')
+	}
+}
+
+// writeAfterComment writes an item's inline comment, if it has one
+func writeAfterComment(result *bytes.Buffer, afterNode *sitter.Node, content []byte) {
+	if afterNode != nil {
+		result.WriteByte(' ')
+		result.Write(content[afterNode.StartByte():afterNode.EndByte()])
+	}
+}
+
+// lastItemHasComma reports whether the last item that isItem accepts between
+// the magic comment and endIndex is directly followed by a comma
+func lastItemHasComma(node *sitter.Node, magicIndex, endIndex int, isItem func(*sitter.Node) bool) bool {
+	hasComma := false
+	for i := magicIndex + 1; i < endIndex; i++ {
+		if !isItem(node.Child(i)) {
+			continue
+		}
+		hasComma = i+1 < endIndex && node.Child(i+1).Type() == ","
+	}
+	return hasComma
+}
+
+// closingNode returns the child the items end at: the end-sort marker at
+// endIndex for a section, otherwise the last child of the given delimiter
+// type, or nil when the node has none besides its first child or no
+// delimiter is given
+func closingNode(node *sitter.Node, endIndex int, delimiter string) *sitter.Node {
+	if endIndex < int(node.ChildCount()) {
+		return node.Child(endIndex)
+	}
+	if delimiter == "" {
+		return nil
+	}
+	for i := int(node.ChildCount()) - 1; i > 0; i-- {
+		child := node.Child(i)
+		if child.Type() == delimiter {
+			return child
+		}
+	}
+	return nil
+}
+
+// writeClosing writes the original text between the last child before
+// endIndex that isContent accepts and the closing node, or a newline when
+// there is none, followed by the rest of the node from the closing node on.
+// Nodes without a closing node, such as a whole file, end with their
+// original text after the last content.
+func writeClosing(result *bytes.Buffer, node *sitter.Node, magicIndex, endIndex int, delimiter string, isContent func(*sitter.Node) bool, content []byte) {
+	lastContentEnd := node.Child(magicIndex).EndByte()
+	for i := endIndex - 1; i > magicIndex; i-- {
+		child := node.Child(i)
+		if isContent(child) {
+			lastContentEnd = child.EndByte()
+			break
+		}
+	}
+
+	closing := closingNode(node, endIndex, delimiter)
+	if closing == nil {
+		if delimiter == "" {
+			result.Write(content[lastContentEnd:node.EndByte()])
+		}
+		return
+	}
+
+	if spacing := content[lastContentEnd:closing.StartByte()]; len(spacing) > 0 {
+		result.Write(spacing)
+	} else {
+		result.WriteByte('\n')
+	}
+	result.Write(content[closing.StartByte():node.EndByte()])
+}
@@ -0,0 +1,108 @@
+package reconstruction
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/config"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/interfaces"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/types/constructors"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// ConstructorReconstructor rebuilds parameter lists with sorted parameters
+type ConstructorReconstructor struct{}
+
+// NewConstructorReconstructor creates a new constructor reconstructor
+func NewConstructorReconstructor() *ConstructorReconstructor {
+	return &ConstructorReconstructor{}
+}
+
+// Reconstruct returns the parameter list's text with its parameters in
+// sorted order
+func (r *ConstructorReconstructor) Reconstruct(sortable interfaces.Sortable, sortedItems []interfaces.SortableItem, cfg config.SortConfig, content []byte) ([]byte, error) {
+	constructorSorter, ok := sortable.(*constructors.ConstructorSorter)
+	if !ok {
+		return nil, fmt.Errorf("expected ConstructorSorter, got %T", sortable)
+	}
+
+	paramsNode := constructorSorter.GetNode()
+	magicIndex := constructorSorter.GetMagicCommentIndex()
+	endIndex := constructorSorter.GetEndIndex()
+
+	var result bytes.Buffer
+
+	// Use the indentation of the first original parameter for all parameters
+	commonIndent := firstChildIndent(paramsNode, magicIndex, endIndex, constructors.IsParameter, content)
+
+	writeThroughMagicComment(&result, paramsNode, magicIndex, content)
+
+	// Write newline after magic comment
+	if magicIndex+1 < int(paramsNode.ChildCount()) {
+		result.WriteByte('\n')
+	}
+
+	hadTrailingComma := lastParameterHasComma(paramsNode, magicIndex, endIndex)
+
+	for i, item := range sortedItems {
+		param, ok := item.(*constructors.Parameter)
+		if !ok {
+			return nil, fmt.Errorf("expected Parameter, got %T", item)
+		}
+
+		writeBeforeComments(&result, param.BeforeNodes, content)
+
+		result.WriteString(commonIndent)
+		result.Write(content[param.Node.StartByte():param.Node.EndByte()])
+
+		if i < len(sortedItems)-1 {
+			writeSeparator(&result, param.CommaNode, content)
+		} else if hadTrailingComma {
+			result.WriteByte(',')
+		}
+
+		writeAfterComment(&result, param.AfterNode, content)
+
+		if i < len(sortedItems)-1 {
+			result.WriteByte('\n')
+			// Add extra newline if with-new-line option is set
+			if cfg.WithNewLine {
+				result.WriteByte('\n')
+			}
+		}
+	}
+
+	writeClosing(&result, paramsNode, magicIndex, endIndex, ")", func(n *sitter.Node) bool {
+		return constructors.IsParameter(n) || n.Type() == "," || n.Type() == "comment"
+	}, content)
+
+	return result.Bytes(), nil
+}
+
+// lastParameterHasComma reports whether a comma follows the last parameter
+// before endIndex, possibly after its inline comment
+func lastParameterHasComma(paramsNode *sitter.Node, magicIndex, endIndex int) bool {
+	last := -1
+	for i := magicIndex + 1; i < endIndex; i++ {
+		if constructors.IsParameter(paramsNode.Child(i)) {
+			last = i
+		}
+	}
+	if last < 0 {
+		return false
+	}
+
+	for i := last + 1; i < endIndex; i++ {
+		if paramsNode.Child(i).Type() == "," {
+			return true
+		}
+	}
+	return false
+}
+
+// CanHandle returns true if this reconstructor can handle the given sortable
+func (r *ConstructorReconstructor) CanHandle(sortable interfaces.Sortable) bool {
+	_, ok := sortable.(*constructors.ConstructorSorter)
+	return ok
+}
@@ -0,0 +1,120 @@
+package reconstruction
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/config"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/interfaces"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/types/enums"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// EnumReconstructor rebuilds enum bodies with sorted members
+type EnumReconstructor struct{}
+
+// NewEnumReconstructor creates a new enum reconstructor
+func NewEnumReconstructor() *EnumReconstructor {
+	return &EnumReconstructor{}
+}
+
+// Reconstruct returns the enum body's text with its members in sorted order
+func (r *EnumReconstructor) Reconstruct(sortable interfaces.Sortable, sortedItems []interfaces.SortableItem, cfg config.SortConfig, content []byte) ([]byte, error) {
+	enumSorter, ok := sortable.(*enums.EnumSorter)
+	if !ok {
+		return nil, fmt.Errorf("expected EnumSorter, got %T", sortable)
+	}
+
+	bodyNode := enumSorter.GetNode()
+	magicIndex := enumSorter.GetMagicCommentIndex()
+	endIndex := enumSorter.GetEndIndex()
+
+	var result bytes.Buffer
+
+	// Use the indentation of the first original member for all members
+	commonIndent := firstChildIndent(bodyNode, magicIndex, endIndex, enums.IsEnumMember, content)
+
+	writeThroughMagicComment(&result, bodyNode, magicIndex, content)
+
+	// Write newline after magic comment
+	if magicIndex+1 < int(bodyNode.ChildCount()) {
+		result.WriteByte('\n')
+	}
+
+	hadTrailingComma := lastItemHasComma(bodyNode, magicIndex, endIndex, enums.IsEnumMember)
+
+	for i, item := range sortedItems {
+		member, ok := item.(*enums.EnumMember)
+		if !ok {
+			return nil, fmt.Errorf("expected EnumMember, got %T", item)
+		}
+
+		writeBeforeComments(&result, member.BeforeNodes, content)
+
+		result.WriteString(commonIndent)
+		if err := writeEnumMember(&result, member, content); err != nil {
+			return nil, err
+		}
+
+		if i < len(sortedItems)-1 {
+			writeSeparator(&result, member.CommaNode, content)
+		} else if hadTrailingComma {
+			result.WriteByte(',')
+		}
+
+		writeAfterComment(&result, member.AfterNode, content)
+
+		if i < len(sortedItems)-1 {
+			result.WriteByte('\n')
+			// Add extra newline if with-new-line option is set
+			if cfg.WithNewLine {
+				result.WriteByte('\n')
+			}
+		}
+	}
+
+	// Comments after the last member stay at the end, unless they are the
+	// inline comment of the member, which moved with it
+	writeClosing(&result, bodyNode, magicIndex, endIndex, "}", func(n *sitter.Node) bool {
+		if n.Type() == "comment" {
+			prev := n.PrevSibling()
+			return prev != nil && prev.Type() != "comment" && prev.EndPoint().Row == n.StartPoint().Row
+		}
+		return enums.IsEnumMember(n) || n.Type() == ","
+	}, content)
+
+	return result.Bytes(), nil
+}
+
+// writeEnumMember writes a member as it is, or with its value written out
+// when the value depends on the member's position or on other members
+func writeEnumMember(result *bytes.Buffer, member *enums.EnumMember, content []byte) error {
+	if !member.Materialize {
+		result.Write(content[member.Node.StartByte():member.Node.EndByte()])
+		return nil
+	}
+
+	if member.Value == nil {
+		return fmt.Errorf("enum member %q has no constant value to write out", member.Name)
+	}
+	literal, ok := member.Value.Literal()
+	if !ok {
+		return fmt.Errorf("enum member %q has no constant value to write out", member.Name)
+	}
+
+	name := member.Node
+	if member.Node.Type() == "enum_assignment" {
+		name = member.Node.ChildByFieldName("name")
+	}
+	result.Write(content[name.StartByte():name.EndByte()])
+	result.WriteString(" = ")
+	result.WriteString(literal)
+	return nil
+}
+
+// CanHandle returns true if this reconstructor can handle the given sortable
+func (r *EnumReconstructor) CanHandle(sortable interfaces.Sortable) bool {
+	_, ok := sortable.(*enums.EnumSorter)
+	return ok
+}
@@ -17,6 +17,11 @@ func NewFactory() *Factory {
 		reconstructors: []interfaces.Reconstructor{
 			NewObjectReconstructor(),
 			NewArrayReconstructor(),
+			NewConstructorReconstructor(),
+			NewStatementReconstructor(),
+			NewClassReconstructor(),
+			NewTypeReconstructor(),
+			NewEnumReconstructor(),
 		},
 	}
 }
@@ -24,23 +29,15 @@ func NewFactory() *Factory {
 // CreateReconstructor returns the appropriate reconstructor for the given sortable
 func (f *Factory) CreateReconstructor(sortable interfaces.Sortable) (interfaces.Reconstructor, error) {
 	for _, reconstructor := range f.reconstructors {
-		// Check if reconstructor can handle this type
-		switch r := reconstructor.(type) {
-		case *ObjectReconstructor:
-			if r.CanHandle(sortable) {
-				return r, nil
-			}
-		case *ArrayReconstructor:
-			if r.CanHandle(sortable) {
-				return r, nil
-			}
+		if reconstructor.CanHandle(sortable) {
+			return reconstructor, nil
 		}
 	}
-	
+
 	return nil, fmt.Errorf("no reconstructor found for sortable type %T", sortable)
 }
 
 // GetSupportedTypes returns the types of sortables this factory supports
 func (f *Factory) GetSupportedTypes() []string {
-	return []string{"objects.ObjectSorter", "arrays.ArraySorter"}
-}
\ No newline at end of file
+	return []string{"objects.ObjectSorter", "arrays.ArraySorter", "constructors.ConstructorSorter", "statements.StatementSorter", "members.ClassSorter", "typemembers.TypeSorter", "enums.EnumSorter"}
+}
@@ -0,0 +1,91 @@
+package reconstruction
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/config"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/interfaces"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/types/statements"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// StatementReconstructor rebuilds statement lists with sorted statements
+type StatementReconstructor struct{}
+
+// NewStatementReconstructor creates a new statement reconstructor
+func NewStatementReconstructor() *StatementReconstructor {
+	return &StatementReconstructor{}
+}
+
+// Reconstruct returns the statement list's text with its statements in
+// sorted order
+func (r *StatementReconstructor) Reconstruct(sortable interfaces.Sortable, sortedItems []interfaces.SortableItem, cfg config.SortConfig, content []byte) ([]byte, error) {
+	statementSorter, ok := sortable.(*statements.StatementSorter)
+	if !ok {
+		return nil, fmt.Errorf("expected StatementSorter, got %T", sortable)
+	}
+
+	listNode := statementSorter.GetNode()
+	magicIndex := statementSorter.GetMagicCommentIndex()
+	endIndex := statementSorter.GetEndIndex()
+
+	// Only namespace and module bodies close with a brace; a file just ends
+	delimiter := ""
+	if listNode.Type() == "statement_block" {
+		delimiter = "}"
+	}
+
+	var result bytes.Buffer
+
+	// Use the indentation of the first original statement for all statements
+	commonIndent := firstChildIndent(listNode, magicIndex, endIndex, statements.IsStatement, content)
+
+	writeThroughMagicComment(&result, listNode, magicIndex, content)
+
+	// Write newline after magic comment
+	if magicIndex+1 < int(listNode.ChildCount()) {
+		result.WriteByte('\n')
+	}
+
+	for i, item := range sortedItems {
+		statement, ok := item.(*statements.Statement)
+		if !ok {
+			return nil, fmt.Errorf("expected Statement, got %T", item)
+		}
+
+		writeBeforeComments(&result, statement.BeforeNodes, content)
+
+		result.WriteString(commonIndent)
+		result.Write(content[statement.Node.StartByte():statement.Node.EndByte()])
+
+		writeAfterComment(&result, statement.AfterNode, content)
+
+		if i < len(sortedItems)-1 {
+			result.WriteByte('\n')
+			// Add extra newline if with-new-line option is set
+			if cfg.WithNewLine {
+				result.WriteByte('\n')
+			}
+		}
+	}
+
+	// Comments after the last statement stay at the end, unless they are the
+	// inline comment of the statement, which moved with it
+	writeClosing(&result, listNode, magicIndex, endIndex, delimiter, func(n *sitter.Node) bool {
+		if n.Type() == "comment" {
+			prev := n.PrevSibling()
+			return prev != nil && statements.IsStatement(prev) && prev.EndPoint().Row == n.StartPoint().Row
+		}
+		return statements.IsStatement(n)
+	}, content)
+
+	return result.Bytes(), nil
+}
+
+// CanHandle returns true if this reconstructor can handle the given sortable
+func (r *StatementReconstructor) CanHandle(sortable interfaces.Sortable) bool {
+	_, ok := sortable.(*statements.StatementSorter)
+	return ok
+}
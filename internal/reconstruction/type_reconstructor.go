@@ -0,0 +1,122 @@
+package reconstruction
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/config"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/interfaces"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/types/typemembers"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// TypeReconstructor rebuilds interface bodies and type literals with sorted
+// members
+type TypeReconstructor struct{}
+
+// NewTypeReconstructor creates a new type reconstructor
+func NewTypeReconstructor() *TypeReconstructor {
+	return &TypeReconstructor{}
+}
+
+// Reconstruct returns the type body's text with its members in sorted order
+func (r *TypeReconstructor) Reconstruct(sortable interfaces.Sortable, sortedItems []interfaces.SortableItem, cfg config.SortConfig, content []byte) ([]byte, error) {
+	typeSorter, ok := sortable.(*typemembers.TypeSorter)
+	if !ok {
+		return nil, fmt.Errorf("expected TypeSorter, got %T", sortable)
+	}
+
+	bodyNode := typeSorter.GetNode()
+	magicIndex := typeSorter.GetMagicCommentIndex()
+	endIndex := typeSorter.GetEndIndex()
+
+	var result bytes.Buffer
+
+	// Use the indentation of the first original member for all members
+	commonIndent := firstChildIndent(bodyNode, magicIndex, endIndex, typemembers.IsTypeMember, content)
+
+	writeThroughMagicComment(&result, bodyNode, magicIndex, content)
+
+	// Write newline after magic comment
+	if magicIndex+1 < int(bodyNode.ChildCount()) {
+		result.WriteByte('\n')
+	}
+
+	separator := defaultSeparator(sortedItems, content)
+	hadTrailingSeparator := lastItemHasSeparator(bodyNode, magicIndex, endIndex)
+
+	for i, item := range sortedItems {
+		member, ok := item.(*typemembers.TypeMember)
+		if !ok {
+			return nil, fmt.Errorf("expected TypeMember, got %T", item)
+		}
+
+		writeBeforeComments(&result, member.BeforeNodes, content)
+
+		result.WriteString(commonIndent)
+		result.Write(content[member.Node.StartByte():member.Node.EndByte()])
+
+		// Members keep their own separator; the ones without get the one
+		// the other members use
+		if i < len(sortedItems)-1 || hadTrailingSeparator {
+			if member.SeparatorNode != nil {
+				result.Write(content[member.SeparatorNode.StartByte():member.SeparatorNode.EndByte()])
+			} else {
+				result.WriteString(separator)
+			}
+		}
+
+		writeAfterComment(&result, member.AfterNode, content)
+
+		if i < len(sortedItems)-1 {
+			result.WriteByte('\n')
+			// Add extra newline if with-new-line option is set
+			if cfg.WithNewLine {
+				result.WriteByte('\n')
+			}
+		}
+	}
+
+	// Comments after the last member stay at the end, unless they are the
+	// inline comment of the member, which moved with it
+	writeClosing(&result, bodyNode, magicIndex, endIndex, "}", func(n *sitter.Node) bool {
+		if n.Type() == "comment" {
+			prev := n.PrevSibling()
+			return prev != nil && prev.Type() != "comment" && prev.EndPoint().Row == n.StartPoint().Row
+		}
+		return typemembers.IsTypeMember(n) || typemembers.IsSeparator(n)
+	}, content)
+
+	return result.Bytes(), nil
+}
+
+// defaultSeparator returns the separator of the first member that has one,
+// or none when the members are only separated by line breaks
+func defaultSeparator(items []interfaces.SortableItem, content []byte) string {
+	for _, item := range items {
+		if member, ok := item.(*typemembers.TypeMember); ok && member.SeparatorNode != nil {
+			return string(content[member.SeparatorNode.StartByte():member.SeparatorNode.EndByte()])
+		}
+	}
+	return ""
+}
+
+// lastItemHasSeparator reports whether the last member before endIndex is
+// followed by a separator
+func lastItemHasSeparator(bodyNode *sitter.Node, magicIndex, endIndex int) bool {
+	hasSeparator := false
+	for i := magicIndex + 1; i < endIndex; i++ {
+		if !typemembers.IsTypeMember(bodyNode.Child(i)) {
+			continue
+		}
+		hasSeparator = i+1 < endIndex && typemembers.IsSeparator(bodyNode.Child(i+1))
+	}
+	return hasSeparator
+}
+
+// CanHandle returns true if this reconstructor can handle the given sortable
+func (r *TypeReconstructor) CanHandle(sortable interfaces.Sortable) bool {
+	_, ok := sortable.(*typemembers.TypeSorter)
+	return ok
+}
@@ -0,0 +1,87 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/processor"
+)
+
+// WriteGitHubAnnotations writes GitHub Actions workflow commands that annotate
+// the magic comment of every unsorted structure, so the problems show up
+// inline on pull requests. Check mode reports errors and dry-run mode
+// warnings; write mode has already fixed everything and reports nothing but
+// files that could not be processed.
+func WriteGitHubAnnotations(w io.Writer, files []File, mode string) error {
+	level := "warning"
+	if mode == ModeCheck {
+		level = "error"
+	}
+
+	for _, file := range files {
+		path := filepath.ToSlash(file.Path)
+
+		for _, s := range file.Result.Structures {
+			var err error
+			switch {
+			case s.Options.HasError:
+				err = writeAnnotation(w, "error", path, s.Comment, "Invalid magic comment", s.Error)
+			case !s.Sorted && s.Error == "" && mode != ModeWrite:
+				err = writeAnnotation(w, level, path, s.Comment, "Not sorted", UnsortedMessage(s.Kind)+". Run tree-sorter-ts --write to fix.")
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		if file.Err != nil && !hasInvalidMagicComment(file.Result.Structures) {
+			var pos processor.Position
+			var parseErr *processor.ParseError
+			if errors.As(file.Err, &parseErr) {
+				pos = processor.Position{Line: parseErr.Line, Column: parseErr.Column}
+			}
+			if err := writeAnnotation(w, "error", path, pos, "tree-sorter-ts", file.Err.Error()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// hasInvalidMagicComment reports whether the file error is already annotated on a structure
+func hasInvalidMagicComment(structures []processor.StructureResult) bool {
+	for _, s := range structures {
+		if s.Options.HasError {
+			return true
+		}
+	}
+	return false
+}
+
+// writeAnnotation writes a single workflow command; a zero position annotates the whole file
+func writeAnnotation(w io.Writer, level, file string, pos processor.Position, title, message string) error {
+	properties := []string{"file=" + escapeProperty(file)}
+	if pos.Line > 0 {
+		properties = append(properties, fmt.Sprintf("line=%d", pos.Line))
+	}
+	if pos.Column > 0 {
+		properties = append(properties, fmt.Sprintf("col=%d", pos.Column))
+	}
+	properties = append(properties, "title="+escapeProperty(title))
+
+	_, err := fmt.Fprintf(w, "::%s %s::%s\n", level, strings.Join(properties, ","), escapeData(message))
+	return err
+}
+
+// escapeData escapes a workflow command message
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeProperty escapes a workflow command property value
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
@@ -0,0 +1,94 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/processor"
+)
+
+func TestWriteGitHubAnnotations(t *testing.T) {
+	files := []File{
+		{
+			Path: "src/config.ts",
+			Result: processor.ProcessResult{
+				Changed: true,
+				Structures: []processor.StructureResult{
+					{Kind: processor.KindObject, Comment: processor.Position{Line: 2, Column: 3}},
+					{Kind: processor.KindArray, Comment: processor.Position{Line: 8, Column: 3}, Sorted: true},
+					{Kind: processor.KindParameters, Comment: processor.Position{Line: 12, Column: 5}},
+				},
+			},
+		},
+		{
+			Path: "src/invalid.ts",
+			Err:  errors.New("invalid configuration"),
+			Result: processor.ProcessResult{
+				Structures: []processor.StructureResult{
+					{
+						Kind:    processor.KindArray,
+						Comment: processor.Position{Line: 2, Column: 3},
+						Options: processor.SortConfig{HasError: true},
+						Error:   "invalid configuration",
+					},
+				},
+			},
+		},
+		{
+			Path: "src/broken,file.ts",
+			Err:  &processor.ParseError{Line: 4, Column: 7, Snippet: "a: 2"},
+		},
+		{
+			Path: "src/unreadable.ts",
+			Err:  errors.New("reading file: permission denied\nsecond line"),
+		},
+	}
+
+	tests := []struct {
+		name string
+		mode string
+		want string
+	}{
+		{
+			name: "check",
+			mode: ModeCheck,
+			want: `::error file=src/config.ts,line=2,col=3,title=Not sorted::Object properties are not sorted. Run tree-sorter-ts --write to fix.
+::error file=src/config.ts,line=12,col=5,title=Not sorted::Constructor parameters are not sorted. Run tree-sorter-ts --write to fix.
+::error file=src/invalid.ts,line=2,col=3,title=Invalid magic comment::invalid configuration
+::error file=src/broken%2Cfile.ts,line=4,col=7,title=tree-sorter-ts::syntax error at line 4, column 7 (unexpected "a: 2"); file left unchanged
+::error file=src/unreadable.ts,title=tree-sorter-ts::reading file: permission denied%0Asecond line
+`,
+		},
+		{
+			name: "dry_run",
+			mode: ModeDryRun,
+			want: `::warning file=src/config.ts,line=2,col=3,title=Not sorted::Object properties are not sorted. Run tree-sorter-ts --write to fix.
+::warning file=src/config.ts,line=12,col=5,title=Not sorted::Constructor parameters are not sorted. Run tree-sorter-ts --write to fix.
+::error file=src/invalid.ts,line=2,col=3,title=Invalid magic comment::invalid configuration
+::error file=src/broken%2Cfile.ts,line=4,col=7,title=tree-sorter-ts::syntax error at line 4, column 7 (unexpected "a: 2"); file left unchanged
+::error file=src/unreadable.ts,title=tree-sorter-ts::reading file: permission denied%0Asecond line
+`,
+		},
+		{
+			name: "write",
+			mode: ModeWrite,
+			want: `::error file=src/invalid.ts,line=2,col=3,title=Invalid magic comment::invalid configuration
+::error file=src/broken%2Cfile.ts,line=4,col=7,title=tree-sorter-ts::syntax error at line 4, column 7 (unexpected "a: 2"); file left unchanged
+::error file=src/unreadable.ts,title=tree-sorter-ts::reading file: permission denied%0Asecond line
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteGitHubAnnotations(&buf, files, tt.mode); err != nil {
+				t.Fatalf("WriteGitHubAnnotations failed: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("annotations mismatch\nGot:\n%s\nWant:\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+}
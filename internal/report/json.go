@@ -0,0 +1,146 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/processor"
+)
+
+type jsonReport struct {
+	Files   []jsonFile  `json:"files"`
+	Summary jsonSummary `json:"summary"`
+}
+
+type jsonFile struct {
+	Path       string      `json:"path"`
+	Changed    bool        `json:"changed"`
+	Error      string      `json:"error,omitempty"`
+	Structures []Structure `json:"structures"`
+}
+
+// Structure is the JSON form of a keep-sorted structure, shared by the JSON
+// report and the serve protocol
+type Structure struct {
+	Kind     string   `json:"kind"`
+	Start    Position `json:"start"`
+	End      Position `json:"end"`
+	Options  Options  `json:"options"`
+	Strategy string   `json:"strategy"`
+	Sorted   bool     `json:"sorted"`
+	Error    string   `json:"error,omitempty"`
+	Edits    []Edit   `json:"edits,omitempty"` // Edits sorting the structure, when it is not sorted
+}
+
+// Edit replaces the bytes from StartByte to EndByte, between Start and End,
+// with NewText
+type Edit struct {
+	StartByte int      `json:"startByte"`
+	EndByte   int      `json:"endByte"`
+	Start     Position `json:"start"`
+	End       Position `json:"end"`
+	NewText   string   `json:"newText"`
+}
+
+// Position is a 1-based line and column
+type Position struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Options are the magic comment options of a Structure
+type Options struct {
+	WithNewLine     bool   `json:"withNewLine"`
+	DeprecatedAtEnd bool   `json:"deprecatedAtEnd"`
+	Key             string `json:"key,omitempty"`
+	SortByComment   bool   `json:"sortByComment"`
+	GroupByKind     bool   `json:"groupByKind,omitempty"`
+	SortByValue     bool   `json:"sortByValue,omitempty"`
+	// MaterializeValues is set when enum member values are written out
+	MaterializeValues bool `json:"materializeValues,omitempty"`
+}
+
+type jsonSummary struct {
+	Mode               string `json:"mode"`
+	TotalFiles         int    `json:"totalFiles"`
+	FilesNeedSort      int    `json:"filesNeedSort"`
+	FilesNoChanges     int    `json:"filesNoChanges"`
+	ErrorFiles         int    `json:"errorFiles"`
+	TotalStructures    int    `json:"totalStructures"`
+	StructuresNeedSort int    `json:"structuresNeedSort"`
+}
+
+// WriteJSON writes every file with its keep-sorted structures and the run summary as JSON
+func WriteJSON(w io.Writer, files []File, summary Summary) error {
+	report := jsonReport{
+		Files: make([]jsonFile, 0, len(files)),
+		Summary: jsonSummary{
+			Mode:               summary.Mode,
+			TotalFiles:         summary.TotalFiles,
+			FilesNeedSort:      summary.FilesNeedSort,
+			FilesNoChanges:     summary.FilesNoChanges,
+			ErrorFiles:         summary.ErrorFiles,
+			TotalStructures:    summary.TotalStructures,
+			StructuresNeedSort: summary.StructuresNeedSort,
+		},
+	}
+
+	for _, file := range files {
+		jf := jsonFile{
+			Path:       file.Path,
+			Changed:    file.Result.Changed,
+			Structures: make([]Structure, 0, len(file.Result.Structures)),
+		}
+		if file.Err != nil {
+			jf.Error = file.Err.Error()
+		}
+		for _, s := range file.Result.Structures {
+			jf.Structures = append(jf.Structures, NewStructure(s))
+		}
+		report.Files = append(report.Files, jf)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// NewStructure converts a structure result into its JSON form
+func NewStructure(s processor.StructureResult) Structure {
+	return Structure{
+		Kind:  s.Kind,
+		Start: Position{Line: s.Start.Line, Column: s.Start.Column},
+		End:   Position{Line: s.End.Line, Column: s.End.Column},
+		Options: Options{
+			WithNewLine:       s.Options.WithNewLine,
+			DeprecatedAtEnd:   s.Options.DeprecatedAtEnd,
+			Key:               s.Options.Key,
+			SortByComment:     s.Options.SortByComment,
+			GroupByKind:       s.Options.GroupByKind,
+			SortByValue:       s.Options.SortByValue,
+			MaterializeValues: s.Options.MaterializeValues,
+		},
+		Strategy: s.Strategy,
+		Sorted:   s.Sorted,
+		Error:    s.Error,
+		Edits:    NewEdits(s.Edits),
+	}
+}
+
+// NewEdits converts edits into their JSON form; nil stays nil
+func NewEdits(edits []processor.TextEdit) []Edit {
+	if len(edits) == 0 {
+		return nil
+	}
+	converted := make([]Edit, 0, len(edits))
+	for _, edit := range edits {
+		converted = append(converted, Edit{
+			StartByte: edit.StartByte,
+			EndByte:   edit.EndByte,
+			Start:     Position{Line: edit.StartPoint.Line, Column: edit.StartPoint.Column},
+			End:       Position{Line: edit.EndPoint.Line, Column: edit.EndPoint.Column},
+			NewText:   edit.NewText,
+		})
+	}
+	return converted
+}
@@ -0,0 +1,131 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/processor"
+)
+
+func TestWriteJSON(t *testing.T) {
+	files := []File{
+		{
+			Path: "src/config.ts",
+			Result: processor.ProcessResult{
+				Changed:         true,
+				ObjectsFound:    2,
+				ObjectsNeedSort: 1,
+				Structures: []processor.StructureResult{
+					{
+						Kind:     processor.KindObject,
+						Start:    processor.Position{Line: 1, Column: 16},
+						End:      processor.Position{Line: 5, Column: 2},
+						Options:  processor.SortConfig{WithNewLine: true},
+						Strategy: "property-name",
+						Sorted:   false,
+						Edits: []processor.TextEdit{{
+							StartByte:  20,
+							EndByte:    26,
+							StartPoint: processor.Position{Line: 2, Column: 3},
+							EndPoint:   processor.Position{Line: 2, Column: 9},
+							NewText:    "a: 1,\n\n  b: 2",
+						}},
+					},
+					{
+						Kind:     processor.KindArray,
+						Start:    processor.Position{Line: 7, Column: 15},
+						End:      processor.Position{Line: 10, Column: 2},
+						Options:  processor.SortConfig{Key: "id"},
+						Strategy: "array-key[id]",
+						Sorted:   true,
+					},
+				},
+			},
+		},
+		{
+			Path: "src/broken.ts",
+			Err:  errors.New("syntax error at line 3, column 1"),
+		},
+		{
+			Path: "src/empty.ts",
+		},
+	}
+	summary := Summarize(ModeCheck, files)
+
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, files, summary); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+
+	var got struct {
+		Files []struct {
+			Path       string `json:"path"`
+			Changed    bool   `json:"changed"`
+			Error      string `json:"error"`
+			Structures []struct {
+				Kind  string `json:"kind"`
+				Start struct {
+					Line   int `json:"line"`
+					Column int `json:"column"`
+				} `json:"start"`
+				Options struct {
+					WithNewLine bool   `json:"withNewLine"`
+					Key         string `json:"key"`
+				} `json:"options"`
+				Strategy string `json:"strategy"`
+				Sorted   bool   `json:"sorted"`
+				Edits    []Edit `json:"edits"`
+			} `json:"structures"`
+		} `json:"files"`
+		Summary map[string]interface{} `json:"summary"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+	}
+
+	if len(got.Files) != 3 {
+		t.Fatalf("got %d files, want 3", len(got.Files))
+	}
+
+	config := got.Files[0]
+	if config.Path != "src/config.ts" || !config.Changed || len(config.Structures) != 2 {
+		t.Errorf("unexpected first file: %+v", config)
+	} else {
+		obj := config.Structures[0]
+		if obj.Kind != "object" || obj.Start.Line != 1 || obj.Start.Column != 16 || !obj.Options.WithNewLine || obj.Sorted {
+			t.Errorf("unexpected object structure: %+v", obj)
+		}
+		wantEdit := Edit{StartByte: 20, EndByte: 26, Start: Position{Line: 2, Column: 3}, End: Position{Line: 2, Column: 9}, NewText: "a: 1,\n\n  b: 2"}
+		if len(obj.Edits) != 1 || obj.Edits[0] != wantEdit {
+			t.Errorf("unexpected object edits: %+v", obj.Edits)
+		}
+		arr := config.Structures[1]
+		if arr.Kind != "array" || arr.Options.Key != "id" || arr.Strategy != "array-key[id]" || !arr.Sorted || arr.Edits != nil {
+			t.Errorf("unexpected array structure: %+v", arr)
+		}
+	}
+
+	if got.Files[1].Error == "" {
+		t.Errorf("expected error for %s", got.Files[1].Path)
+	}
+	if got.Files[2].Structures == nil {
+		t.Errorf("files without structures should report an empty list, not null")
+	}
+
+	wantSummary := map[string]interface{}{
+		"mode":               "check",
+		"totalFiles":         float64(3),
+		"filesNeedSort":      float64(1),
+		"filesNoChanges":     float64(1),
+		"errorFiles":         float64(1),
+		"totalStructures":    float64(2),
+		"structuresNeedSort": float64(1),
+	}
+	for key, want := range wantSummary {
+		if got.Summary[key] != want {
+			t.Errorf("summary[%q] = %v, want %v", key, got.Summary[key], want)
+		}
+	}
+}
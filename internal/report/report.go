@@ -0,0 +1,92 @@
+package report
+
+import (
+	"github.com/evanrichards/tree-sorter-ts/internal/processor"
+)
+
+// Output formats selected with --format
+const (
+	FormatText   = "text"
+	FormatJSON   = "json"
+	FormatSARIF  = "sarif"
+	FormatGitHub = "github"
+)
+
+// Run modes reported in Summary.Mode
+const (
+	ModeCheck  = "check"
+	ModeWrite  = "write"
+	ModeDryRun = "dry-run"
+)
+
+// File is the outcome of processing a single file
+type File struct {
+	Path   string
+	Result processor.ProcessResult
+	Err    error
+}
+
+// Summary totals the outcome of a run
+type Summary struct {
+	Mode               string
+	TotalFiles         int
+	FilesNeedSort      int
+	FilesNoChanges     int
+	ErrorFiles         int
+	TotalStructures    int
+	StructuresNeedSort int
+}
+
+// ModeFor returns the run mode selected by the check and write flags
+func ModeFor(config processor.Config) string {
+	switch {
+	case config.Check:
+		return ModeCheck
+	case config.Write:
+		return ModeWrite
+	default:
+		return ModeDryRun
+	}
+}
+
+// UnsortedMessage describes an unsorted structure of kind for humans
+func UnsortedMessage(kind string) string {
+	switch kind {
+	case processor.KindArray:
+		return "Array elements are not sorted"
+	case processor.KindParameters:
+		return "Constructor parameters are not sorted"
+	case processor.KindStatements:
+		return "Statements are not sorted"
+	case processor.KindMembers:
+		return "Class members are not sorted"
+	case processor.KindTypeMembers:
+		return "Type members are not sorted"
+	case processor.KindEnumMembers:
+		return "Enum members are not sorted"
+	default:
+		return "Object properties are not sorted"
+	}
+}
+
+// Summarize totals the results of a run
+func Summarize(mode string, files []File) Summary {
+	summary := Summary{
+		Mode:       mode,
+		TotalFiles: len(files),
+	}
+	for _, file := range files {
+		if file.Err != nil {
+			summary.ErrorFiles++
+			continue
+		}
+		summary.TotalStructures += file.Result.ObjectsFound
+		summary.StructuresNeedSort += file.Result.ObjectsNeedSort
+		if file.Result.Changed {
+			summary.FilesNeedSort++
+		} else {
+			summary.FilesNoChanges++
+		}
+	}
+	return summary
+}
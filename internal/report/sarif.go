@@ -0,0 +1,347 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/processor"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	toolName     = "tree-sorter-ts"
+	toolURI      = "https://github.com/evanrichards/tree-sorter-ts"
+)
+
+// SARIF rule IDs
+const (
+	RuleUnsortedObject      = "unsorted-object"
+	RuleUnsortedArray       = "unsorted-array"
+	RuleUnsortedParameters  = "unsorted-parameters"
+	RuleUnsortedStatements  = "unsorted-statements"
+	RuleUnsortedMembers     = "unsorted-members"
+	RuleUnsortedTypeMembers = "unsorted-type-members"
+	RuleUnsortedEnumMembers = "unsorted-enum-members"
+	RuleInvalidMagicComment = "invalid-magic-comment"
+)
+
+// RuleForKind returns the rule ID reported for an unsorted structure of kind
+func RuleForKind(kind string) string {
+	switch kind {
+	case processor.KindArray:
+		return RuleUnsortedArray
+	case processor.KindParameters:
+		return RuleUnsortedParameters
+	case processor.KindStatements:
+		return RuleUnsortedStatements
+	case processor.KindMembers:
+		return RuleUnsortedMembers
+	case processor.KindTypeMembers:
+		return RuleUnsortedTypeMembers
+	case processor.KindEnumMembers:
+		return RuleUnsortedEnumMembers
+	default:
+		return RuleUnsortedObject
+	}
+}
+
+type sarifRuleInfo struct {
+	id          string
+	name        string
+	description string
+	level       string
+}
+
+// sarifRules lists every rule in the order of the driver's rules array
+var sarifRules = []sarifRuleInfo{
+	{
+		id:          RuleUnsortedObject,
+		name:        "UnsortedObject",
+		description: "Object marked with a keep-sorted magic comment is not sorted",
+		level:       "warning",
+	},
+	{
+		id:          RuleUnsortedArray,
+		name:        "UnsortedArray",
+		description: "Array marked with a keep-sorted magic comment is not sorted",
+		level:       "warning",
+	},
+	{
+		id:          RuleUnsortedParameters,
+		name:        "UnsortedParameters",
+		description: "Constructor parameters marked with a keep-sorted magic comment are not sorted",
+		level:       "warning",
+	},
+	{
+		id:          RuleUnsortedStatements,
+		name:        "UnsortedStatements",
+		description: "Statements marked with a keep-sorted magic comment are not sorted",
+		level:       "warning",
+	},
+	{
+		id:          RuleUnsortedMembers,
+		name:        "UnsortedMembers",
+		description: "Class members marked with a keep-sorted magic comment are not sorted",
+		level:       "warning",
+	},
+	{
+		id:          RuleUnsortedTypeMembers,
+		name:        "UnsortedTypeMembers",
+		description: "Interface or type literal members marked with a keep-sorted magic comment are not sorted",
+		level:       "warning",
+	},
+	{
+		id:          RuleUnsortedEnumMembers,
+		name:        "UnsortedEnumMembers",
+		description: "Enum members marked with a keep-sorted magic comment are not sorted",
+		level:       "warning",
+	},
+	{
+		id:          RuleInvalidMagicComment,
+		name:        "InvalidMagicComment",
+		description: "Keep-sorted magic comment combines options that cannot be used together",
+		level:       "error",
+	},
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool         `json:"tool"`
+	Invocations []sarifInvocation `json:"invocations"`
+	ColumnKind  string            `json:"columnKind"`
+	Results     []sarifResult     `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	HelpURI              string             `json:"helpUri"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifNotification struct {
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+	Fixes     []sarifFix      `json:"fixes,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndLine     int `json:"endLine"`
+	EndColumn   int `json:"endColumn"`
+}
+
+type sarifFix struct {
+	Description     sarifMessage          `json:"description"`
+	ArtifactChanges []sarifArtifactChange `json:"artifactChanges"`
+}
+
+type sarifArtifactChange struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Replacements     []sarifReplacement    `json:"replacements"`
+}
+
+type sarifReplacement struct {
+	DeletedRegion   sarifRegion  `json:"deletedRegion"`
+	InsertedContent sarifMessage `json:"insertedContent"`
+}
+
+// WriteSARIF writes one SARIF result per unsorted structure or invalid magic
+// comment. Unsorted structures carry a fix with the edits sorting them;
+// files that could not be processed are reported as tool execution
+// notifications.
+func WriteSARIF(w io.Writer, files []File, version string) error {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           toolName,
+				Version:        version,
+				InformationURI: toolURI,
+				Rules:          make([]sarifRule, 0, len(sarifRules)),
+			},
+		},
+		ColumnKind: "unicodeCodePoints",
+		Results:    []sarifResult{},
+	}
+	for _, rule := range sarifRules {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+			ID:                   rule.id,
+			Name:                 rule.name,
+			ShortDescription:     sarifMessage{Text: rule.description},
+			HelpURI:              toolURI + "#readme",
+			DefaultConfiguration: sarifConfiguration{Level: rule.level},
+		})
+	}
+
+	invocation := sarifInvocation{ExecutionSuccessful: true}
+
+	for _, file := range files {
+		artifact := sarifArtifactLocation{URI: artifactURI(file.Path)}
+
+		if file.Err != nil {
+			invocation.ExecutionSuccessful = false
+			invocation.ToolExecutionNotifications = append(invocation.ToolExecutionNotifications, sarifNotification{
+				Level:     "error",
+				Message:   sarifMessage{Text: file.Err.Error()},
+				Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: artifact}}},
+			})
+		}
+
+		for _, s := range file.Result.Structures {
+			if result, ok := newSARIFResult(s, artifact); ok {
+				run.Results = append(run.Results, result)
+			}
+		}
+	}
+
+	run.Invocations = []sarifInvocation{invocation}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	})
+}
+
+// newSARIFResult converts a structure into a result, reporting false for
+// structures that are sorted or were skipped for syntax errors
+func newSARIFResult(s processor.StructureResult, artifact sarifArtifactLocation) (sarifResult, bool) {
+	region := sarifRegion{
+		StartLine:   s.Start.Line,
+		StartColumn: s.Start.Column,
+		EndLine:     s.End.Line,
+		EndColumn:   s.End.Column,
+	}
+	location := []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: artifact, Region: &region}}}
+
+	if s.Options.HasError {
+		ruleIndex := sarifRuleIndex(RuleInvalidMagicComment)
+		return sarifResult{
+			RuleID:    RuleInvalidMagicComment,
+			RuleIndex: ruleIndex,
+			Level:     sarifRules[ruleIndex].level,
+			Message:   sarifMessage{Text: s.Error},
+			Locations: location,
+		}, true
+	}
+
+	if s.Sorted || s.Error != "" {
+		return sarifResult{}, false
+	}
+
+	ruleID := RuleForKind(s.Kind)
+	ruleIndex := sarifRuleIndex(ruleID)
+
+	return sarifResult{
+		RuleID:    ruleID,
+		RuleIndex: ruleIndex,
+		Level:     sarifRules[ruleIndex].level,
+		Message:   sarifMessage{Text: fmt.Sprintf("%s (%s)", UnsortedMessage(s.Kind), s.Strategy)},
+		Locations: location,
+		Fixes: []sarifFix{{
+			Description: sarifMessage{Text: "Sort with tree-sorter-ts"},
+			ArtifactChanges: []sarifArtifactChange{{
+				ArtifactLocation: artifact,
+				Replacements:     sarifReplacements(s.Edits),
+			}},
+		}},
+	}, true
+}
+
+// sarifReplacements converts a structure's edits into fix replacements
+func sarifReplacements(edits []processor.TextEdit) []sarifReplacement {
+	replacements := make([]sarifReplacement, 0, len(edits))
+	for _, edit := range edits {
+		replacements = append(replacements, sarifReplacement{
+			DeletedRegion: sarifRegion{
+				StartLine:   edit.StartPoint.Line,
+				StartColumn: edit.StartPoint.Column,
+				EndLine:     edit.EndPoint.Line,
+				EndColumn:   edit.EndPoint.Column,
+			},
+			InsertedContent: sarifMessage{Text: edit.NewText},
+		})
+	}
+	return replacements
+}
+
+func sarifRuleIndex(id string) int {
+	for i, rule := range sarifRules {
+		if rule.id == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// artifactURI returns a relative URI for relative paths, which code scanning
+// resolves against the repository root, and a file URI otherwise
+func artifactURI(path string) string {
+	slashed := filepath.ToSlash(filepath.Clean(path))
+	if filepath.IsAbs(path) {
+		if !strings.HasPrefix(slashed, "/") {
+			slashed = "/" + slashed
+		}
+		return "file://" + slashed
+	}
+	return slashed
+}
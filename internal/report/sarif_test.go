@@ -0,0 +1,135 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/processor"
+)
+
+func TestWriteSARIF(t *testing.T) {
+	files := []File{
+		{
+			Path: "src/config.ts",
+			Result: processor.ProcessResult{
+				Changed: true,
+				Structures: []processor.StructureResult{
+					{
+						Kind:     processor.KindObject,
+						Start:    processor.Position{Line: 1, Column: 16},
+						End:      processor.Position{Line: 4, Column: 2},
+						Strategy: "property-name",
+						Edits: []processor.TextEdit{{
+							StartPoint: processor.Position{Line: 2, Column: 3},
+							EndPoint:   processor.Position{Line: 3, Column: 7},
+							NewText:    "a: 1,\n  b: 2",
+						}},
+					},
+					{
+						Kind:     processor.KindArray,
+						Start:    processor.Position{Line: 6, Column: 15},
+						End:      processor.Position{Line: 9, Column: 2},
+						Strategy: "array-element-value",
+						Sorted:   true,
+					},
+					{
+						Kind:     processor.KindParameters,
+						Start:    processor.Position{Line: 12, Column: 14},
+						End:      processor.Position{Line: 15, Column: 4},
+						Strategy: "property-name",
+						Error:    "structure contains syntax errors; left unchanged",
+					},
+				},
+			},
+		},
+		{
+			Path: "src/invalid.ts",
+			Err:  errors.New("invalid configuration"),
+			Result: processor.ProcessResult{
+				Structures: []processor.StructureResult{
+					{
+						Kind:    processor.KindArray,
+						Start:   processor.Position{Line: 2, Column: 3},
+						End:     processor.Position{Line: 5, Column: 2},
+						Options: processor.SortConfig{Key: "id", SortByComment: true, HasError: true},
+						Error:   "invalid configuration",
+					},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSARIF(&buf, files, "1.2.3"); err != nil {
+		t.Fatalf("WriteSARIF failed: %v", err)
+	}
+
+	var got sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+	}
+
+	if got.Version != "2.1.0" || len(got.Runs) != 1 {
+		t.Fatalf("unexpected SARIF envelope: version %q with %d runs", got.Version, len(got.Runs))
+	}
+	run := got.Runs[0]
+	if run.Tool.Driver.Version != "1.2.3" || len(run.Tool.Driver.Rules) != len(sarifRules) {
+		t.Errorf("unexpected driver: %+v", run.Tool.Driver)
+	}
+
+	// The sorted array and the structure with syntax errors produce no results
+	if len(run.Results) != 2 {
+		t.Fatalf("got %d results, want 2: %+v", len(run.Results), run.Results)
+	}
+
+	unsorted := run.Results[0]
+	if unsorted.RuleID != RuleUnsortedObject || run.Tool.Driver.Rules[unsorted.RuleIndex].ID != RuleUnsortedObject {
+		t.Errorf("unexpected rule for unsorted object: %s (index %d)", unsorted.RuleID, unsorted.RuleIndex)
+	}
+	wantRegion := sarifRegion{StartLine: 1, StartColumn: 16, EndLine: 4, EndColumn: 2}
+	if region := unsorted.Locations[0].PhysicalLocation.Region; region == nil || *region != wantRegion {
+		t.Errorf("region = %+v, want %+v", region, wantRegion)
+	}
+	if uri := unsorted.Locations[0].PhysicalLocation.ArtifactLocation.URI; uri != "src/config.ts" {
+		t.Errorf("uri = %q, want src/config.ts", uri)
+	}
+	if len(unsorted.Fixes) != 1 {
+		t.Fatalf("expected one fix, got %d", len(unsorted.Fixes))
+	}
+	replacement := unsorted.Fixes[0].ArtifactChanges[0].Replacements[0]
+	wantDeleted := sarifRegion{StartLine: 2, StartColumn: 3, EndLine: 3, EndColumn: 7}
+	if replacement.DeletedRegion != wantDeleted || replacement.InsertedContent.Text != "a: 1,\n  b: 2" {
+		t.Errorf("unexpected fix replacement: %+v", replacement)
+	}
+
+	invalid := run.Results[1]
+	if invalid.RuleID != RuleInvalidMagicComment || invalid.Level != "error" || len(invalid.Fixes) != 0 {
+		t.Errorf("unexpected invalid magic comment result: %+v", invalid)
+	}
+
+	if len(run.Invocations) != 1 || run.Invocations[0].ExecutionSuccessful {
+		t.Fatalf("expected one unsuccessful invocation, got %+v", run.Invocations)
+	}
+	if notes := run.Invocations[0].ToolExecutionNotifications; len(notes) != 1 || notes[0].Message.Text != "invalid configuration" {
+		t.Errorf("unexpected notifications: %+v", notes)
+	}
+}
+
+func TestArtifactURI(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "src/config.ts", want: "src/config.ts"},
+		{path: "./src/../lib/config.ts", want: "lib/config.ts"},
+		{path: "/repo/src/config.ts", want: "file:///repo/src/config.ts"},
+	}
+
+	for _, tt := range tests {
+		if got := artifactURI(tt.path); got != tt.want {
+			t.Errorf("artifactURI(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
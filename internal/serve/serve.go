@@ -0,0 +1,300 @@
+// Package serve implements the protocol of `tree-sorter-ts serve`: one JSON
+// request per line in, one JSON response per line out, so that editors and
+// the npm package can sort many buffers with a single long-running process.
+package serve
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/config"
+	"github.com/evanrichards/tree-sorter-ts/internal/processor"
+	"github.com/evanrichards/tree-sorter-ts/internal/report"
+	"github.com/evanrichards/tree-sorter-ts/internal/textutil"
+)
+
+// Error codes, following JSON-RPC 2.0
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeSortFailed     = 1 // The content could not be sorted, e.g. it does not parse
+)
+
+type request struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+type response struct {
+	ID     json.RawMessage `json:"id"`
+	Result any             `json:"result,omitempty"`
+	Error  *responseError  `json:"error,omitempty"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// ContentParams are the params of every method: a buffer and the path it
+// belongs to, which selects the grammar and the project configuration. The
+// file need not exist.
+type ContentParams struct {
+	FilePath         string  `json:"filePath"`
+	Content          *string `json:"content"`
+	AllowParseErrors bool    `json:"allowParseErrors"`
+}
+
+// SortResult is the result of sortContent
+type SortResult struct {
+	Changed bool   `json:"changed"`
+	Ignored bool   `json:"ignored"` // The project configuration ignores the file
+	Content string `json:"content"`
+	Edits   []Edit `json:"edits"`
+}
+
+// Edit replaces a range of the request content. Start and End count UTF-16
+// code units, so they index JavaScript strings directly. The edits of a
+// result do not overlap and are ordered by position.
+type Edit struct {
+	Start     int    `json:"start"`
+	End       int    `json:"end"`
+	StartByte int    `json:"startByte"`
+	EndByte   int    `json:"endByte"`
+	NewText   string `json:"newText"`
+}
+
+// CheckResult is the result of checkContent
+type CheckResult struct {
+	Sorted             bool               `json:"sorted"`
+	Ignored            bool               `json:"ignored"`
+	StructuresNeedSort int                `json:"structuresNeedSort"`
+	Structures         []report.Structure `json:"structures"`
+	Error              string             `json:"error,omitempty"`
+}
+
+// ListResult is the result of listStructures
+type ListResult struct {
+	Ignored    bool               `json:"ignored"`
+	Structures []report.Structure `json:"structures"`
+	Error      string             `json:"error,omitempty"`
+}
+
+// Options configures a Server
+type Options struct {
+	ConfigPath string // Project configuration file for every request; empty looks up the nearest one
+	Workers    int    // Requests handled at once (0 = number of CPUs)
+}
+
+// Server answers requests read from one stream on another. Requests are
+// handled concurrently, so responses may arrive out of order and must be
+// matched to requests by id.
+type Server struct {
+	in       *bufio.Reader
+	out      *json.Encoder
+	outMu    sync.Mutex
+	resolver *config.Resolver
+	workers  int
+}
+
+// NewServer creates a server reading requests from r and writing responses to w
+func NewServer(r io.Reader, w io.Writer, opts Options) (*Server, error) {
+	resolver, err := config.NewResolver(opts.ConfigPath)
+	if err != nil {
+		return nil, err
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	out := json.NewEncoder(w)
+	out.SetEscapeHTML(false)
+
+	return &Server{
+		in:       bufio.NewReader(r),
+		out:      out,
+		resolver: resolver,
+		workers:  workers,
+	}, nil
+}
+
+// Run serves requests until the input is closed, then waits for the requests
+// in flight. Only read and write failures are returned.
+func (s *Server) Run() error {
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, s.workers)
+	var writeErr error
+	var writeErrOnce sync.Once
+
+	for {
+		line, err := s.in.ReadBytes('\n')
+		if len(line) > 0 && !isBlank(line) {
+			slots <- struct{}{}
+			wg.Add(1)
+			go func(line []byte) {
+				defer func() {
+					<-slots
+					wg.Done()
+				}()
+				if err := s.write(s.handle(line)); err != nil {
+					writeErrOnce.Do(func() { writeErr = err })
+				}
+			}(line)
+		}
+
+		if err != nil {
+			wg.Wait()
+			if errors.Is(err, io.EOF) {
+				return writeErr
+			}
+			return err
+		}
+	}
+}
+
+func (s *Server) write(resp response) error {
+	s.outMu.Lock()
+	defer s.outMu.Unlock()
+	return s.out.Encode(resp)
+}
+
+// handle answers a single request line
+func (s *Server) handle(line []byte) response {
+	var req request
+	if err := json.Unmarshal(line, &req); err != nil {
+		return response{ID: json.RawMessage("null"), Error: &responseError{Code: codeParseError, Message: err.Error()}}
+	}
+	if len(req.ID) == 0 {
+		req.ID = json.RawMessage("null")
+	}
+	if req.Method == "" {
+		return response{ID: req.ID, Error: &responseError{Code: codeInvalidRequest, Message: "missing method"}}
+	}
+
+	var handler func(ContentParams) (any, *responseError)
+	switch req.Method {
+	case "sortContent":
+		handler = s.sortContent
+	case "checkContent":
+		handler = s.checkContent
+	case "listStructures":
+		handler = s.listStructures
+	default:
+		return response{ID: req.ID, Error: &responseError{Code: codeMethodNotFound, Message: "unknown method: " + req.Method}}
+	}
+
+	var params ContentParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return response{ID: req.ID, Error: &responseError{Code: codeInvalidParams, Message: err.Error()}}
+	}
+	if params.Content == nil {
+		return response{ID: req.ID, Error: &responseError{Code: codeInvalidParams, Message: "missing content"}}
+	}
+
+	result, rpcErr := handler(params)
+	return response{ID: req.ID, Result: result, Error: rpcErr}
+}
+
+// process sorts the params' content under the project configuration for its
+// path; ignored is true when the configuration ignores the file
+func (s *Server) process(params ContentParams) (result processor.ProcessResult, ignored bool, err error) {
+	settings, err := s.resolver.SettingsForContent(params.FilePath)
+	if err != nil {
+		return result, false, err
+	}
+	if settings.Ignored {
+		return result, true, nil
+	}
+
+	result, err = processor.ProcessContentAST(params.FilePath, []byte(*params.Content), processor.Config{
+		AllowParseErrors: params.AllowParseErrors,
+		SortDefaults:     settings.Defaults,
+	})
+	return result, false, err
+}
+
+func (s *Server) sortContent(params ContentParams) (any, *responseError) {
+	result, ignored, err := s.process(params)
+	if err != nil {
+		return nil, &responseError{Code: codeSortFailed, Message: errorMessage(params, err)}
+	}
+
+	sorted := SortResult{Ignored: ignored, Content: *params.Content, Edits: []Edit{}}
+	if result.Changed {
+		sorted.Changed = true
+		sorted.Content = string(result.Sorted)
+		for _, edit := range result.Edits {
+			sorted.Edits = append(sorted.Edits, Edit{
+				Start:     textutil.UTF16Len(result.Original[:edit.StartByte]),
+				End:       textutil.UTF16Len(result.Original[:edit.EndByte]),
+				StartByte: edit.StartByte,
+				EndByte:   edit.EndByte,
+				NewText:   edit.NewText,
+			})
+		}
+	}
+	return sorted, nil
+}
+
+func (s *Server) checkContent(params ContentParams) (any, *responseError) {
+	result, ignored, err := s.process(params)
+
+	check := CheckResult{
+		Sorted:     err == nil && !result.Changed,
+		Ignored:    ignored,
+		Structures: structures(result),
+	}
+	for _, structure := range result.Structures {
+		if !structure.Sorted {
+			check.StructuresNeedSort++
+		}
+	}
+	if err != nil {
+		check.Error = errorMessage(params, err)
+	}
+	return check, nil
+}
+
+func (s *Server) listStructures(params ContentParams) (any, *responseError) {
+	result, ignored, err := s.process(params)
+
+	list := ListResult{Ignored: ignored, Structures: structures(result)}
+	if err != nil {
+		list.Error = errorMessage(params, err)
+	}
+	return list, nil
+}
+
+func structures(result processor.ProcessResult) []report.Structure {
+	converted := make([]report.Structure, 0, len(result.Structures))
+	for _, structure := range result.Structures {
+		converted = append(converted, report.NewStructure(structure))
+	}
+	return converted
+}
+
+func errorMessage(params ContentParams, err error) string {
+	if params.FilePath == "" {
+		return err.Error()
+	}
+	return fmt.Sprintf("%s: %v", params.FilePath, err)
+}
+
+func isBlank(line []byte) bool {
+	for _, b := range line {
+		if b != ' ' && b != '\t' && b != '\r' && b != '\n' {
+			return false
+		}
+	}
+	return true
+}
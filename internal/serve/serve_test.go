@@ -0,0 +1,196 @@
+package serve
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+// serve runs a server over the given request lines and returns its
+// responses keyed by id
+func serve(t *testing.T, opts Options, lines ...string) map[string]response {
+	t.Helper()
+
+	var out bytes.Buffer
+	server, err := NewServer(strings.NewReader(strings.Join(lines, "\n")), &out, opts)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	if err := server.Run(); err != nil {
+		t.Fatalf("Failed to serve: %v", err)
+	}
+
+	responses := make(map[string]response)
+	decoder := json.NewDecoder(&out)
+	for decoder.More() {
+		var resp struct {
+			ID     json.RawMessage `json:"id"`
+			Result json.RawMessage `json:"result"`
+			Error  *responseError  `json:"error"`
+		}
+		if err := decoder.Decode(&resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		responses[string(resp.ID)] = response{ID: resp.ID, Result: resp.Result, Error: resp.Error}
+	}
+	if len(responses) != len(lines) {
+		t.Fatalf("got %d responses, want %d: %v", len(responses), len(lines), responses)
+	}
+	return responses
+}
+
+func requestLine(t *testing.T, id int, method string, params any) string {
+	t.Helper()
+	data, err := json.Marshal(map[string]any{"id": id, "method": method, "params": params})
+	if err != nil {
+		t.Fatalf("Failed to encode request: %v", err)
+	}
+	return string(data)
+}
+
+func decodeResult(t *testing.T, resp response, v any) {
+	t.Helper()
+	if resp.Error != nil {
+		t.Fatalf("unexpected error response: %+v", resp.Error)
+	}
+	if err := json.Unmarshal(resp.Result.(json.RawMessage), v); err != nil {
+		t.Fatalf("Failed to decode result: %v", err)
+	}
+}
+
+const unsortedSource = `const label = "😀";
+
+const config = {
+  /** tree-sorter-ts: keep-sorted **/
+  zebra: "🦓",
+  apple: "🍎",
+};
+`
+
+func TestSortContent(t *testing.T) {
+	sortedSource := strings.Replace(unsortedSource, "  zebra: \"🦓\",\n  apple: \"🍎\",\n", "  apple: \"🍎\",\n  zebra: \"🦓\",\n", 1)
+
+	responses := serve(t, Options{},
+		requestLine(t, 1, "sortContent", map[string]any{"filePath": "src/config.ts", "content": unsortedSource}),
+		requestLine(t, 2, "sortContent", map[string]any{"filePath": "src/config.ts", "content": sortedSource}),
+		requestLine(t, 3, "sortContent", map[string]any{"filePath": "src/broken.ts", "content": strings.TrimSuffix(unsortedSource, "};\n")}),
+	)
+
+	var result SortResult
+	decodeResult(t, responses["1"], &result)
+	if !result.Changed || result.Content != sortedSource {
+		t.Errorf("unexpected sort result: %+v", result)
+	}
+	if len(result.Edits) != 1 {
+		t.Fatalf("got %d edits, want 1", len(result.Edits))
+	}
+
+	// Applying the edit with UTF-16 indexes, as JavaScript does, gives the
+	// sorted content
+	edit := result.Edits[0]
+	units := utf16.Encode([]rune(unsortedSource))
+	applied := string(utf16.Decode(units[:edit.Start])) + edit.NewText + string(utf16.Decode(units[edit.End:]))
+	if applied != sortedSource {
+		t.Errorf("applying %+v by UTF-16 index gave:\n%s", edit, applied)
+	}
+	applied = unsortedSource[:edit.StartByte] + edit.NewText + unsortedSource[edit.EndByte:]
+	if applied != sortedSource {
+		t.Errorf("applying %+v by byte offset gave:\n%s", edit, applied)
+	}
+
+	decodeResult(t, responses["2"], &result)
+	if result.Changed || result.Content != sortedSource || len(result.Edits) != 0 {
+		t.Errorf("unexpected result for sorted content: %+v", result)
+	}
+
+	if resp := responses["3"]; resp.Error == nil || resp.Error.Code != codeSortFailed || !strings.Contains(resp.Error.Message, "src/broken.ts") {
+		t.Errorf("expected a sort failure for unparsable content, got %+v", resp)
+	}
+}
+
+func TestCheckContentAndListStructures(t *testing.T) {
+	invalid := `const ids = [
+  /** tree-sorter-ts: keep-sorted key="id" sort-by-comment **/
+  { id: 2 },
+  { id: 1 },
+];
+`
+	responses := serve(t, Options{},
+		requestLine(t, 1, "checkContent", map[string]any{"filePath": "config.ts", "content": unsortedSource}),
+		requestLine(t, 2, "listStructures", map[string]any{"filePath": "config.ts", "content": unsortedSource}),
+		requestLine(t, 3, "checkContent", map[string]any{"filePath": "ids.ts", "content": invalid}),
+	)
+
+	var check CheckResult
+	decodeResult(t, responses["1"], &check)
+	if check.Sorted || check.StructuresNeedSort != 1 || len(check.Structures) != 1 || check.Error != "" {
+		t.Errorf("unexpected check result: %+v", check)
+	}
+
+	var list ListResult
+	decodeResult(t, responses["2"], &list)
+	if len(list.Structures) != 1 {
+		t.Fatalf("got %d structures, want 1", len(list.Structures))
+	}
+	if s := list.Structures[0]; s.Kind != "object" || s.Sorted || s.Start.Line != 3 || s.End.Line != 7 {
+		t.Errorf("unexpected structure: %+v", s)
+	}
+
+	decodeResult(t, responses["3"], &check)
+	if check.Sorted || check.Error == "" || len(check.Structures) != 1 || check.Structures[0].Error == "" {
+		t.Errorf("expected the invalid magic comment to be reported, got %+v", check)
+	}
+}
+
+func TestServeProtocolErrors(t *testing.T) {
+	responses := serve(t, Options{},
+		`{"id": 1, "method": "formatEverything", "params": {"content": ""}}`,
+		`{"id": 2, "method": "sortContent", "params": {"filePath": "a.ts"}}`,
+		`{"id": 3, "method": "sortContent", "params": []}`,
+		`{"id": 4}`,
+		`not json`,
+	)
+
+	tests := []struct {
+		id   string
+		code int
+	}{
+		{id: "1", code: codeMethodNotFound},
+		{id: "2", code: codeInvalidParams},
+		{id: "3", code: codeInvalidParams},
+		{id: "4", code: codeInvalidRequest},
+		{id: "null", code: codeParseError},
+	}
+	for _, tt := range tests {
+		resp, ok := responses[tt.id]
+		if !ok {
+			t.Errorf("no response with id %s", tt.id)
+			continue
+		}
+		if resp.Error == nil || resp.Error.Code != tt.code {
+			t.Errorf("response %s: got %+v, want error code %d", tt.id, resp.Error, tt.code)
+		}
+	}
+}
+
+func TestServeHonorsProjectConfiguration(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, ".tree-sorter-ts.json")
+	if err := os.WriteFile(configPath, []byte(`{"ignore": ["generated/**"]}`), 0o644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	responses := serve(t, Options{ConfigPath: configPath},
+		requestLine(t, 1, "sortContent", map[string]any{"filePath": filepath.Join(dir, "generated", "api.ts"), "content": unsortedSource}),
+	)
+
+	var result SortResult
+	decodeResult(t, responses["1"], &result)
+	if !result.Ignored || result.Changed || result.Content != unsortedSource {
+		t.Errorf("expected the ignored file to be returned unchanged, got %+v", result)
+	}
+}
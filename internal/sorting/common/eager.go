@@ -0,0 +1,75 @@
+package common
+
+import (
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// lazyNodes are the nodes whose code does not run along with the code holding
+// them, or that only hold types
+var lazyNodes = map[string]bool{
+	"function_declaration":           true,
+	"generator_function_declaration": true,
+	"function_signature":             true,
+	"method_definition":              true,
+	"interface_declaration":          true,
+	"type_alias_declaration":         true,
+	"ambient_declaration":            true,
+	"type_annotation":                true,
+	"type_arguments":                 true,
+	"type_parameters":                true,
+}
+
+// functionNodes are the function expressions, whose bodies only run along
+// with the code holding them when they are called right away
+var functionNodes = map[string]bool{
+	"arrow_function":      true,
+	"function":            true,
+	"function_expression": true,
+	"generator_function":  true,
+}
+
+// WalkEager calls visit for node and the named nodes under it whose code runs
+// along with node, in document order. It leaves out functions and methods
+// that are not called right away, instance field initializers and types. The
+// nodes under a node are only visited when visit returns true.
+func WalkEager(node *sitter.Node, visit func(n *sitter.Node) bool) {
+	switch {
+	case lazyNodes[node.Type()]:
+		return
+	case functionNodes[node.Type()] && !isCalled(node):
+		return
+	case node.Type() == "public_field_definition" && !hasChildOfType(node, "static"):
+		return
+	}
+	if !visit(node) {
+		return
+	}
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		WalkEager(node.NamedChild(i), visit)
+	}
+}
+
+// isCalled reports whether a function expression is called right away, as in
+// (() => value)()
+func isCalled(fn *sitter.Node) bool {
+	n := fn
+	for n.Parent() != nil && n.Parent().Type() == "parenthesized_expression" {
+		n = n.Parent()
+	}
+	parent := n.Parent()
+	if parent == nil || parent.Type() != "call_expression" {
+		return false
+	}
+	callee := parent.ChildByFieldName("function")
+	return callee != nil && callee.StartByte() == n.StartByte() && callee.EndByte() == n.EndByte()
+}
+
+// hasChildOfType reports whether one of a node's children has the given type
+func hasChildOfType(node *sitter.Node, nodeType string) bool {
+	for i := 0; i < int(node.ChildCount()); i++ {
+		if node.Child(i).Type() == nodeType {
+			return true
+		}
+	}
+	return false
+}
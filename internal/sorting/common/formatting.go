@@ -0,0 +1,17 @@
+package common
+
+import (
+	"bytes"
+)
+
+// SpacingDiffers reports whether the gap between two items, from the end of
+// the first (including its comma and inline comment) to the start of the
+// second (including its leading comments), holds a different number of
+// newlines than asked for: two with with-new-line, one without
+func SpacingDiffers(gapStart, gapEnd uint32, withNewLine bool, content []byte) bool {
+	expectedNewlines := 1
+	if withNewLine {
+		expectedNewlines = 2
+	}
+	return bytes.Count(content[gapStart:gapEnd], []byte("\n")) != expectedNewlines
+}
@@ -1,6 +1,8 @@
 package interfaces
 
 import (
+	"github.com/evanrichards/tree-sorter-ts/internal/config"
+
 	sitter "github.com/smacker/go-tree-sitter"
 )
 
@@ -8,16 +10,16 @@ import (
 type SortableItem interface {
 	// GetSortKey returns the key used for sorting based on the strategy
 	GetSortKey(strategy SortStrategy, content []byte) (string, error)
-	
+
 	// IsDeprecated returns true if this item has @deprecated annotation
 	IsDeprecated() bool
-	
+
 	// GetNode returns the underlying AST node
 	GetNode() *sitter.Node
-	
+
 	// GetBeforeComments returns comments that appear before this item
 	GetBeforeComments() []*sitter.Node
-	
+
 	// GetAfterComment returns inline comment that appears after this item
 	GetAfterComment() *sitter.Node
 }
@@ -26,7 +28,7 @@ type SortableItem interface {
 type SortStrategy interface {
 	// ExtractKey extracts the sorting key from a sortable item
 	ExtractKey(item SortableItem, content []byte) (string, error)
-	
+
 	// GetName returns the strategy name for debugging
 	GetName() string
 }
@@ -35,37 +37,39 @@ type SortStrategy interface {
 type Sortable interface {
 	// Extract finds and extracts sortable items from the AST node
 	Extract(node *sitter.Node, content []byte) ([]SortableItem, error)
-	
+
 	// Sort applies the strategy to sort the items
 	Sort(items []SortableItem, strategy SortStrategy, deprecatedAtEnd bool, content []byte) ([]SortableItem, error)
-	
+
 	// CheckIfSorted determines if items are already sorted according to strategy
 	CheckIfSorted(items []SortableItem, strategy SortStrategy, deprecatedAtEnd bool, content []byte) bool
-	
+
+	// NeedsFormatting reports whether the blank lines between the items
+	// differ from what the with-new-line option asks for
+	NeedsFormatting(items []SortableItem, withNewLine bool, content []byte) bool
+
 	// GetMagicCommentIndex returns the index of the magic comment
 	GetMagicCommentIndex() int
-	
+
+	// GetMagicComment returns the magic comment node, the start-sort marker
+	// for a section
+	GetMagicComment() *sitter.Node
+
+	// GetEndIndex returns the index of the child ending the items: the
+	// end-sort marker of a section, or the child count when the items run
+	// to the closing delimiter
+	GetEndIndex() int
+
 	// GetNode returns the underlying AST node
 	GetNode() *sitter.Node
 }
 
-// Reconstructor rebuilds AST content with sorted items
+// Reconstructor rebuilds a structure with sorted items
 type Reconstructor interface {
-	// Reconstruct generates new content with sorted items
-	Reconstruct(sortable Sortable, sortedItems []SortableItem, config interface{}, content []byte) ([]byte, error)
-}
+	// Reconstruct returns the new text of the sortable's node, from its
+	// opening to its closing delimiter, with the items in the given order
+	Reconstruct(sortable Sortable, sortedItems []SortableItem, config config.SortConfig, content []byte) ([]byte, error)
 
-// SortConfig contains configuration options from the magic comment
-type SortConfig struct {
-	WithNewLine     bool
-	DeprecatedAtEnd bool
-	Key             string // For array sorting
-	SortByComment   bool   // Sort by comment content
-	HasError        bool   // Indicates a validation error
+	// CanHandle returns true if this reconstructor can handle the given sortable
+	CanHandle(sortable Sortable) bool
 }
-
-// StrategyFactory creates sorting strategies
-type StrategyFactory interface {
-	// CreateStrategy creates a strategy based on config
-	CreateStrategy(config SortConfig) (SortStrategy, error)
-}
\ No newline at end of file
@@ -0,0 +1,29 @@
+package strategies
+
+import (
+	"errors"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/interfaces"
+)
+
+// valued is implemented by items with a constant value, such as enum members
+type valued interface {
+	ValueKey() (string, bool)
+}
+
+// EnumValueStrategy sorts items by their value: numbers in numeric order
+// before strings. Items without a known value sort last.
+type EnumValueStrategy struct{}
+
+func (s *EnumValueStrategy) ExtractKey(item interfaces.SortableItem, content []byte) (string, error) {
+	if v, ok := item.(valued); ok {
+		if key, ok := v.ValueKey(); ok {
+			return key, nil
+		}
+	}
+	return "", errors.New("item has no constant value")
+}
+
+func (s *EnumValueStrategy) GetName() string {
+	return "enum-value"
+}
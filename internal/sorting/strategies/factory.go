@@ -10,6 +10,16 @@ type Factory struct{}
 
 // CreateStrategy creates the appropriate strategy based on config
 func (f *Factory) CreateStrategy(cfg config.SortConfig) (interfaces.SortStrategy, error) {
+	if cfg.GroupByKind {
+		inner := cfg
+		inner.GroupByKind = false
+		strategy, err := f.CreateStrategy(inner)
+		if err != nil {
+			return nil, err
+		}
+		return &MemberKindStrategy{Inner: strategy}, nil
+	}
+
 	if cfg.SortByComment {
 		return &CommentContentStrategy{}, nil
 	}
@@ -17,6 +27,10 @@ func (f *Factory) CreateStrategy(cfg config.SortConfig) (interfaces.SortStrategy
 	if cfg.Key != "" {
 		return &ArrayKeyStrategy{KeyPath: cfg.Key}, nil
 	}
+
+	if cfg.SortByValue {
+		return &EnumValueStrategy{}, nil
+	}
 	
 	return &PropertyNameStrategy{}, nil
 }
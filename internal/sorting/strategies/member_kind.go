@@ -0,0 +1,38 @@
+package strategies
+
+import (
+	"fmt"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/interfaces"
+)
+
+// kindRanked is implemented by items that belong to an ordered group, such
+// as class members grouped into fields, the constructor and methods
+type kindRanked interface {
+	KindRank() int
+}
+
+// MemberKindStrategy groups items by kind and sorts each group with the
+// wrapped strategy. Items without a kind are sorted by the wrapped strategy
+// alone.
+type MemberKindStrategy struct {
+	Inner interfaces.SortStrategy
+}
+
+func (s *MemberKindStrategy) ExtractKey(item interfaces.SortableItem, content []byte) (string, error) {
+	key, err := item.GetSortKey(s.Inner, content)
+	if err != nil {
+		return "", err
+	}
+
+	ranked, ok := item.(kindRanked)
+	if !ok {
+		return key, nil
+	}
+	// Ranks are single digits, so the prefix sorts groups in order
+	return fmt.Sprintf("%d\x00%s", ranked.KindRank(), key), nil
+}
+
+func (s *MemberKindStrategy) GetName() string {
+	return "group-by-kind+" + s.Inner.GetName()
+}
@@ -18,9 +18,9 @@ func (s *PropertyNameStrategy) ExtractKey(item interfaces.SortableItem, content
 		// For object properties, return the property key
 		return typedItem.Key, nil
 	case *arrays.Element:
-		// For array elements, extract value as string
-		nodeText := strings.TrimSpace(string(content[typedItem.GetNode().StartByte():typedItem.GetNode().EndByte()]))
-		return common.TrimQuotes(nodeText), nil
+		// For array elements, use the raw text so that quoted numbers still
+		// compare as strings
+		return string(content[typedItem.GetNode().StartByte():typedItem.GetNode().EndByte()]), nil
 	default:
 		// Fallback for other types
 		nodeText := strings.TrimSpace(string(content[item.GetNode().StartByte():item.GetNode().EndByte()]))
@@ -15,6 +15,7 @@ type ArraySorter struct {
 	node         *sitter.Node
 	magicComment *sitter.Node
 	magicIndex   int
+	endIndex     int
 }
 
 // NewArraySorter creates a new array sorter
@@ -23,6 +24,18 @@ func NewArraySorter(arrayNode, magicComment *sitter.Node, magicIndex int) *Array
 		node:         arrayNode,
 		magicComment: magicComment,
 		magicIndex:   magicIndex,
+		endIndex:     int(arrayNode.ChildCount()),
+	}
+}
+
+// NewArraySection creates a sorter for the elements between a start-sort marker
+// and the end-sort marker at endIndex
+func NewArraySection(arrayNode, startMarker *sitter.Node, startIndex, endIndex int) *ArraySorter {
+	return &ArraySorter{
+		node:         arrayNode,
+		magicComment: startMarker,
+		magicIndex:   startIndex,
+		endIndex:     endIndex,
 	}
 }
 
@@ -34,7 +47,7 @@ func (a *ArraySorter) Extract(node *sitter.Node, content []byte) ([]interfaces.S
 	// Start after magic comment
 	startIdx := a.magicIndex + 1
 
-	for i := startIdx; i < int(a.node.ChildCount()); i++ {
+	for i := startIdx; i < a.endIndex; i++ {
 		child := a.node.Child(i)
 
 		switch child.Type() {
@@ -61,7 +74,7 @@ func (a *ArraySorter) Extract(node *sitter.Node, content []byte) ([]interfaces.S
 			// Check if followed by comma and/or inline comment
 			j := i + 1
 			continueLoop := true
-			for j < int(a.node.ChildCount()) && continueLoop {
+			for j < a.endIndex && continueLoop {
 				next := a.node.Child(j)
 				switch next.Type() {
 				case ",":
@@ -110,7 +123,7 @@ func (a *ArraySorter) Sort(items []interfaces.SortableItem, strategy interfaces.
 		sortKey, err := item.GetSortKey(strategy, content)
 		if err != nil {
 			// For missing/invalid keys, mark with special prefix to sort last
-			elem.SortKey = "\uffff" + "unknown"
+			elem.SortKey = "\uffff" + string(content[elem.Node.StartByte():elem.Node.EndByte()])
 		} else {
 			elem.SortKey = sortKey
 		}
@@ -180,12 +193,56 @@ func (a *ArraySorter) CheckIfSorted(items []interfaces.SortableItem, strategy in
 	return true
 }
 
+// NeedsFormatting determines if the spacing between elements differs from
+// what the with-new-line option asks for. Single-line arrays are left as they
+// are.
+func (a *ArraySorter) NeedsFormatting(items []interfaces.SortableItem, withNewLine bool, content []byte) bool {
+	if len(items) > 0 && items[0].GetNode().StartPoint().Row == items[len(items)-1].GetNode().EndPoint().Row {
+		return false
+	}
+
+	for i := 0; i < len(items)-1; i++ {
+		elem := items[i].(*Element)
+		nextElem := items[i+1].(*Element)
+
+		// Find the end of current element (including comma and inline comment)
+		endNode := elem.Node
+		if elem.AfterNode != nil {
+			endNode = elem.AfterNode
+		} else if elem.CommaNode != nil {
+			endNode = elem.CommaNode
+		}
+
+		// Handle comments before the next element
+		startNode := nextElem.Node
+		if len(nextElem.BeforeNodes) > 0 {
+			startNode = nextElem.BeforeNodes[0]
+		}
+
+		if common.SpacingDiffers(endNode.EndByte(), startNode.StartByte(), withNewLine, content) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // GetMagicCommentIndex returns the index of the magic comment
 func (a *ArraySorter) GetMagicCommentIndex() int {
 	return a.magicIndex
 }
 
+// GetEndIndex returns the index of the child ending the elements
+func (a *ArraySorter) GetEndIndex() int {
+	return a.endIndex
+}
+
 // GetNode returns the underlying AST node
 func (a *ArraySorter) GetNode() *sitter.Node {
 	return a.node
-}
\ No newline at end of file
+}
+
+// GetMagicComment returns the magic comment node
+func (a *ArraySorter) GetMagicComment() *sitter.Node {
+	return a.magicComment
+}
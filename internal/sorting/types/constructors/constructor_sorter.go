@@ -0,0 +1,217 @@
+package constructors
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/common"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/interfaces"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// ConstructorSorter handles sorting of the parameters of a formal parameter
+// list, usually a constructor's
+type ConstructorSorter struct {
+	node         *sitter.Node
+	magicComment *sitter.Node
+	magicIndex   int
+	endIndex     int
+}
+
+// NewConstructorSorter creates a new constructor sorter
+func NewConstructorSorter(formalParams, magicComment *sitter.Node, magicIndex int) *ConstructorSorter {
+	return &ConstructorSorter{
+		node:         formalParams,
+		magicComment: magicComment,
+		magicIndex:   magicIndex,
+		endIndex:     int(formalParams.ChildCount()),
+	}
+}
+
+// NewConstructorSection creates a sorter for the parameters between a start-sort marker
+// and the end-sort marker at endIndex
+func NewConstructorSection(formalParams, startMarker *sitter.Node, startIndex, endIndex int) *ConstructorSorter {
+	return &ConstructorSorter{
+		node:         formalParams,
+		magicComment: startMarker,
+		magicIndex:   startIndex,
+		endIndex:     endIndex,
+	}
+}
+
+// IsParameter reports whether a child of a formal parameter list is a
+// parameter
+func IsParameter(node *sitter.Node) bool {
+	return node.Type() == "required_parameter" || node.Type() == "optional_parameter"
+}
+
+// Extract finds and extracts sortable parameters from the parameter list
+func (c *ConstructorSorter) Extract(node *sitter.Node, content []byte) ([]interfaces.SortableItem, error) {
+	var params []interfaces.SortableItem
+	var pendingComments []*sitter.Node
+
+	// Start after magic comment
+	startIdx := c.magicIndex + 1
+
+	for i := startIdx; i < c.endIndex; i++ {
+		child := c.node.Child(i)
+
+		switch {
+		case child.Type() == "comment":
+			// Accumulate comments
+			pendingComments = append(pendingComments, child)
+
+		case IsParameter(child):
+			param := NewParameter(child, content)
+			param.BeforeNodes = pendingComments
+
+			// Check if this parameter has @deprecated annotation
+			param.isDeprecated = common.HasDeprecatedAnnotation(pendingComments, content)
+
+			// Check if followed by comma and/or inline comment
+			j := i + 1
+			continueLoop := true
+			lastNode := child // Track the last node for line comparison
+			for j < c.endIndex && continueLoop {
+				next := c.node.Child(j)
+				switch next.Type() {
+				case ",":
+					param.HasComma = true
+					param.CommaNode = next
+					lastNode = next
+					j++
+				case "comment":
+					// Check if it's on the same line as the parameter or comma
+					if next.StartPoint().Row == lastNode.EndPoint().Row {
+						param.AfterNode = next
+						j++
+					} else {
+						continueLoop = false
+					}
+				default:
+					continueLoop = false
+				}
+			}
+			i = j - 1 // Update loop counter to skip processed nodes
+
+			// Also check inline comment for @deprecated
+			if !param.isDeprecated && param.AfterNode != nil {
+				text := string(content[param.AfterNode.StartByte():param.AfterNode.EndByte()])
+				if strings.Contains(text, "@deprecated") {
+					param.isDeprecated = true
+				}
+			}
+
+			params = append(params, param)
+			pendingComments = nil // Reset comments
+		}
+	}
+
+	return params, nil
+}
+
+// Sort orders the parameters by name, considering the deprecated-at-end flag
+func (c *ConstructorSorter) Sort(items []interfaces.SortableItem, strategy interfaces.SortStrategy, deprecatedAtEnd bool, content []byte) ([]interfaces.SortableItem, error) {
+	if len(items) <= 1 {
+		return items, nil
+	}
+
+	// Make a copy for sorting
+	sorted := make([]interfaces.SortableItem, len(items))
+	copy(sorted, items)
+
+	if deprecatedAtEnd {
+		sort.Slice(sorted, func(i, j int) bool {
+			paramI := sorted[i].(*Parameter)
+			paramJ := sorted[j].(*Parameter)
+			// If one is deprecated and the other isn't, put non-deprecated first
+			if paramI.isDeprecated != paramJ.isDeprecated {
+				return !paramI.isDeprecated
+			}
+			// Otherwise sort alphabetically by parameter name
+			return paramI.Name < paramJ.Name
+		})
+	} else {
+		sort.Slice(sorted, func(i, j int) bool {
+			return sorted[i].(*Parameter).Name < sorted[j].(*Parameter).Name
+		})
+	}
+
+	return sorted, nil
+}
+
+// CheckIfSorted determines if parameters are already sorted by name
+func (c *ConstructorSorter) CheckIfSorted(items []interfaces.SortableItem, strategy interfaces.SortStrategy, deprecatedAtEnd bool, content []byte) bool {
+	if len(items) <= 1 {
+		return true
+	}
+
+	sorted, err := c.Sort(items, strategy, deprecatedAtEnd, content)
+	if err != nil {
+		return false
+	}
+
+	for i := range items {
+		paramOriginal := items[i].(*Parameter)
+		paramSorted := sorted[i].(*Parameter)
+		if paramOriginal.Name != paramSorted.Name {
+			return false
+		}
+		// For deprecated-at-end, also check if deprecated parameters are in the right place
+		if deprecatedAtEnd && paramOriginal.isDeprecated != paramSorted.isDeprecated {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NeedsFormatting determines if the spacing between parameters differs from
+// what the with-new-line option asks for
+func (c *ConstructorSorter) NeedsFormatting(items []interfaces.SortableItem, withNewLine bool, content []byte) bool {
+	for i := 0; i < len(items)-1; i++ {
+		param := items[i].(*Parameter)
+		nextParam := items[i+1].(*Parameter)
+
+		// Find the end of current parameter (including comma and inline comment)
+		endNode := param.Node
+		if param.AfterNode != nil {
+			endNode = param.AfterNode
+		} else if param.CommaNode != nil {
+			endNode = param.CommaNode
+		}
+
+		// Handle comments before the next parameter
+		startNode := nextParam.Node
+		if len(nextParam.BeforeNodes) > 0 {
+			startNode = nextParam.BeforeNodes[0]
+		}
+
+		if common.SpacingDiffers(endNode.EndByte(), startNode.StartByte(), withNewLine, content) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetMagicCommentIndex returns the index of the magic comment
+func (c *ConstructorSorter) GetMagicCommentIndex() int {
+	return c.magicIndex
+}
+
+// GetEndIndex returns the index of the child ending the parameters
+func (c *ConstructorSorter) GetEndIndex() int {
+	return c.endIndex
+}
+
+// GetMagicComment returns the magic comment node
+func (c *ConstructorSorter) GetMagicComment() *sitter.Node {
+	return c.magicComment
+}
+
+// GetNode returns the underlying AST node
+func (c *ConstructorSorter) GetNode() *sitter.Node {
+	return c.node
+}
@@ -0,0 +1,79 @@
+package constructors
+
+import (
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/interfaces"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// Parameter represents a constructor parameter that can be sorted
+type Parameter struct {
+	Node         *sitter.Node   // The required_parameter or optional_parameter node
+	Name         string         // Parameter name (from its pattern)
+	BeforeNodes  []*sitter.Node // Comments before this parameter
+	AfterNode    *sitter.Node   // Inline comment after parameter
+	HasComma     bool
+	CommaNode    *sitter.Node
+	isDeprecated bool
+}
+
+// GetSortKey returns the key for sorting. Parameters are always sorted by
+// name, whatever the strategy.
+func (p *Parameter) GetSortKey(strategy interfaces.SortStrategy, content []byte) (string, error) {
+	return p.Name, nil
+}
+
+// IsDeprecated returns true if this parameter has @deprecated annotation
+func (p *Parameter) IsDeprecated() bool {
+	return p.isDeprecated
+}
+
+// GetNode returns the underlying AST node
+func (p *Parameter) GetNode() *sitter.Node {
+	return p.Node
+}
+
+// GetBeforeComments returns comments that appear before this parameter
+func (p *Parameter) GetBeforeComments() []*sitter.Node {
+	return p.BeforeNodes
+}
+
+// GetAfterComment returns inline comment that appears after this parameter
+func (p *Parameter) GetAfterComment() *sitter.Node {
+	return p.AfterNode
+}
+
+// NewParameter creates a new Parameter from a parameter node
+func NewParameter(node *sitter.Node, content []byte) *Parameter {
+	return &Parameter{
+		Node: node,
+		Name: parameterName(node, content),
+	}
+}
+
+// parameterName extracts the name a parameter is sorted by from its pattern
+func parameterName(node *sitter.Node, content []byte) string {
+	patternNode := node.ChildByFieldName("pattern")
+	if patternNode == nil {
+		return ""
+	}
+
+	switch patternNode.Type() {
+	case "identifier":
+		// Simple parameter like: someParam: string
+		return string(content[patternNode.StartByte():patternNode.EndByte()])
+	case "object_pattern":
+		// Destructured parameter like: { someParam }: { someParam: string }
+		// For sorting, use the first property name
+		for i := 0; i < int(patternNode.ChildCount()); i++ {
+			propChild := patternNode.Child(i)
+			if propChild.Type() == "shorthand_property_identifier_pattern" {
+				return string(content[propChild.StartByte():propChild.EndByte()])
+			}
+		}
+		return ""
+	default:
+		// For other patterns (array destructuring, etc.), use the full text
+		return string(content[patternNode.StartByte():patternNode.EndByte()])
+	}
+}
@@ -0,0 +1,73 @@
+package enums
+
+import (
+	"strings"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/common"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/interfaces"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// EnumMember represents an enum member that can be sorted
+type EnumMember struct {
+	Node        *sitter.Node
+	Name        string
+	SortKey     string         // The key used for sorting (may be different from name when sorting by value or comment)
+	Value       *Value         // The member's value; nil when it is not a known constant
+	BeforeNodes []*sitter.Node // Comments before this member
+	AfterNode   *sitter.Node   // Inline comment after member
+	CommaNode   *sitter.Node   // The comma following the member, if any
+	// Materialize is set for members whose value depends on their position
+	// or on other members: the value is written out when they are moved
+	Materialize  bool
+	isDeprecated bool
+}
+
+// GetSortKey returns the key for sorting based on the strategy
+func (m *EnumMember) GetSortKey(strategy interfaces.SortStrategy, content []byte) (string, error) {
+	// Only sorting by comment or value looks past the member name; the key
+	// option applies to array elements
+	if name := strategy.GetName(); name != "comment-content" && name != "enum-value" {
+		return m.Name, nil
+	}
+
+	return strategy.ExtractKey(m, content)
+}
+
+// ValueKey returns the key sorting the member by its value, or false when
+// the value is not known
+func (m *EnumMember) ValueKey() (string, bool) {
+	if m.Value == nil {
+		return "", false
+	}
+	return m.Value.SortKey(), true
+}
+
+// IsDeprecated returns true if this member has @deprecated annotation
+func (m *EnumMember) IsDeprecated() bool {
+	return m.isDeprecated
+}
+
+// GetNode returns the underlying AST node
+func (m *EnumMember) GetNode() *sitter.Node {
+	return m.Node
+}
+
+// GetBeforeComments returns comments that appear before this member
+func (m *EnumMember) GetBeforeComments() []*sitter.Node {
+	return m.BeforeNodes
+}
+
+// GetAfterComment returns inline comment that appears after this member
+func (m *EnumMember) GetAfterComment() *sitter.Node {
+	return m.AfterNode
+}
+
+// memberName returns the name of an enum member, without quotes
+func memberName(node *sitter.Node, content []byte) string {
+	if node.Type() == "enum_assignment" {
+		node = node.ChildByFieldName("name")
+	}
+	return common.TrimQuotes(strings.TrimSpace(node.Content(content)))
+}
@@ -0,0 +1,310 @@
+package enums
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/common"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/interfaces"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// EnumSorter handles sorting of enum members
+type EnumSorter struct {
+	node         *sitter.Node
+	magicComment *sitter.Node
+	magicIndex   int
+	endIndex     int
+}
+
+// NewEnumSorter creates a new enum sorter
+func NewEnumSorter(enumBody, magicComment *sitter.Node, magicIndex int) *EnumSorter {
+	return &EnumSorter{
+		node:         enumBody,
+		magicComment: magicComment,
+		magicIndex:   magicIndex,
+		endIndex:     int(enumBody.ChildCount()),
+	}
+}
+
+// NewEnumSection creates a sorter for the members between a start-sort marker
+// and the end-sort marker at endIndex
+func NewEnumSection(enumBody, startMarker *sitter.Node, startIndex, endIndex int) *EnumSorter {
+	return &EnumSorter{
+		node:         enumBody,
+		magicComment: startMarker,
+		magicIndex:   startIndex,
+		endIndex:     endIndex,
+	}
+}
+
+// IsEnumBody reports whether a node is the body of an enum
+func IsEnumBody(node *sitter.Node) bool {
+	return node.Type() == "enum_body"
+}
+
+// IsEnumMember reports whether a child of an enum body is a member
+func IsEnumMember(node *sitter.Node) bool {
+	return node.IsNamed() && node.Type() != "comment"
+}
+
+// CheckValues returns an error describing why sorting would change the value
+// of a member, or nil when the members can be reordered safely. Members
+// without an initializer are numbered by their position, and initializers
+// may only refer to members declared before them. With materialize, such
+// members are fine as long as their values are constants that can be
+// written out.
+func (e *EnumSorter) CheckValues(content []byte, materialize bool) error {
+	members := evaluateMembers(e.node, content)
+	sorted := e.sortedNames(content)
+
+	for _, m := range members {
+		if !sorted[m.name] {
+			continue
+		}
+		if m.init == nil {
+			if !materialize {
+				return fmt.Errorf("enum member %q has an implicit value that sorting would change; add materialize-values to write the values out", m.name)
+			}
+			if !canMaterialize(m) {
+				return fmt.Errorf("enum member %q has an implicit value that cannot be written out", m.name)
+			}
+		}
+		for _, ref := range m.refersTo {
+			if !sorted[ref] {
+				continue
+			}
+			if !materialize {
+				return fmt.Errorf("enum member %q refers to member %q, which sorting may move after it; add materialize-values to write the values out", m.name, ref)
+			}
+			if !canMaterialize(m) {
+				return fmt.Errorf("enum member %q refers to member %q and its value cannot be written out", m.name, ref)
+			}
+		}
+	}
+
+	// The member following a section is numbered after its last member
+	if e.endIndex < int(e.node.ChildCount()) {
+		for i := e.endIndex + 1; i < int(e.node.ChildCount()); i++ {
+			child := e.node.Child(i)
+			if !IsEnumMember(child) {
+				continue
+			}
+			if child.Type() != "enum_assignment" {
+				return fmt.Errorf("enum member %q after the section has an implicit value that depends on the last member of the section", memberName(child, content))
+			}
+			break
+		}
+	}
+
+	return nil
+}
+
+// sortedNames returns the names of the members the sorter reorders
+func (e *EnumSorter) sortedNames(content []byte) map[string]bool {
+	names := make(map[string]bool)
+	for i := e.magicIndex + 1; i < e.endIndex; i++ {
+		if child := e.node.Child(i); IsEnumMember(child) {
+			names[memberName(child, content)] = true
+		}
+	}
+	return names
+}
+
+// canMaterialize reports whether a member's value can be written as a literal
+func canMaterialize(m memberValue) bool {
+	if m.value == nil {
+		return false
+	}
+	_, ok := m.value.Literal()
+	return ok
+}
+
+// Extract finds and extracts sortable members from the enum body
+func (e *EnumSorter) Extract(node *sitter.Node, content []byte) ([]interfaces.SortableItem, error) {
+	values := make(map[uint32]memberValue)
+	for _, m := range evaluateMembers(e.node, content) {
+		values[m.node.StartByte()] = m
+	}
+	sorted := e.sortedNames(content)
+
+	var members []interfaces.SortableItem
+	var pendingComments []*sitter.Node
+
+	// Start after magic comment
+	startIdx := e.magicIndex + 1
+
+	for i := startIdx; i < e.endIndex; i++ {
+		child := e.node.Child(i)
+
+		switch {
+		case child.Type() == "comment":
+			// Accumulate comments
+			pendingComments = append(pendingComments, child)
+
+		case IsEnumMember(child):
+			value := values[child.StartByte()]
+			member := &EnumMember{
+				Node:         child,
+				Name:         value.name,
+				Value:        value.value,
+				BeforeNodes:  pendingComments,
+				isDeprecated: common.HasDeprecatedAnnotation(pendingComments, content),
+			}
+			member.Materialize = value.init == nil
+			for _, ref := range value.refersTo {
+				if sorted[ref] {
+					member.Materialize = true
+				}
+			}
+
+			// Check if followed by comma and/or inline comment
+			j := i + 1
+			continueLoop := true
+			lastNode := child // Track the last node for line comparison
+			for j < e.endIndex && continueLoop {
+				next := e.node.Child(j)
+				switch {
+				case next.Type() == "," && member.CommaNode == nil:
+					member.CommaNode = next
+					lastNode = next
+					j++
+				case next.Type() == "comment" && next.StartPoint().Row == lastNode.EndPoint().Row:
+					member.AfterNode = next
+					j++
+					continueLoop = false
+				default:
+					continueLoop = false
+				}
+			}
+			i = j - 1 // Update loop counter to skip processed nodes
+
+			// Also check inline comment for @deprecated
+			if !member.isDeprecated && member.AfterNode != nil {
+				text := string(content[member.AfterNode.StartByte():member.AfterNode.EndByte()])
+				if strings.Contains(text, "@deprecated") {
+					member.isDeprecated = true
+				}
+			}
+
+			members = append(members, member)
+			pendingComments = nil // Reset comments
+		}
+	}
+
+	return members, nil
+}
+
+// Sort applies the strategy to sort the members, considering the
+// deprecated-at-end flag. Members with the same key keep their order.
+func (e *EnumSorter) Sort(items []interfaces.SortableItem, strategy interfaces.SortStrategy, deprecatedAtEnd bool, content []byte) ([]interfaces.SortableItem, error) {
+	if len(items) <= 1 {
+		return items, nil
+	}
+
+	// Extract sort keys for each member
+	for _, item := range items {
+		member := item.(*EnumMember)
+		sortKey, err := item.GetSortKey(strategy, content)
+		if err != nil {
+			// For missing comments or values, mark with special prefix to sort last
+			member.SortKey = "\uffff" + member.Name
+		} else {
+			member.SortKey = sortKey
+		}
+	}
+
+	// Make a copy for sorting
+	sorted := make([]interfaces.SortableItem, len(items))
+	copy(sorted, items)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		memberI := sorted[i].(*EnumMember)
+		memberJ := sorted[j].(*EnumMember)
+		// If one is deprecated and the other isn't, put non-deprecated first
+		if deprecatedAtEnd && memberI.isDeprecated != memberJ.isDeprecated {
+			return !memberI.isDeprecated
+		}
+		return memberI.SortKey < memberJ.SortKey
+	})
+
+	return sorted, nil
+}
+
+// CheckIfSorted determines if members are already sorted according to
+// strategy. Members whose values still have to be written out are not.
+func (e *EnumSorter) CheckIfSorted(items []interfaces.SortableItem, strategy interfaces.SortStrategy, deprecatedAtEnd bool, content []byte) bool {
+	for _, item := range items {
+		if item.(*EnumMember).Materialize {
+			return false
+		}
+	}
+	if len(items) <= 1 {
+		return true
+	}
+
+	sorted, err := e.Sort(items, strategy, deprecatedAtEnd, content)
+	if err != nil {
+		return false
+	}
+
+	// The sort is stable, so sorted members keep their positions
+	for i := range items {
+		if items[i] != sorted[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NeedsFormatting determines if the spacing between members differs from
+// what the with-new-line option asks for
+func (e *EnumSorter) NeedsFormatting(items []interfaces.SortableItem, withNewLine bool, content []byte) bool {
+	for i := 0; i < len(items)-1; i++ {
+		member := items[i].(*EnumMember)
+		nextMember := items[i+1].(*EnumMember)
+
+		// Find the end of current member (including comma and inline comment)
+		endNode := member.Node
+		if member.AfterNode != nil {
+			endNode = member.AfterNode
+		} else if member.CommaNode != nil {
+			endNode = member.CommaNode
+		}
+
+		// Handle comments before the next member
+		startNode := nextMember.Node
+		if len(nextMember.BeforeNodes) > 0 {
+			startNode = nextMember.BeforeNodes[0]
+		}
+
+		if common.SpacingDiffers(endNode.EndByte(), startNode.StartByte(), withNewLine, content) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetMagicCommentIndex returns the index of the magic comment
+func (e *EnumSorter) GetMagicCommentIndex() int {
+	return e.magicIndex
+}
+
+// GetEndIndex returns the index of the child ending the members
+func (e *EnumSorter) GetEndIndex() int {
+	return e.endIndex
+}
+
+// GetMagicComment returns the magic comment node
+func (e *EnumSorter) GetMagicComment() *sitter.Node {
+	return e.magicComment
+}
+
+// GetNode returns the underlying AST node
+func (e *EnumSorter) GetNode() *sitter.Node {
+	return e.node
+}
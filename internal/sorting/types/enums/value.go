@@ -0,0 +1,337 @@
+package enums
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/common"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// Value is the constant value of an enum member: a number or a string
+type Value struct {
+	Number   float64
+	String   string
+	IsString bool
+}
+
+// Literal returns the value written as a TypeScript literal, or false when
+// it has none, as for NaN and the infinities
+func (v Value) Literal() (string, bool) {
+	if v.IsString {
+		return quote(v.String), true
+	}
+	if math.IsNaN(v.Number) || math.IsInf(v.Number, 0) {
+		return "", false
+	}
+	if v.Number == math.Trunc(v.Number) && math.Abs(v.Number) < 1e15 {
+		return strconv.FormatInt(int64(v.Number), 10), true
+	}
+	return strconv.FormatFloat(v.Number, 'g', -1, 64), true
+}
+
+// quote writes s as a double-quoted JavaScript string literal. Printable
+// characters are written as they are and others as \uXXXX escapes, with a
+// surrogate pair for characters outside the Basic Multilingual Plane, since
+// Go escapes such as \a and \U mean something else in JavaScript.
+func quote(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case r == '\n':
+			sb.WriteString(`\n`)
+		case r == '\r':
+			sb.WriteString(`\r`)
+		case r == '\t':
+			sb.WriteString(`\t`)
+		case unicode.IsPrint(r):
+			sb.WriteRune(r)
+		case r > 0xFFFF:
+			high, low := utf16.EncodeRune(r)
+			fmt.Fprintf(&sb, `\u%04x\u%04x`, high, low)
+		default:
+			fmt.Fprintf(&sb, `\u%04x`, r)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+// SortKey returns a key that orders numbers numerically and before strings
+func (v Value) SortKey() string {
+	if v.IsString {
+		return "1" + v.String
+	}
+	// Flip the bits of the float so that unsigned comparison orders it
+	bits := math.Float64bits(v.Number)
+	if bits&(1<<63) != 0 {
+		bits = ^bits
+	} else {
+		bits |= 1 << 63
+	}
+	return fmt.Sprintf("0%016x", bits)
+}
+
+// memberValue is an enum member along with the value it evaluates to
+type memberValue struct {
+	node     *sitter.Node
+	name     string
+	init     *sitter.Node // The initializer; nil for implicitly valued members
+	value    *Value       // nil when the value is not a constant this package can compute
+	refersTo []string     // Names of the other members the initializer refers to
+}
+
+// evaluateMembers computes the values of all members of an enum body in
+// declaration order, the way the TypeScript compiler numbers them: a member
+// without an initializer is one more than the member before it, or 0 when
+// it comes first
+func evaluateMembers(body *sitter.Node, content []byte) []memberValue {
+	enumName := ""
+	if decl := body.Parent(); decl != nil {
+		if name := decl.ChildByFieldName("name"); name != nil {
+			enumName = name.Content(content)
+		}
+	}
+
+	var members []memberValue
+	known := make(map[string]Value)
+	for i := 0; i < int(body.ChildCount()); i++ {
+		child := body.Child(i)
+		if !IsEnumMember(child) {
+			continue
+		}
+
+		m := memberValue{node: child, name: memberName(child, content)}
+		if child.Type() == "enum_assignment" {
+			m.init = child.ChildByFieldName("value")
+		}
+
+		switch {
+		case m.init != nil:
+			e := evaluator{enumName: enumName, known: known, content: content}
+			if v, ok := e.eval(m.init); ok {
+				m.value = &v
+			}
+			m.refersTo = e.references(m.init, m.name)
+		case len(members) == 0:
+			m.value = &Value{}
+		default:
+			if prev := members[len(members)-1].value; prev != nil && !prev.IsString {
+				m.value = &Value{Number: prev.Number + 1}
+			}
+		}
+
+		if m.value != nil {
+			known[m.name] = *m.value
+		}
+		members = append(members, m)
+	}
+	return members
+}
+
+// MemberValues returns each member of an enum body as "name = value", with
+// the value computed where possible and written as in the source otherwise.
+// Two enum bodies with the same member values describe the same enum.
+func MemberValues(body *sitter.Node, content []byte) []string {
+	var values []string
+	for _, m := range evaluateMembers(body, content) {
+		if m.value != nil {
+			if literal, ok := m.value.Literal(); ok {
+				values = append(values, m.name+" = "+literal)
+				continue
+			}
+		}
+		values = append(values, strings.TrimSpace(m.node.Content(content)))
+	}
+	return values
+}
+
+// evaluator computes constant enum member initializers, which may refer to
+// the members declared before them
+type evaluator struct {
+	enumName string
+	known    map[string]Value
+	content  []byte
+}
+
+// memberRef returns the name of the member of the same enum that node
+// refers to, as in B, E.B or E["B"]
+func (e evaluator) memberRef(node *sitter.Node) (string, bool) {
+	switch node.Type() {
+	case "identifier":
+		return node.Content(e.content), true
+	case "member_expression", "subscript_expression":
+		object := node.ChildByFieldName("object")
+		if object == nil || object.Type() != "identifier" || object.Content(e.content) != e.enumName {
+			return "", false
+		}
+		if property := node.ChildByFieldName("property"); property != nil {
+			return property.Content(e.content), true
+		}
+		if index := node.ChildByFieldName("index"); index != nil && index.Type() == "string" {
+			return common.TrimQuotes(index.Content(e.content)), true
+		}
+	}
+	return "", false
+}
+
+// references returns the names of the members an initializer refers to,
+// other than the member itself
+func (e evaluator) references(init *sitter.Node, self string) []string {
+	var refs []string
+	var walk func(n *sitter.Node)
+	walk = func(n *sitter.Node) {
+		if name, ok := e.memberRef(n); ok {
+			if name != self && name != e.enumName {
+				refs = append(refs, name)
+			}
+			return
+		}
+		for i := 0; i < int(n.NamedChildCount()); i++ {
+			walk(n.NamedChild(i))
+		}
+	}
+	walk(init)
+	return refs
+}
+
+// eval computes the value of a constant expression
+func (e evaluator) eval(node *sitter.Node) (Value, bool) {
+	switch node.Type() {
+	case "number":
+		n, ok := parseNumber(node.Content(e.content))
+		return Value{Number: n}, ok
+	case "string":
+		text := node.Content(e.content)
+		// Escapes are not interpreted, so only plain strings are constants here
+		if strings.Contains(text, `\`) {
+			return Value{}, false
+		}
+		return Value{String: common.TrimQuotes(text), IsString: true}, true
+	case "parenthesized_expression":
+		if node.NamedChildCount() != 1 {
+			return Value{}, false
+		}
+		return e.eval(node.NamedChild(0))
+	case "unary_expression":
+		return e.evalUnary(node)
+	case "binary_expression":
+		return e.evalBinary(node)
+	}
+
+	if name, ok := e.memberRef(node); ok {
+		v, ok := e.known[name]
+		return v, ok
+	}
+	return Value{}, false
+}
+
+// evalUnary computes the value of a numeric unary expression
+func (e evaluator) evalUnary(node *sitter.Node) (Value, bool) {
+	operator := node.ChildByFieldName("operator")
+	argument := node.ChildByFieldName("argument")
+	if operator == nil || argument == nil {
+		return Value{}, false
+	}
+	v, ok := e.eval(argument)
+	if !ok || v.IsString {
+		return Value{}, false
+	}
+
+	switch operator.Type() {
+	case "-":
+		return Value{Number: -v.Number}, true
+	case "+":
+		return v, true
+	case "~":
+		return Value{Number: float64(^toInt32(v.Number))}, true
+	}
+	return Value{}, false
+}
+
+// evalBinary computes the value of a binary expression on numbers, or the
+// concatenation of two strings
+func (e evaluator) evalBinary(node *sitter.Node) (Value, bool) {
+	operator := node.ChildByFieldName("operator")
+	left := node.ChildByFieldName("left")
+	right := node.ChildByFieldName("right")
+	if operator == nil || left == nil || right == nil {
+		return Value{}, false
+	}
+	l, ok := e.eval(left)
+	if !ok {
+		return Value{}, false
+	}
+	r, ok := e.eval(right)
+	if !ok {
+		return Value{}, false
+	}
+
+	if l.IsString || r.IsString {
+		if operator.Type() == "+" && l.IsString && r.IsString {
+			return Value{String: l.String + r.String, IsString: true}, true
+		}
+		return Value{}, false
+	}
+
+	a, b := l.Number, r.Number
+	var n float64
+	switch operator.Type() {
+	case "+":
+		n = a + b
+	case "-":
+		n = a - b
+	case "*":
+		n = a * b
+	case "/":
+		n = a / b
+	case "%":
+		n = math.Mod(a, b)
+	case "**":
+		n = math.Pow(a, b)
+	case "|":
+		n = float64(toInt32(a) | toInt32(b))
+	case "&":
+		n = float64(toInt32(a) & toInt32(b))
+	case "^":
+		n = float64(toInt32(a) ^ toInt32(b))
+	case "<<":
+		n = float64(toInt32(a) << (uint32(toInt32(b)) & 31))
+	case ">>":
+		n = float64(toInt32(a) >> (uint32(toInt32(b)) & 31))
+	case ">>>":
+		n = float64(uint32(toInt32(a)) >> (uint32(toInt32(b)) & 31))
+	default:
+		return Value{}, false
+	}
+	return Value{Number: n}, true
+}
+
+// toInt32 converts a number the way JavaScript bitwise operators do
+func toInt32(n float64) int32 {
+	if math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0
+	}
+	return int32(uint32(int64(math.Trunc(n))))
+}
+
+// parseNumber parses a JavaScript numeric literal
+func parseNumber(text string) (float64, bool) {
+	text = strings.ReplaceAll(text, "_", "")
+	lower := strings.ToLower(text)
+	if strings.HasPrefix(lower, "0x") || strings.HasPrefix(lower, "0o") || strings.HasPrefix(lower, "0b") {
+		n, err := strconv.ParseInt(lower, 0, 64)
+		return float64(n), err == nil
+	}
+	n, err := strconv.ParseFloat(text, 64)
+	return n, err == nil
+}
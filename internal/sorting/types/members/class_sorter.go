@@ -0,0 +1,295 @@
+package members
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/common"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/interfaces"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// ClassSorter handles sorting of the members of a class body
+type ClassSorter struct {
+	node         *sitter.Node
+	magicComment *sitter.Node
+	magicIndex   int
+	endIndex     int
+}
+
+// NewClassSorter creates a new class member sorter
+func NewClassSorter(classBody, magicComment *sitter.Node, magicIndex int) *ClassSorter {
+	return &ClassSorter{
+		node:         classBody,
+		magicComment: magicComment,
+		magicIndex:   magicIndex,
+		endIndex:     int(classBody.ChildCount()),
+	}
+}
+
+// NewClassSection creates a sorter for the members between a start-sort marker
+// and the end-sort marker at endIndex
+func NewClassSection(classBody, startMarker *sitter.Node, startIndex, endIndex int) *ClassSorter {
+	return &ClassSorter{
+		node:         classBody,
+		magicComment: startMarker,
+		magicIndex:   startIndex,
+		endIndex:     endIndex,
+	}
+}
+
+// IsMemberNode reports whether a child of a class body is part of a member:
+// a definition, an overload signature or a decorator
+func IsMemberNode(node *sitter.Node) bool {
+	return node.IsNamed() && node.Type() != "comment"
+}
+
+// unit is a child of a class body with the semicolon and inline comment
+// following it and the comments leading it
+type unit struct {
+	node      *sitter.Node
+	semicolon *sitter.Node
+	after     *sitter.Node
+	leading   []*sitter.Node
+}
+
+// end returns the last node of the unit before its inline comment
+func (u unit) end() *sitter.Node {
+	if u.semicolon != nil {
+		return u.semicolon
+	}
+	return u.node
+}
+
+// Extract finds and extracts sortable members from the class body. Decorators
+// written before a member and the overload signatures of a method belong to
+// the member they precede.
+func (c *ClassSorter) Extract(node *sitter.Node, content []byte) ([]interfaces.SortableItem, error) {
+	var members []interfaces.SortableItem
+	var group []unit
+
+	// closeGroup turns the units of the group up to and including the one
+	// at last into a member, keeping the rest for the next member
+	closeGroup := func(last int) {
+		member := NewMember(group[last].node, group[0].node, content)
+		member.LastNode = group[last].end()
+		member.AfterNode = group[last].after
+		member.BeforeNodes = group[0].leading
+		for _, u := range group[:last+1] {
+			if common.HasDeprecatedAnnotation(u.leading, content) || isDeprecatedDecorator(u.node, content) {
+				member.isDeprecated = true
+			}
+		}
+		for _, decorator := range childrenOfType(member.Node, "decorator") {
+			if isDeprecatedDecorator(decorator, content) {
+				member.isDeprecated = true
+			}
+		}
+		members = append(members, member)
+		group = group[last+1:]
+	}
+
+	// lastSignature returns the index of the group's last overload signature
+	lastSignature := func() int {
+		for i := len(group) - 1; i >= 0; i-- {
+			if group[i].node.Type() == "method_signature" {
+				return i
+			}
+		}
+		return -1
+	}
+
+	for _, u := range c.units(content) {
+		// Overload signatures of another method end the pending member
+		if last := lastSignature(); last >= 0 && u.node.Type() != "decorator" &&
+			memberName(group[last].node, content) != memberName(u.node, content) {
+			closeGroup(last)
+		}
+
+		group = append(group, u)
+		switch u.node.Type() {
+		case "decorator", "method_signature":
+			// Wait for the member they belong to
+			continue
+		}
+		closeGroup(len(group) - 1)
+	}
+
+	// Signatures without an implementation, as in declared classes
+	if last := lastSignature(); last >= 0 {
+		closeGroup(last)
+	}
+
+	return members, nil
+}
+
+// units splits the class body's children between the magic comment and the
+// end index into units
+func (c *ClassSorter) units(content []byte) []unit {
+	var units []unit
+	var pendingComments []*sitter.Node
+
+	// Start after magic comment
+	startIdx := c.magicIndex + 1
+
+	for i := startIdx; i < c.endIndex; i++ {
+		child := c.node.Child(i)
+
+		switch {
+		case child.Type() == "comment":
+			// Accumulate comments, such as JSDoc
+			pendingComments = append(pendingComments, child)
+
+		case IsMemberNode(child):
+			u := unit{node: child, leading: pendingComments}
+
+			// Check if followed by semicolon and/or inline comment
+			j := i + 1
+			continueLoop := true
+			lastNode := child // Track the last node for line comparison
+			for j < c.endIndex && continueLoop {
+				next := c.node.Child(j)
+				switch {
+				case next.Type() == ";" && u.semicolon == nil:
+					u.semicolon = next
+					lastNode = next
+					j++
+				case next.Type() == "comment" && next.StartPoint().Row == lastNode.EndPoint().Row && u.node.Type() != "decorator":
+					u.after = next
+					j++
+					continueLoop = false
+				default:
+					continueLoop = false
+				}
+			}
+			i = j - 1 // Update loop counter to skip processed nodes
+
+			units = append(units, u)
+			pendingComments = nil // Reset comments
+		}
+	}
+
+	return units
+}
+
+// isDeprecatedDecorator reports whether a node is a @deprecated decorator
+func isDeprecatedDecorator(node *sitter.Node, content []byte) bool {
+	return node.Type() == "decorator" && strings.HasPrefix(string(content[node.StartByte():node.EndByte()]), "@deprecated")
+}
+
+// childrenOfType returns a node's children of the given type
+func childrenOfType(node *sitter.Node, nodeType string) []*sitter.Node {
+	var children []*sitter.Node
+	for i := 0; i < int(node.ChildCount()); i++ {
+		if child := node.Child(i); child.Type() == nodeType {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+// Sort applies the strategy to sort the members, considering the
+// deprecated-at-end flag. Members with the same name, such as a getter and
+// its setter, keep their order.
+func (c *ClassSorter) Sort(items []interfaces.SortableItem, strategy interfaces.SortStrategy, deprecatedAtEnd bool, content []byte) ([]interfaces.SortableItem, error) {
+	if len(items) <= 1 {
+		return items, nil
+	}
+
+	// Extract sort keys for each member
+	for _, item := range items {
+		member := item.(*Member)
+		sortKey, err := item.GetSortKey(strategy, content)
+		if err != nil {
+			// For missing comments, mark with special prefix to sort last
+			member.SortKey = "\uffff" + member.Name
+		} else {
+			member.SortKey = sortKey
+		}
+	}
+
+	// Make a copy for sorting
+	sorted := make([]interfaces.SortableItem, len(items))
+	copy(sorted, items)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		memberI := sorted[i].(*Member)
+		memberJ := sorted[j].(*Member)
+		// If one is deprecated and the other isn't, put non-deprecated first
+		if deprecatedAtEnd && memberI.isDeprecated != memberJ.isDeprecated {
+			return !memberI.isDeprecated
+		}
+		return memberI.SortKey < memberJ.SortKey
+	})
+
+	return sorted, nil
+}
+
+// CheckIfSorted determines if members are already sorted according to strategy
+func (c *ClassSorter) CheckIfSorted(items []interfaces.SortableItem, strategy interfaces.SortStrategy, deprecatedAtEnd bool, content []byte) bool {
+	if len(items) <= 1 {
+		return true
+	}
+
+	sorted, err := c.Sort(items, strategy, deprecatedAtEnd, content)
+	if err != nil {
+		return false
+	}
+
+	// The sort is stable, so sorted members keep their positions
+	for i := range items {
+		if items[i] != sorted[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NeedsFormatting determines if the spacing between members differs from
+// what the with-new-line option asks for
+func (c *ClassSorter) NeedsFormatting(items []interfaces.SortableItem, withNewLine bool, content []byte) bool {
+	for i := 0; i < len(items)-1; i++ {
+		member := items[i].(*Member)
+		nextMember := items[i+1].(*Member)
+
+		// Find the end of current member (including semicolon and inline comment)
+		endNode := member.LastNode
+		if member.AfterNode != nil {
+			endNode = member.AfterNode
+		}
+
+		// Handle comments before the next member
+		startNode := nextMember.FirstNode
+		if len(nextMember.BeforeNodes) > 0 {
+			startNode = nextMember.BeforeNodes[0]
+		}
+
+		if common.SpacingDiffers(endNode.EndByte(), startNode.StartByte(), withNewLine, content) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetMagicCommentIndex returns the index of the magic comment
+func (c *ClassSorter) GetMagicCommentIndex() int {
+	return c.magicIndex
+}
+
+// GetEndIndex returns the index of the child ending the members
+func (c *ClassSorter) GetEndIndex() int {
+	return c.endIndex
+}
+
+// GetMagicComment returns the magic comment node
+func (c *ClassSorter) GetMagicComment() *sitter.Node {
+	return c.magicComment
+}
+
+// GetNode returns the underlying AST node
+func (c *ClassSorter) GetNode() *sitter.Node {
+	return c.node
+}
@@ -0,0 +1,120 @@
+package members
+
+import (
+	"strings"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/common"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/interfaces"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// Ranks of the member kinds used when grouping by kind
+const (
+	rankStaticField = iota
+	rankInstanceField
+	rankConstructor
+	rankMethod
+)
+
+// Member represents a class member that can be sorted, along with the
+// decorators and overload signatures that belong to it
+type Member struct {
+	Node         *sitter.Node   // The member's definition; the implementation of overloads
+	FirstNode    *sitter.Node   // First node of the member: a decorator, an overload signature or Node
+	LastNode     *sitter.Node   // Last node of the member: its semicolon or Node
+	Name         string         // Member name
+	SortKey      string         // The key used for sorting (may be different from name when using sort-by-comment)
+	BeforeNodes  []*sitter.Node // Comments before this member, such as its JSDoc
+	AfterNode    *sitter.Node   // Inline comment after member
+	rank         int
+	isDeprecated bool
+}
+
+// GetSortKey returns the key for sorting based on the strategy
+func (m *Member) GetSortKey(strategy interfaces.SortStrategy, content []byte) (string, error) {
+	// Only comment sorting and grouping look past the member name; the key
+	// option applies to array elements
+	name := strategy.GetName()
+	if name != "comment-content" && !strings.HasPrefix(name, "group-by-kind") {
+		return m.Name, nil
+	}
+
+	return strategy.ExtractKey(m, content)
+}
+
+// KindRank returns the position of the member's kind when grouping by kind:
+// static fields, instance fields, the constructor, then methods
+func (m *Member) KindRank() int {
+	return m.rank
+}
+
+// IsDeprecated returns true if this member has @deprecated annotation
+func (m *Member) IsDeprecated() bool {
+	return m.isDeprecated
+}
+
+// GetNode returns the underlying AST node
+func (m *Member) GetNode() *sitter.Node {
+	return m.Node
+}
+
+// GetBeforeComments returns comments that appear before this member
+func (m *Member) GetBeforeComments() []*sitter.Node {
+	return m.BeforeNodes
+}
+
+// GetAfterComment returns inline comment that appears after this member
+func (m *Member) GetAfterComment() *sitter.Node {
+	return m.AfterNode
+}
+
+// NewMember creates a new Member from its definition and the node it starts at
+func NewMember(node, firstNode *sitter.Node, content []byte) *Member {
+	return &Member{
+		Node:      node,
+		FirstNode: firstNode,
+		LastNode:  node,
+		Name:      memberName(node, content),
+		rank:      kindRank(node, content),
+	}
+}
+
+// memberName extracts the name a member is sorted by. Index signatures have
+// no name and are sorted by their text.
+func memberName(node *sitter.Node, content []byte) string {
+	if node.Type() != "index_signature" {
+		if name := node.ChildByFieldName("name"); name != nil {
+			return common.ExtractKeyFromNode(name, content)
+		}
+	}
+	return strings.TrimSpace(string(content[node.StartByte():node.EndByte()]))
+}
+
+// kindRank returns the group a member belongs to when grouping by kind
+func kindRank(node *sitter.Node, content []byte) int {
+	switch node.Type() {
+	case "public_field_definition":
+		if hasChildOfType(node, "static") {
+			return rankStaticField
+		}
+		return rankInstanceField
+	case "index_signature":
+		return rankInstanceField
+	}
+
+	if memberName(node, content) == "constructor" {
+		return rankConstructor
+	}
+	return rankMethod
+}
+
+// hasChildOfType reports whether one of a node's children has the given type
+func hasChildOfType(node *sitter.Node, nodeType string) bool {
+	for i := 0; i < int(node.ChildCount()); i++ {
+		if node.Child(i).Type() == nodeType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package members
+
+import (
+	"fmt"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/common"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// fieldKey identifies a field; static and instance fields have separate
+// namespaces
+type fieldKey struct {
+	name     string
+	isStatic bool
+}
+
+// CheckOrder returns an error describing why sorting would change what the
+// class does, or nil when the members can be reordered safely. Static blocks
+// and field initializers run in the order they are written, so a field whose
+// initializer uses another sorted field must stay after it. Empty members,
+// stray semicolons, would be lost when the body is rebuilt.
+func (c *ClassSorter) CheckOrder(content []byte) error {
+	units := c.units(content)
+
+	consumed := make(map[uint32]bool)
+	for _, u := range units {
+		if u.semicolon != nil {
+			consumed[u.semicolon.StartByte()] = true
+		}
+	}
+	for i := c.magicIndex + 1; i < c.endIndex; i++ {
+		child := c.node.Child(i)
+		switch {
+		case child.Type() == "class_static_block":
+			return fmt.Errorf("static block at line %d runs where it is written, and sorting could change the fields it sees", child.StartPoint().Row+1)
+		case child.Type() == ";" && !consumed[child.StartByte()]:
+			return fmt.Errorf("empty member at line %d would be dropped by sorting", child.StartPoint().Row+1)
+		}
+	}
+
+	fields := make(map[fieldKey]*sitter.Node)
+	for _, u := range units {
+		if u.node.Type() == "public_field_definition" {
+			fields[fieldKey{memberName(u.node, content), hasChildOfType(u.node, "static")}] = u.node
+		}
+	}
+
+	className := ""
+	if name := c.node.Parent().ChildByFieldName("name"); name != nil {
+		className = name.Content(content)
+	}
+
+	for _, u := range units {
+		value := u.node.ChildByFieldName("value")
+		if u.node.Type() != "public_field_definition" || value == nil {
+			continue
+		}
+		name := memberName(u.node, content)
+		isStatic := hasChildOfType(u.node, "static")
+
+		var err error
+		common.WalkEager(value, func(n *sitter.Node) bool {
+			if err != nil {
+				return false
+			}
+			if ref, ok := fieldReference(n, className, isStatic, content); ok {
+				if owner, found := fields[fieldKey{ref, isStatic}]; found && owner != u.node {
+					err = fmt.Errorf("field %q uses field %q, which sorting may move after it", name, ref)
+				}
+			}
+			return true
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fieldReference returns the field a member expression reads: this.x, or in
+// static initializers, where this is the class, also ClassName.x
+func fieldReference(n *sitter.Node, className string, isStatic bool, content []byte) (string, bool) {
+	if n.Type() != "member_expression" {
+		return "", false
+	}
+	object := n.ChildByFieldName("object")
+	property := n.ChildByFieldName("property")
+	if object == nil || property == nil {
+		return "", false
+	}
+	switch {
+	case object.Type() == "this":
+	case isStatic && className != "" && object.Type() == "identifier" && object.Content(content) == className:
+	default:
+		return "", false
+	}
+	return property.Content(content), true
+}
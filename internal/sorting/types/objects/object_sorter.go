@@ -15,6 +15,7 @@ type ObjectSorter struct {
 	node         *sitter.Node
 	magicComment *sitter.Node
 	magicIndex   int
+	endIndex     int
 }
 
 // NewObjectSorter creates a new object sorter
@@ -23,6 +24,18 @@ func NewObjectSorter(objectNode, magicComment *sitter.Node, magicIndex int) *Obj
 		node:         objectNode,
 		magicComment: magicComment,
 		magicIndex:   magicIndex,
+		endIndex:     int(objectNode.ChildCount()),
+	}
+}
+
+// NewObjectSection creates a sorter for the properties between a start-sort marker
+// and the end-sort marker at endIndex
+func NewObjectSection(objectNode, startMarker *sitter.Node, startIndex, endIndex int) *ObjectSorter {
+	return &ObjectSorter{
+		node:         objectNode,
+		magicComment: startMarker,
+		magicIndex:   startIndex,
+		endIndex:     endIndex,
 	}
 }
 
@@ -34,7 +47,7 @@ func (o *ObjectSorter) Extract(node *sitter.Node, content []byte) ([]interfaces.
 	// Start after magic comment
 	startIdx := o.magicIndex + 1
 
-	for i := startIdx; i < int(o.node.ChildCount()); i++ {
+	for i := startIdx; i < o.endIndex; i++ {
 		child := o.node.Child(i)
 
 		switch child.Type() {
@@ -52,7 +65,7 @@ func (o *ObjectSorter) Extract(node *sitter.Node, content []byte) ([]interfaces.
 			// Check if followed by comma and/or inline comment
 			j := i + 1
 			continueLoop := true
-			for j < int(o.node.ChildCount()) && continueLoop {
+			for j < o.endIndex && continueLoop {
 				next := o.node.Child(j)
 				switch next.Type() {
 				case ",":
@@ -169,13 +182,51 @@ func (o *ObjectSorter) CheckIfSorted(items []interfaces.SortableItem, strategy i
 	return true
 }
 
+// NeedsFormatting determines if the spacing between properties differs from
+// what the with-new-line option asks for
+func (o *ObjectSorter) NeedsFormatting(items []interfaces.SortableItem, withNewLine bool, content []byte) bool {
+	for i := 0; i < len(items)-1; i++ {
+		prop := items[i].(*Property)
+		nextProp := items[i+1].(*Property)
+
+		// Find the end of current property (including comma and inline comment)
+		endNode := prop.PairNode
+		if prop.AfterNode != nil {
+			endNode = prop.AfterNode
+		} else if prop.CommaNode != nil {
+			endNode = prop.CommaNode
+		}
+
+		// Handle comments before the next property
+		startNode := nextProp.PairNode
+		if len(nextProp.BeforeNodes) > 0 {
+			startNode = nextProp.BeforeNodes[0]
+		}
+
+		if common.SpacingDiffers(endNode.EndByte(), startNode.StartByte(), withNewLine, content) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // GetMagicCommentIndex returns the index of the magic comment
 func (o *ObjectSorter) GetMagicCommentIndex() int {
 	return o.magicIndex
 }
 
+// GetEndIndex returns the index of the child ending the properties
+func (o *ObjectSorter) GetEndIndex() int {
+	return o.endIndex
+}
+
 // GetNode returns the underlying AST node
 func (o *ObjectSorter) GetNode() *sitter.Node {
 	return o.node
 }
 
+// GetMagicComment returns the magic comment node
+func (o *ObjectSorter) GetMagicComment() *sitter.Node {
+	return o.magicComment
+}
@@ -23,12 +23,12 @@ type Property struct {
 
 // GetSortKey returns the key for sorting based on the strategy
 func (p *Property) GetSortKey(strategy interfaces.SortStrategy, content []byte) (string, error) {
-	// For property name strategy, return the property key
-	if strategy.GetName() == "property-name" {
+	// Only comment sorting looks past the property name; the key option
+	// applies to array elements
+	if strategy.GetName() != "comment-content" {
 		return p.Key, nil
 	}
 	
-	// For other strategies, delegate to the strategy
 	return strategy.ExtractKey(p, content)
 }
 
@@ -0,0 +1,146 @@
+package statements
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/common"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// CheckOrder returns an error describing why sorting would change what the
+// statements do, or nil when they can be reordered safely. Statements that
+// declare nothing, such as calls and side-effect imports, run in the order
+// they are written, and a declaration whose initializer uses another sorted
+// declaration must stay after it.
+func (s *StatementSorter) CheckOrder(content []byte) error {
+	var sorted []*sitter.Node
+	for i := s.magicIndex + 1; i < s.endIndex; i++ {
+		if child := s.node.Child(i); IsStatement(child) {
+			sorted = append(sorted, child)
+		}
+	}
+
+	for _, statement := range sorted {
+		if declaredName(statement, content) == "" {
+			return fmt.Errorf("statement %q declares nothing and sorting could change when it runs", firstLine(statement, content))
+		}
+	}
+
+	// Declarations in ambient contexts have no initializers that run
+	if isAmbient(s.node) {
+		return nil
+	}
+
+	owners := make(map[string]*sitter.Node)
+	for _, statement := range sorted {
+		for _, name := range runtimeBindings(statement, content) {
+			owners[name] = statement
+		}
+	}
+	for _, statement := range sorted {
+		for _, ref := range eagerReferences(statement, content) {
+			if owner, ok := owners[ref]; ok && owner != statement {
+				return fmt.Errorf("%q uses %q, which sorting may move after it", declaredName(statement, content), ref)
+			}
+		}
+	}
+
+	return nil
+}
+
+// isAmbient reports whether a statement list is the body of a declare
+// namespace or declare module block
+func isAmbient(node *sitter.Node) bool {
+	for n := node.Parent(); n != nil; n = n.Parent() {
+		if n.Type() == "ambient_declaration" {
+			return true
+		}
+	}
+	return false
+}
+
+// runtimeBindings returns the names a statement binds when it runs and that
+// cannot be used before it: everything but hoisted function declarations and
+// type-only declarations
+func runtimeBindings(node *sitter.Node, content []byte) []string {
+	if node.Type() == "export_statement" {
+		declaration := node.ChildByFieldName("declaration")
+		if declaration == nil {
+			return nil
+		}
+		node = declaration
+	}
+
+	switch node.Type() {
+	case "function_declaration", "generator_function_declaration", "function_signature",
+		"interface_declaration", "type_alias_declaration", "ambient_declaration":
+		return nil
+	case "lexical_declaration", "variable_declaration":
+		var names []string
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			declarator := node.NamedChild(i)
+			if declarator.Type() != "variable_declarator" {
+				continue
+			}
+			if name := declarator.ChildByFieldName("name"); name != nil {
+				names = append(names, patternNames(name, content)...)
+			}
+		}
+		return names
+	}
+
+	if name := declaredName(node, content); name != "" {
+		return []string{name}
+	}
+	return nil
+}
+
+// patternNames returns the names a binding pattern declares
+func patternNames(pattern *sitter.Node, content []byte) []string {
+	switch pattern.Type() {
+	case "identifier", "shorthand_property_identifier_pattern":
+		return []string{pattern.Content(content)}
+	case "object_assignment_pattern", "assignment_pattern":
+		if left := pattern.ChildByFieldName("left"); left != nil {
+			return patternNames(left, content)
+		}
+		return nil
+	case "pair_pattern":
+		if value := pattern.ChildByFieldName("value"); value != nil {
+			return patternNames(value, content)
+		}
+		return nil
+	}
+
+	var names []string
+	for i := 0; i < int(pattern.NamedChildCount()); i++ {
+		names = append(names, patternNames(pattern.NamedChild(i), content)...)
+	}
+	return names
+}
+
+// eagerReferences returns the names a statement uses while it runs, leaving
+// out the ones only used inside functions and methods that are not called
+// right away, and instance field initializers
+func eagerReferences(statement *sitter.Node, content []byte) []string {
+	var refs []string
+	common.WalkEager(statement, func(n *sitter.Node) bool {
+		if n.Type() == "identifier" || n.Type() == "shorthand_property_identifier" {
+			refs = append(refs, n.Content(content))
+			return false
+		}
+		return true
+	})
+	return refs
+}
+
+// firstLine returns the first line of a statement's text
+func firstLine(node *sitter.Node, content []byte) string {
+	text := node.Content(content)
+	if i := strings.IndexByte(text, '\n'); i >= 0 {
+		text = text[:i]
+	}
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,102 @@
+package statements
+
+import (
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/common"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/interfaces"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// Statement represents a statement of a statement list that can be sorted
+type Statement struct {
+	Node         *sitter.Node
+	Name         string         // Declared name, empty when the statement declares nothing
+	Exported     bool           // Whether the statement is an export
+	SortKey      string         // The key used for sorting (may be different from name when using sort-by-comment)
+	BeforeNodes  []*sitter.Node // Comments before this statement, such as its JSDoc
+	AfterNode    *sitter.Node   // Inline comment after statement
+	isDeprecated bool
+}
+
+// GetSortKey returns the key for sorting based on the strategy
+func (s *Statement) GetSortKey(strategy interfaces.SortStrategy, content []byte) (string, error) {
+	// Only comment sorting looks past the declared name; the key option
+	// applies to array elements
+	if strategy.GetName() != "comment-content" {
+		return s.Name, nil
+	}
+
+	return strategy.ExtractKey(s, content)
+}
+
+// IsDeprecated returns true if this statement has @deprecated annotation
+func (s *Statement) IsDeprecated() bool {
+	return s.isDeprecated
+}
+
+// GetNode returns the underlying AST node
+func (s *Statement) GetNode() *sitter.Node {
+	return s.Node
+}
+
+// GetBeforeComments returns comments that appear before this statement
+func (s *Statement) GetBeforeComments() []*sitter.Node {
+	return s.BeforeNodes
+}
+
+// GetAfterComment returns inline comment that appears after this statement
+func (s *Statement) GetAfterComment() *sitter.Node {
+	return s.AfterNode
+}
+
+// NewStatement creates a new Statement from a statement node
+func NewStatement(node *sitter.Node, content []byte) *Statement {
+	return &Statement{
+		Node:     node,
+		Name:     declaredName(node, content),
+		Exported: node.Type() == "export_statement",
+	}
+}
+
+// declaredName extracts the name a statement declares, or returns "" for
+// statements that declare nothing, such as calls and re-exports
+func declaredName(node *sitter.Node, content []byte) string {
+	switch node.Type() {
+	case "export_statement":
+		if declaration := node.ChildByFieldName("declaration"); declaration != nil {
+			return declaredName(declaration, content)
+		}
+		for i := 0; i < int(node.ChildCount()); i++ {
+			if node.Child(i).Type() == "default" {
+				return "default"
+			}
+		}
+	case "ambient_declaration":
+		// declare const x, declare function f() and declare namespace X
+		if inner := node.NamedChild(0); inner != nil && inner.Type() != "statement_block" {
+			return declaredName(inner, content)
+		}
+	case "expression_statement":
+		// Namespaces parse as expression statements
+		if inner := node.NamedChild(0); inner != nil && inner.Type() == "internal_module" {
+			return declaredName(inner, content)
+		}
+	case "lexical_declaration", "variable_declaration":
+		// Use the first declarator's name
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			child := node.NamedChild(i)
+			if child.Type() != "variable_declarator" {
+				continue
+			}
+			if name := child.ChildByFieldName("name"); name != nil {
+				return string(content[name.StartByte():name.EndByte()])
+			}
+		}
+	default:
+		if name := node.ChildByFieldName("name"); name != nil {
+			return common.TrimQuotes(string(content[name.StartByte():name.EndByte()]))
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,211 @@
+package statements
+
+import (
+	"sort"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/common"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/interfaces"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// StatementSorter handles sorting of the statements of a file, a namespace
+// or a module declaration
+type StatementSorter struct {
+	node         *sitter.Node
+	magicComment *sitter.Node
+	magicIndex   int
+	endIndex     int
+}
+
+// NewStatementSorter creates a new statement sorter
+func NewStatementSorter(listNode, magicComment *sitter.Node, magicIndex int) *StatementSorter {
+	return &StatementSorter{
+		node:         listNode,
+		magicComment: magicComment,
+		magicIndex:   magicIndex,
+		endIndex:     int(listNode.ChildCount()),
+	}
+}
+
+// NewStatementSection creates a sorter for the statements between a start-sort marker
+// and the end-sort marker at endIndex
+func NewStatementSection(listNode, startMarker *sitter.Node, startIndex, endIndex int) *StatementSorter {
+	return &StatementSorter{
+		node:         listNode,
+		magicComment: startMarker,
+		magicIndex:   startIndex,
+		endIndex:     endIndex,
+	}
+}
+
+// IsStatementList reports whether a node holds statements that can be
+// sorted: a file, or the body of a namespace or module declaration.
+// Function bodies are not sorted, since their statements run in order.
+func IsStatementList(node *sitter.Node) bool {
+	switch node.Type() {
+	case "program":
+		return true
+	case "statement_block":
+		parent := node.Parent()
+		if parent == nil {
+			return false
+		}
+		switch parent.Type() {
+		case "internal_module", "module", "ambient_declaration":
+			return true
+		}
+	}
+	return false
+}
+
+// IsStatement reports whether a child of a statement list is a statement
+func IsStatement(node *sitter.Node) bool {
+	return node.IsNamed() && node.Type() != "comment"
+}
+
+// Extract finds and extracts sortable statements from the statement list
+func (s *StatementSorter) Extract(node *sitter.Node, content []byte) ([]interfaces.SortableItem, error) {
+	var statements []interfaces.SortableItem
+	var pendingComments []*sitter.Node
+
+	// Start after magic comment
+	startIdx := s.magicIndex + 1
+
+	for i := startIdx; i < s.endIndex; i++ {
+		child := s.node.Child(i)
+
+		switch {
+		case child.Type() == "comment":
+			// Accumulate comments, such as JSDoc
+			pendingComments = append(pendingComments, child)
+
+		case IsStatement(child):
+			statement := NewStatement(child, content)
+			statement.BeforeNodes = pendingComments
+			statement.isDeprecated = common.HasDeprecatedAnnotation(pendingComments, content)
+
+			// Check if followed by an inline comment
+			if i+1 < s.endIndex {
+				next := s.node.Child(i + 1)
+				if next.Type() == "comment" && next.StartPoint().Row == child.EndPoint().Row {
+					statement.AfterNode = next
+					i++
+				}
+			}
+
+			statements = append(statements, statement)
+			pendingComments = nil // Reset comments
+		}
+	}
+
+	return statements, nil
+}
+
+// Sort applies the strategy to sort the statements, exports first, considering
+// the deprecated-at-end flag. Statements with the same name, such as function
+// overloads, keep their order.
+func (s *StatementSorter) Sort(items []interfaces.SortableItem, strategy interfaces.SortStrategy, deprecatedAtEnd bool, content []byte) ([]interfaces.SortableItem, error) {
+	if len(items) <= 1 {
+		return items, nil
+	}
+
+	// Extract sort keys for each statement
+	for _, item := range items {
+		statement := item.(*Statement)
+		sortKey, err := item.GetSortKey(strategy, content)
+		if err != nil {
+			// For missing comments, mark with special prefix to sort last
+			statement.SortKey = "\uffff" + statement.Name
+		} else {
+			statement.SortKey = sortKey
+		}
+	}
+
+	// Make a copy for sorting
+	sorted := make([]interfaces.SortableItem, len(items))
+	copy(sorted, items)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		statementI := sorted[i].(*Statement)
+		statementJ := sorted[j].(*Statement)
+		// If one is deprecated and the other isn't, put non-deprecated first
+		if deprecatedAtEnd && statementI.isDeprecated != statementJ.isDeprecated {
+			return !statementI.isDeprecated
+		}
+		if statementI.Exported != statementJ.Exported {
+			return statementI.Exported
+		}
+		return statementI.SortKey < statementJ.SortKey
+	})
+
+	return sorted, nil
+}
+
+// CheckIfSorted determines if statements are already sorted according to strategy
+func (s *StatementSorter) CheckIfSorted(items []interfaces.SortableItem, strategy interfaces.SortStrategy, deprecatedAtEnd bool, content []byte) bool {
+	if len(items) <= 1 {
+		return true
+	}
+
+	sorted, err := s.Sort(items, strategy, deprecatedAtEnd, content)
+	if err != nil {
+		return false
+	}
+
+	// The sort is stable, so sorted statements keep their positions
+	for i := range items {
+		if items[i] != sorted[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NeedsFormatting determines if the spacing between statements differs from
+// what the with-new-line option asks for
+func (s *StatementSorter) NeedsFormatting(items []interfaces.SortableItem, withNewLine bool, content []byte) bool {
+	for i := 0; i < len(items)-1; i++ {
+		statement := items[i].(*Statement)
+		nextStatement := items[i+1].(*Statement)
+
+		// Find the end of current statement (including inline comment)
+		endNode := statement.Node
+		if statement.AfterNode != nil {
+			endNode = statement.AfterNode
+		}
+
+		// Handle comments before the next statement
+		startNode := nextStatement.Node
+		if len(nextStatement.BeforeNodes) > 0 {
+			startNode = nextStatement.BeforeNodes[0]
+		}
+
+		if common.SpacingDiffers(endNode.EndByte(), startNode.StartByte(), withNewLine, content) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetMagicCommentIndex returns the index of the magic comment
+func (s *StatementSorter) GetMagicCommentIndex() int {
+	return s.magicIndex
+}
+
+// GetEndIndex returns the index of the child ending the statements
+func (s *StatementSorter) GetEndIndex() int {
+	return s.endIndex
+}
+
+// GetMagicComment returns the magic comment node
+func (s *StatementSorter) GetMagicComment() *sitter.Node {
+	return s.magicComment
+}
+
+// GetNode returns the underlying AST node
+func (s *StatementSorter) GetNode() *sitter.Node {
+	return s.node
+}
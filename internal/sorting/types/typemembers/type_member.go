@@ -0,0 +1,80 @@
+package typemembers
+
+import (
+	"strings"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/common"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/interfaces"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// TypeMember represents a member of an interface or type literal that can
+// be sorted: a property, method, call, construct or index signature
+type TypeMember struct {
+	Node          *sitter.Node
+	Name          string         // Member name; empty for call signatures
+	SortKey       string         // The key used for sorting (may be different from name when using sort-by-comment)
+	BeforeNodes   []*sitter.Node // Comments before this member
+	AfterNode     *sitter.Node   // Inline comment after member
+	SeparatorNode *sitter.Node   // The ; or , following the member, if any
+	isDeprecated  bool
+}
+
+// GetSortKey returns the key for sorting based on the strategy
+func (m *TypeMember) GetSortKey(strategy interfaces.SortStrategy, content []byte) (string, error) {
+	// Only comment sorting looks past the member name; the key option
+	// applies to array elements
+	if strategy.GetName() != "comment-content" {
+		return m.Name, nil
+	}
+
+	return strategy.ExtractKey(m, content)
+}
+
+// IsDeprecated returns true if this member has @deprecated annotation
+func (m *TypeMember) IsDeprecated() bool {
+	return m.isDeprecated
+}
+
+// GetNode returns the underlying AST node
+func (m *TypeMember) GetNode() *sitter.Node {
+	return m.Node
+}
+
+// GetBeforeComments returns comments that appear before this member
+func (m *TypeMember) GetBeforeComments() []*sitter.Node {
+	return m.BeforeNodes
+}
+
+// GetAfterComment returns inline comment that appears after this member
+func (m *TypeMember) GetAfterComment() *sitter.Node {
+	return m.AfterNode
+}
+
+// NewTypeMember creates a new TypeMember from a signature node
+func NewTypeMember(node *sitter.Node, content []byte) *TypeMember {
+	return &TypeMember{
+		Node: node,
+		Name: memberName(node, content),
+	}
+}
+
+// memberName extracts the name a member is sorted by. Call signatures share
+// an empty name and construct signatures the name "new", so that overloads
+// keep their order; index signatures are sorted by their text.
+func memberName(node *sitter.Node, content []byte) string {
+	switch node.Type() {
+	case "call_signature":
+		return ""
+	case "construct_signature":
+		return "new"
+	case "index_signature":
+		return strings.TrimSpace(string(content[node.StartByte():node.EndByte()]))
+	}
+
+	if name := node.ChildByFieldName("name"); name != nil {
+		return common.ExtractKeyFromNode(name, content)
+	}
+	return strings.TrimSpace(string(content[node.StartByte():node.EndByte()]))
+}
@@ -0,0 +1,222 @@
+package typemembers
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/common"
+	"github.com/evanrichards/tree-sorter-ts/internal/sorting/interfaces"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// TypeSorter handles sorting of the members of an interface body or a type
+// literal
+type TypeSorter struct {
+	node         *sitter.Node
+	magicComment *sitter.Node
+	magicIndex   int
+	endIndex     int
+}
+
+// NewTypeSorter creates a new type member sorter
+func NewTypeSorter(typeBody, magicComment *sitter.Node, magicIndex int) *TypeSorter {
+	return &TypeSorter{
+		node:         typeBody,
+		magicComment: magicComment,
+		magicIndex:   magicIndex,
+		endIndex:     int(typeBody.ChildCount()),
+	}
+}
+
+// NewTypeSection creates a sorter for the members between a start-sort marker
+// and the end-sort marker at endIndex
+func NewTypeSection(typeBody, startMarker *sitter.Node, startIndex, endIndex int) *TypeSorter {
+	return &TypeSorter{
+		node:         typeBody,
+		magicComment: startMarker,
+		magicIndex:   startIndex,
+		endIndex:     endIndex,
+	}
+}
+
+// IsTypeBody reports whether a node holds type members: an interface body
+// or a type literal
+func IsTypeBody(node *sitter.Node) bool {
+	return node.Type() == "interface_body" || node.Type() == "object_type"
+}
+
+// IsTypeMember reports whether a child of a type body is a member
+func IsTypeMember(node *sitter.Node) bool {
+	return node.IsNamed() && node.Type() != "comment"
+}
+
+// IsSeparator reports whether a child of a type body separates members
+func IsSeparator(node *sitter.Node) bool {
+	return node.Type() == ";" || node.Type() == ","
+}
+
+// Extract finds and extracts sortable members from the type body
+func (t *TypeSorter) Extract(node *sitter.Node, content []byte) ([]interfaces.SortableItem, error) {
+	var members []interfaces.SortableItem
+	var pendingComments []*sitter.Node
+
+	// Start after magic comment
+	startIdx := t.magicIndex + 1
+
+	for i := startIdx; i < t.endIndex; i++ {
+		child := t.node.Child(i)
+
+		switch {
+		case child.Type() == "comment":
+			// Accumulate comments
+			pendingComments = append(pendingComments, child)
+
+		case IsTypeMember(child):
+			member := NewTypeMember(child, content)
+			member.BeforeNodes = pendingComments
+			member.isDeprecated = common.HasDeprecatedAnnotation(pendingComments, content)
+
+			// Check if followed by separator and/or inline comment
+			j := i + 1
+			continueLoop := true
+			lastNode := child // Track the last node for line comparison
+			for j < t.endIndex && continueLoop {
+				next := t.node.Child(j)
+				switch {
+				case IsSeparator(next) && member.SeparatorNode == nil:
+					member.SeparatorNode = next
+					lastNode = next
+					j++
+				case next.Type() == "comment" && next.StartPoint().Row == lastNode.EndPoint().Row:
+					member.AfterNode = next
+					j++
+					continueLoop = false
+				default:
+					continueLoop = false
+				}
+			}
+			i = j - 1 // Update loop counter to skip processed nodes
+
+			// Also check inline comment for @deprecated
+			if !member.isDeprecated && member.AfterNode != nil {
+				text := string(content[member.AfterNode.StartByte():member.AfterNode.EndByte()])
+				if strings.Contains(text, "@deprecated") {
+					member.isDeprecated = true
+				}
+			}
+
+			members = append(members, member)
+			pendingComments = nil // Reset comments
+		}
+	}
+
+	return members, nil
+}
+
+// Sort applies the strategy to sort the members, considering the
+// deprecated-at-end flag. Members with the same name, such as overloads,
+// keep their order.
+func (t *TypeSorter) Sort(items []interfaces.SortableItem, strategy interfaces.SortStrategy, deprecatedAtEnd bool, content []byte) ([]interfaces.SortableItem, error) {
+	if len(items) <= 1 {
+		return items, nil
+	}
+
+	// Extract sort keys for each member
+	for _, item := range items {
+		member := item.(*TypeMember)
+		sortKey, err := item.GetSortKey(strategy, content)
+		if err != nil {
+			// For missing comments, mark with special prefix to sort last
+			member.SortKey = "\uffff" + member.Name
+		} else {
+			member.SortKey = sortKey
+		}
+	}
+
+	// Make a copy for sorting
+	sorted := make([]interfaces.SortableItem, len(items))
+	copy(sorted, items)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		memberI := sorted[i].(*TypeMember)
+		memberJ := sorted[j].(*TypeMember)
+		// If one is deprecated and the other isn't, put non-deprecated first
+		if deprecatedAtEnd && memberI.isDeprecated != memberJ.isDeprecated {
+			return !memberI.isDeprecated
+		}
+		return memberI.SortKey < memberJ.SortKey
+	})
+
+	return sorted, nil
+}
+
+// CheckIfSorted determines if members are already sorted according to strategy
+func (t *TypeSorter) CheckIfSorted(items []interfaces.SortableItem, strategy interfaces.SortStrategy, deprecatedAtEnd bool, content []byte) bool {
+	if len(items) <= 1 {
+		return true
+	}
+
+	sorted, err := t.Sort(items, strategy, deprecatedAtEnd, content)
+	if err != nil {
+		return false
+	}
+
+	// The sort is stable, so sorted members keep their positions
+	for i := range items {
+		if items[i] != sorted[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NeedsFormatting determines if the spacing between members differs from
+// what the with-new-line option asks for
+func (t *TypeSorter) NeedsFormatting(items []interfaces.SortableItem, withNewLine bool, content []byte) bool {
+	for i := 0; i < len(items)-1; i++ {
+		member := items[i].(*TypeMember)
+		nextMember := items[i+1].(*TypeMember)
+
+		// Find the end of current member (including separator and inline comment)
+		endNode := member.Node
+		if member.AfterNode != nil {
+			endNode = member.AfterNode
+		} else if member.SeparatorNode != nil {
+			endNode = member.SeparatorNode
+		}
+
+		// Handle comments before the next member
+		startNode := nextMember.Node
+		if len(nextMember.BeforeNodes) > 0 {
+			startNode = nextMember.BeforeNodes[0]
+		}
+
+		if common.SpacingDiffers(endNode.EndByte(), startNode.StartByte(), withNewLine, content) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetMagicCommentIndex returns the index of the magic comment
+func (t *TypeSorter) GetMagicCommentIndex() int {
+	return t.magicIndex
+}
+
+// GetEndIndex returns the index of the child ending the members
+func (t *TypeSorter) GetEndIndex() int {
+	return t.endIndex
+}
+
+// GetMagicComment returns the magic comment node
+func (t *TypeSorter) GetMagicComment() *sitter.Node {
+	return t.magicComment
+}
+
+// GetNode returns the underlying AST node
+func (t *TypeSorter) GetNode() *sitter.Node {
+	return t.node
+}
@@ -0,0 +1,24 @@
+// Package textutil measures text in UTF-16 code units, as editors count it
+package textutil
+
+import "unicode/utf8"
+
+// RuneUTF16Len returns the number of UTF-16 code units encoding r; invalid
+// UTF-8 decodes to U+FFFD, a single code unit
+func RuneUTF16Len(r rune) int {
+	if r >= 0x10000 && r <= utf8.MaxRune {
+		return 2
+	}
+	return 1
+}
+
+// UTF16Len returns the number of UTF-16 code units encoding text
+func UTF16Len(text []byte) int {
+	n := 0
+	for len(text) > 0 {
+		r, size := utf8.DecodeRune(text)
+		text = text[size:]
+		n += RuneUTF16Len(r)
+	}
+	return n
+}
@@ -0,0 +1,22 @@
+package textutil
+
+import "testing"
+
+func TestUTF16Len(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{text: "", want: 0},
+		{text: "abc", want: 3},
+		{text: "é", want: 1},
+		{text: "😀", want: 2},
+		{text: "a😀b", want: 4},
+		{text: "\xff", want: 1},
+	}
+	for _, tt := range tests {
+		if got := UTF16Len([]byte(tt.text)); got != tt.want {
+			t.Errorf("UTF16Len(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DefaultInterval is the polling interval used when Options.Interval is zero
+const DefaultInterval = 500 * time.Millisecond
+
+// fileState is what the poller compares between scans
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// poller detects changes by scanning the watched directories periodically
+type poller struct {
+	roots  []Root
+	opts   Options
+	events chan string
+	errors chan error
+	done   chan struct{}
+	wg     sync.WaitGroup
+	once   sync.Once
+	files  map[string]fileState
+}
+
+// NewPoller watches roots by scanning them every Options.Interval. It works
+// everywhere, including network file systems without change notifications.
+func NewPoller(roots []Root, opts Options) (Watcher, error) {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+
+	p := &poller{
+		roots:  roots,
+		opts:   opts,
+		events: make(chan string),
+		errors: make(chan error),
+		done:   make(chan struct{}),
+	}
+
+	// The first scan is the baseline; only later differences are reported
+	files, err := p.scan()
+	if err != nil {
+		return nil, err
+	}
+	p.files = files
+
+	p.wg.Add(1)
+	go p.run()
+	return p, nil
+}
+
+func (p *poller) Events() <-chan string { return p.events }
+func (p *poller) Errors() <-chan error  { return p.errors }
+
+func (p *poller) Close() error {
+	p.once.Do(func() {
+		close(p.done)
+		p.wg.Wait()
+		close(p.events)
+		close(p.errors)
+	})
+	return nil
+}
+
+func (p *poller) run() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+		}
+
+		files, err := p.scan()
+		if err != nil {
+			if !p.sendError(err) {
+				return
+			}
+			continue
+		}
+
+		for path, state := range files {
+			if old, ok := p.files[path]; ok && old == state {
+				continue
+			}
+			select {
+			case p.events <- path:
+			case <-p.done:
+				return
+			}
+		}
+		p.files = files
+	}
+}
+
+func (p *poller) sendError(err error) bool {
+	select {
+	case p.errors <- err:
+		return true
+	case <-p.done:
+		return false
+	}
+}
+
+// scan records the state of every file directly inside the watched directories
+func (p *poller) scan() (map[string]fileState, error) {
+	files := make(map[string]fileState)
+	for _, root := range p.roots {
+		err := walkDirs(root, p.opts.SkipDir, func(dir string) error {
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			for _, entry := range entries {
+				if entry.IsDir() {
+					continue
+				}
+				info, err := entry.Info()
+				if err != nil {
+					continue
+				}
+				files[filepath.Join(dir, entry.Name())] = fileState{modTime: info.ModTime(), size: info.Size()}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,71 @@
+package watch
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// errUnsupported is returned by newNative on platforms without a native backend
+var errUnsupported = errors.New("native file watching is not supported on this platform")
+
+// Root is a directory to watch
+type Root struct {
+	Dir       string
+	Recursive bool // Also watch subdirectories, including ones created later
+}
+
+// Options configures a Watcher
+type Options struct {
+	// SkipDir reports whether a subdirectory is left unwatched; nil watches all
+	SkipDir func(path string) bool
+	// Interval is how often the polling backend scans for changes
+	Interval time.Duration
+}
+
+// Watcher reports files that were created or written below a set of
+// directories. Deleted files are not reported.
+type Watcher interface {
+	// Events delivers the path of each changed file, joined to its root's Dir.
+	// A burst of writes may report the same file several times.
+	Events() <-chan string
+	// Errors delivers problems that do not stop the watcher
+	Errors() <-chan error
+	// Close stops the watcher and closes both channels
+	Close() error
+}
+
+// New watches roots with the platform's native file notifications, falling
+// back to polling when they are unavailable
+func New(roots []Root, opts Options) (Watcher, error) {
+	w, err := newNative(roots, opts)
+	if err == nil {
+		return w, nil
+	}
+	return NewPoller(roots, opts)
+}
+
+// walkDirs calls fn for every directory to watch below root, including root
+func walkDirs(root Root, skipDir func(string) bool, fn func(dir string) error) error {
+	if !root.Recursive {
+		return fn(root.Dir)
+	}
+
+	return filepath.Walk(root.Dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			// Directories removed while walking are simply not watched
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if path != root.Dir && skipDir != nil && skipDir(path) {
+			return filepath.SkipDir
+		}
+		return fn(path)
+	})
+}
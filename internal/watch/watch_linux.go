@@ -0,0 +1,218 @@
+//go:build linux
+
+package watch
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+)
+
+// inotifyMask selects the inotify events the watcher listens to
+const inotifyMask = syscall.IN_CLOSE_WRITE | syscall.IN_MODIFY | syscall.IN_MOVED_TO |
+	syscall.IN_CREATE | syscall.IN_DELETE_SELF
+
+// inotifyWatcher is the native Linux backend
+type inotifyWatcher struct {
+	file   *os.File
+	fd     int
+	opts   Options
+	events chan string
+	errors chan error
+	done   chan struct{}
+	wg     sync.WaitGroup
+	once   sync.Once
+
+	mu        sync.Mutex
+	dirs      map[int32]string // Watched directory per watch descriptor
+	recursive map[string]bool  // Whether new subdirectories of a directory are watched
+}
+
+func newNative(roots []Root, opts Options) (Watcher, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("inotify_init1: %w", err)
+	}
+
+	w := &inotifyWatcher{
+		// A non-blocking descriptor lets reads wait in the runtime poller, so
+		// Close can interrupt them
+		file:      os.NewFile(uintptr(fd), "inotify"),
+		fd:        fd,
+		opts:      opts,
+		events:    make(chan string),
+		errors:    make(chan error),
+		done:      make(chan struct{}),
+		dirs:      make(map[int32]string),
+		recursive: make(map[string]bool),
+	}
+
+	for _, root := range roots {
+		if err := walkDirs(root, opts.SkipDir, func(dir string) error {
+			return w.addDir(dir, root.Recursive)
+		}); err != nil {
+			w.file.Close()
+			return nil, err
+		}
+	}
+
+	w.wg.Add(1)
+	go w.run()
+	return w, nil
+}
+
+func (w *inotifyWatcher) Events() <-chan string { return w.events }
+func (w *inotifyWatcher) Errors() <-chan error  { return w.errors }
+
+func (w *inotifyWatcher) Close() error {
+	var err error
+	w.once.Do(func() {
+		close(w.done)
+		err = w.file.Close()
+		w.wg.Wait()
+		close(w.events)
+		close(w.errors)
+	})
+	return err
+}
+
+// addDir starts watching a single directory
+func (w *inotifyWatcher) addDir(dir string, recursive bool) error {
+	wd, err := syscall.InotifyAddWatch(w.fd, dir, inotifyMask|syscall.IN_ONLYDIR)
+	if err != nil {
+		if errors.Is(err, syscall.ENOENT) {
+			return nil
+		}
+		return fmt.Errorf("watching %s: %w", dir, err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.dirs[int32(wd)] = dir
+	w.recursive[dir] = w.recursive[dir] || recursive
+	return nil
+}
+
+func (w *inotifyWatcher) run() {
+	defer w.wg.Done()
+
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := w.file.Read(buf)
+		if err != nil {
+			select {
+			case <-w.done:
+			default:
+				w.sendError(fmt.Errorf("reading inotify events: %w", err))
+			}
+			return
+		}
+
+		for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+			wd := int32(binary.NativeEndian.Uint32(buf[offset:]))
+			mask := binary.NativeEndian.Uint32(buf[offset+4:])
+			nameLen := int(binary.NativeEndian.Uint32(buf[offset+12:]))
+			nameStart := offset + syscall.SizeofInotifyEvent
+			name := string(trimNUL(buf[nameStart : nameStart+nameLen]))
+			offset = nameStart + nameLen
+
+			if !w.handle(wd, mask, name) {
+				return
+			}
+		}
+	}
+}
+
+// handle processes a single event, reporting false once the watcher is closed
+func (w *inotifyWatcher) handle(wd int32, mask uint32, name string) bool {
+	if mask&syscall.IN_Q_OVERFLOW != 0 {
+		return w.sendError(errors.New("inotify event queue overflowed; some changes were missed"))
+	}
+
+	w.mu.Lock()
+	dir, ok := w.dirs[wd]
+	recursive := w.recursive[dir]
+	if mask&syscall.IN_IGNORED != 0 {
+		delete(w.dirs, wd)
+	}
+	w.mu.Unlock()
+	if !ok || name == "" {
+		return true
+	}
+
+	path := filepath.Join(dir, name)
+	if mask&syscall.IN_ISDIR != 0 {
+		if !recursive || mask&(syscall.IN_CREATE|syscall.IN_MOVED_TO) == 0 {
+			return true
+		}
+		if w.opts.SkipDir != nil && w.opts.SkipDir(path) {
+			return true
+		}
+		return w.addNewDir(path)
+	}
+
+	return w.send(path)
+}
+
+// addNewDir watches a directory created after the watcher started and
+// reports the files that appeared in it before the watch was in place
+func (w *inotifyWatcher) addNewDir(path string) bool {
+	var files []string
+	err := walkDirs(Root{Dir: path, Recursive: true}, w.opts.SkipDir, func(dir string) error {
+		if err := w.addDir(dir, true); err != nil {
+			return err
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				files = append(files, filepath.Join(dir, entry.Name()))
+			}
+		}
+		return nil
+	})
+	if err != nil && !w.sendError(err) {
+		return false
+	}
+
+	for _, file := range files {
+		if !w.send(file) {
+			return false
+		}
+	}
+	return true
+}
+
+func (w *inotifyWatcher) send(path string) bool {
+	select {
+	case w.events <- path:
+		return true
+	case <-w.done:
+		return false
+	}
+}
+
+func (w *inotifyWatcher) sendError(err error) bool {
+	select {
+	case w.errors <- err:
+		return true
+	case <-w.done:
+		return false
+	}
+}
+
+// trimNUL drops the NUL padding after an inotify event name
+func trimNUL(b []byte) []byte {
+	for i, c := range b {
+		if c == 0 {
+			return b[:i]
+		}
+	}
+	return b
+}
@@ -0,0 +1,7 @@
+//go:build !linux
+
+package watch
+
+func newNative(roots []Root, opts Options) (Watcher, error) {
+	return nil, errUnsupported
+}
@@ -0,0 +1,111 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWatchers(t *testing.T) {
+	backends := []struct {
+		name string
+		new  func([]Root, Options) (Watcher, error)
+	}{
+		{name: "native", new: newNative},
+		{name: "poller", new: NewPoller},
+	}
+
+	for _, backend := range backends {
+		t.Run(backend.name, func(t *testing.T) {
+			root := t.TempDir()
+			mustWrite(t, filepath.Join(root, "existing.ts"), "a")
+			if err := os.Mkdir(filepath.Join(root, "skipped"), 0o755); err != nil {
+				t.Fatalf("Failed to create dir: %v", err)
+			}
+
+			w, err := backend.new([]Root{{Dir: root, Recursive: true}}, Options{
+				SkipDir:  func(path string) bool { return filepath.Base(path) == "skipped" },
+				Interval: 20 * time.Millisecond,
+			})
+			if err == errUnsupported {
+				t.Skip(err)
+			}
+			if err != nil {
+				t.Fatalf("Failed to create watcher: %v", err)
+			}
+			defer w.Close()
+
+			// Give the poller a tick so the next write changes the modification time
+			time.Sleep(50 * time.Millisecond)
+
+			mustWrite(t, filepath.Join(root, "skipped", "ignored.ts"), "x")
+			mustWrite(t, filepath.Join(root, "existing.ts"), "changed")
+			expectEvent(t, w, filepath.Join(root, "existing.ts"))
+
+			mustWrite(t, filepath.Join(root, "nested", "deeper", "new.ts"), "new")
+			expectEvent(t, w, filepath.Join(root, "nested", "deeper", "new.ts"))
+
+			// Files in directories created after the watcher started are watched too
+			time.Sleep(50 * time.Millisecond)
+			mustWrite(t, filepath.Join(root, "nested", "deeper", "later.ts"), "later")
+			expectEvent(t, w, filepath.Join(root, "nested", "deeper", "later.ts"))
+
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close failed: %v", err)
+			}
+			for range w.Events() {
+				// Drain events queued before Close
+			}
+		})
+	}
+}
+
+func TestWatcherNonRecursive(t *testing.T) {
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "sub"), 0o755); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+
+	w, err := NewPoller([]Root{{Dir: root}}, Options{Interval: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Failed to create watcher: %v", err)
+	}
+	defer w.Close()
+
+	mustWrite(t, filepath.Join(root, "sub", "nested.ts"), "x")
+	mustWrite(t, filepath.Join(root, "top.ts"), "x")
+	expectEvent(t, w, filepath.Join(root, "top.ts"))
+}
+
+func mustWrite(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+}
+
+// expectEvent waits for an event for want, failing on events for skipped files
+func expectEvent(t *testing.T, w Watcher, want string) {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case path := <-w.Events():
+			if strings.Contains(path, "skipped") || strings.Contains(path, string(filepath.Separator)+"sub"+string(filepath.Separator)) {
+				t.Fatalf("got event for unwatched file %s", path)
+			}
+			if path == want {
+				return
+			}
+		case err := <-w.Errors():
+			t.Fatalf("watcher error: %v", err)
+		case <-timeout:
+			t.Fatalf("timed out waiting for an event for %s", want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+const path = require('path');
+const fs = require('fs');
+
+// Map Node.js platform/arch to npm package names
+const PLATFORM_MAP = {
+  darwin: 'darwin',
+  linux: 'linux',
+  win32: 'windows'  // Node.js uses 'win32' but our package uses 'windows'
+};
+
+const ARCH_MAP = {
+  x64: 'x64',
+  arm64: 'arm64'
+};
+
+// Returns the path of the binary shipped in the platform-specific package,
+// throwing when the platform is unsupported or the package is missing
+function getBinaryPath() {
+  const platform = PLATFORM_MAP[process.platform];
+  const arch = ARCH_MAP[process.arch];
+
+  if (!platform || !arch) {
+    throw new Error(`Unsupported platform: ${process.platform} ${process.arch}`);
+  }
+
+  const packageName = `tree-sorter-ts-${platform}-${arch}`;
+  const binaryName = `tree-sorter-ts${process.platform === 'win32' ? '.exe' : ''}`;
+
+  try {
+    // Try to resolve the platform-specific package
+    const packagePath = require.resolve(`${packageName}/package.json`);
+    const packageDir = path.dirname(packagePath);
+    const binaryPath = path.join(packageDir, binaryName);
+
+    if (fs.existsSync(binaryPath)) {
+      return binaryPath;
+    }
+  } catch (e) {
+    // Package not found
+  }
+
+  throw new Error(`
+tree-sorter-ts binary not found!
+
+It looks like the optional dependency for your platform (${platform}-${arch}) was not installed.
+
+Try running:
+  npm install ${packageName}
+
+Or reinstall tree-sorter-ts:
+  npm install -g tree-sorter-ts
+`);
+}
+
+module.exports = { getBinaryPath };
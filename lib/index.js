@@ -0,0 +1,117 @@
+const { spawn } = require('child_process');
+const readline = require('readline');
+const { getBinaryPath } = require('./binary');
+
+// Starts a long-running `tree-sorter-ts serve` process and returns a client
+// sending it requests. The process is reused for every call, so parsers stay
+// warm; call close() when done.
+//
+// Options:
+//   binaryPath - binary to run (default: the one from the platform package)
+//   config     - project configuration file (default: nearest one per file)
+//   workers    - requests handled at once (default: number of CPUs)
+function createClient(options = {}) {
+  const args = ['serve'];
+  if (options.config) {
+    args.push('--config', options.config);
+  }
+  if (options.workers) {
+    args.push('--workers', String(options.workers));
+  }
+
+  const child = spawn(options.binaryPath || getBinaryPath(), args, {
+    stdio: ['pipe', 'pipe', 'inherit'],
+    shell: false
+  });
+
+  const pending = new Map();
+  let nextId = 1;
+  let exitError = null;
+
+  // The process only keeps Node.js alive while requests are in flight
+  const setActive = (active) => {
+    const method = active ? 'ref' : 'unref';
+    child[method]();
+    child.stdin[method]();
+    child.stdout[method]();
+  };
+  setActive(false);
+
+  const failAll = (err) => {
+    exitError = exitError || err;
+    for (const { reject } of pending.values()) {
+      reject(exitError);
+    }
+    pending.clear();
+  };
+
+  child.on('error', (err) => failAll(new Error(`Failed to execute tree-sorter-ts: ${err.message}`)));
+  child.on('exit', (code, signal) => {
+    failAll(new Error(`tree-sorter-ts serve exited (${signal || `code ${code}`})`));
+  });
+  child.stdin.on('error', () => {
+    // Reported through the exit event
+  });
+
+  readline.createInterface({ input: child.stdout }).on('line', (line) => {
+    let response;
+    try {
+      response = JSON.parse(line);
+    } catch (e) {
+      return;
+    }
+
+    const request = pending.get(response.id);
+    if (!request) {
+      return;
+    }
+    pending.delete(response.id);
+    if (pending.size === 0) {
+      setActive(false);
+    }
+
+    if (response.error) {
+      const err = new Error(response.error.message);
+      err.code = response.error.code;
+      request.reject(err);
+    } else {
+      request.resolve(response.result);
+    }
+  });
+
+  const send = (method, params) => {
+    if (exitError) {
+      return Promise.reject(exitError);
+    }
+
+    const id = nextId++;
+    return new Promise((resolve, reject) => {
+      pending.set(id, { resolve, reject });
+      setActive(true);
+      child.stdin.write(JSON.stringify({ id, method, params }) + '\n');
+    });
+  };
+
+  return {
+    // Resolves to { changed, ignored, content, edits }; rejects when the
+    // content cannot be sorted, e.g. because it does not parse. Edit offsets
+    // index JavaScript strings.
+    sortContent: ({ filePath, content, allowParseErrors }) =>
+      send('sortContent', { filePath, content, allowParseErrors }),
+
+    // Resolves to { sorted, ignored, structuresNeedSort, structures, error }
+    checkContent: ({ filePath, content, allowParseErrors }) =>
+      send('checkContent', { filePath, content, allowParseErrors }),
+
+    // Resolves to { ignored, structures, error }
+    listStructures: ({ filePath, content, allowParseErrors }) =>
+      send('listStructures', { filePath, content, allowParseErrors }),
+
+    // Stops the process once the requests in flight are answered
+    close: () => {
+      child.stdin.end();
+    }
+  };
+}
+
+module.exports = { createClient, getBinaryPath };
@@ -0,0 +1,310 @@
+// Package treesorter sorts the keep-sorted structures of TypeScript and TSX
+// source, the same way the tree-sorter-ts command does, for Go programs that
+// generate or rewrite TypeScript.
+//
+//	result, err := treesorter.SortSource("api/client.ts", src, treesorter.Options{})
+//	if err != nil {
+//		return err
+//	}
+//	if result.Changed {
+//		src = result.Source
+//	}
+//
+// The API is stable: fields and functions are only ever added.
+package treesorter
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/evanrichards/tree-sorter-ts/internal/config"
+	"github.com/evanrichards/tree-sorter-ts/internal/processor"
+)
+
+// Kinds of keep-sorted structures reported in Structure.Kind
+const (
+	KindObject      = processor.KindObject
+	KindArray       = processor.KindArray
+	KindParameters  = processor.KindParameters
+	KindStatements  = processor.KindStatements
+	KindMembers     = processor.KindMembers
+	KindTypeMembers = processor.KindTypeMembers
+	KindEnumMembers = processor.KindEnumMembers
+)
+
+// ErrInvalidMagicComment is wrapped by the error returned for source with a
+// magic comment whose options cannot be combined, or with start-sort and
+// end-sort markers that do not pair up
+var ErrInvalidMagicComment = errors.New("invalid magic comment")
+
+// ErrUnsafeEnum is wrapped by the error returned for source with a keep-sorted
+// enum whose member values depend on the order of the members
+var ErrUnsafeEnum = errors.New("enum members cannot be sorted safely")
+
+// ErrUnsafeStatements is wrapped by the error returned for source with a
+// keep-sorted statement list whose statements run code that depends on their
+// order
+var ErrUnsafeStatements = errors.New("statements cannot be sorted safely")
+
+// ErrUnsafeClassMembers is wrapped by the error returned for source with a
+// keep-sorted class body whose field initializers or static blocks depend on
+// the order of the members
+var ErrUnsafeClassMembers = errors.New("class members cannot be sorted safely")
+
+// Options configures sorting
+type Options struct {
+	// Defaults are magic comment options applied to every structure unless
+	// its magic comment sets them, written as in a magic comment, e.g.
+	// "with-new-line deprecated-at-end"
+	Defaults string
+
+	// AllowParseErrors sorts structures in source with syntax errors as long
+	// as the structure itself parsed cleanly. Without it such source returns
+	// a *SyntaxError.
+	AllowParseErrors bool
+
+	// Lines limits sorting to structures overlapping these line ranges; empty
+	// sorts every structure
+	Lines []LineRange
+}
+
+// LineRange is an inclusive range of 1-based line numbers
+type LineRange struct {
+	Start int
+	End   int
+}
+
+// Position is a 1-based line and column; columns count characters, not bytes
+type Position struct {
+	Line   int
+	Column int
+}
+
+// TextEdit replaces the bytes StartByte to EndByte of the source with NewText
+type TextEdit struct {
+	StartByte int
+	EndByte   int
+	Start     Position
+	End       Position
+	NewText   string
+}
+
+// StructureOptions are the effective options of a keep-sorted structure
+type StructureOptions struct {
+	WithNewLine     bool
+	DeprecatedAtEnd bool
+	Key             string // Property the elements of an array of objects are sorted by
+	SortByComment   bool
+	GroupByKind     bool // Class members are grouped by kind before sorting
+	SortByValue     bool // Enum members are sorted by value
+	// MaterializeValues writes out enum member values that depend on the
+	// order of the members
+	MaterializeValues bool
+}
+
+// Structure describes one keep-sorted structure of the source
+type Structure struct {
+	Kind      string   // KindObject, KindArray, KindParameters, KindStatements, KindMembers, KindTypeMembers or KindEnumMembers
+	Start     Position // Start of the object, array or parameter list
+	End       Position
+	Comment   Position // Start of the magic comment
+	StartByte int
+	EndByte   int
+	Options   StructureOptions
+	Strategy  string // Name of the sorting strategy selected by the options
+	Sorted    bool   // Whether the structure already was in sorted order
+	Error     string // Why the structure could not be sorted, if it could not
+
+	// Edits sort the structure along with the structures nested in it,
+	// touching only the bytes between StartByte and EndByte that change;
+	// empty when the structure is sorted
+	Edits []TextEdit
+}
+
+// Result is the outcome of sorting source
+type Result struct {
+	Changed    bool
+	Source     []byte // The sorted source; the input itself when unchanged
+	Structures []Structure
+
+	// Edits turn the input into Source; they do not overlap and are ordered
+	// by position
+	Edits []TextEdit
+}
+
+// CheckResult is the outcome of checking source
+type CheckResult struct {
+	Sorted             bool
+	StructuresNeedSort int
+	Structures         []Structure
+}
+
+// SyntaxError reports source that does not parse. Such source is never
+// sorted unless Options.AllowParseErrors is set.
+type SyntaxError struct {
+	Line    int    // 1-based line of the first syntax error
+	Column  int    // 1-based byte column of the first syntax error
+	Missing string // Node type the parser expected but did not find, if any
+	Snippet string // Unexpected source text, if any
+	err     error
+}
+
+func (e *SyntaxError) Error() string {
+	return e.err.Error()
+}
+
+// VerificationError reports that the sorted source failed the check that
+// sorting only moved items around. It always indicates a bug in
+// tree-sorter-ts.
+type VerificationError struct {
+	Reason  string
+	Details []string
+	err     error
+}
+
+func (e *VerificationError) Error() string {
+	return e.err.Error()
+}
+
+// SortSource sorts the keep-sorted structures of src. The path selects the
+// grammar (.tsx files are parsed as TSX, anything else as TypeScript) and is
+// used in error messages; the file is never read or written. src is not
+// modified.
+//
+// When an error is returned the Result still describes the structures that
+// were found, but Source is the unchanged input.
+func SortSource(path string, src []byte, opts Options) (Result, error) {
+	processed, err := process(path, src, opts)
+
+	result := Result{
+		Source:     src,
+		Structures: structures(processed),
+		Edits:      []TextEdit{},
+	}
+	if err != nil {
+		return result, err
+	}
+
+	if processed.Changed {
+		result.Changed = true
+		result.Source = processed.Sorted
+		result.Edits = textEdits(processed.Edits)
+	}
+	return result, nil
+}
+
+// Check reports whether every keep-sorted structure of src is sorted,
+// without producing the sorted source. Errors are those of SortSource.
+func Check(path string, src []byte, opts Options) (CheckResult, error) {
+	processed, err := process(path, src, opts)
+
+	result := CheckResult{
+		Sorted:     err == nil && !processed.Changed,
+		Structures: structures(processed),
+	}
+	for _, structure := range result.Structures {
+		if !structure.Sorted {
+			result.StructuresNeedSort++
+		}
+	}
+	return result, err
+}
+
+func process(path string, src []byte, opts Options) (processor.ProcessResult, error) {
+	defaults, err := config.ParseOptions(opts.Defaults)
+	if err != nil {
+		return processor.ProcessResult{}, fmt.Errorf("invalid default options: %w", err)
+	}
+
+	cfg := processor.Config{
+		AllowParseErrors: opts.AllowParseErrors,
+		SortDefaults:     defaults,
+	}
+	if len(opts.Lines) > 0 {
+		cfg.Lines = make([]processor.LineRange, 0, len(opts.Lines))
+		for _, r := range opts.Lines {
+			cfg.Lines = append(cfg.Lines, processor.LineRange{Start: r.Start, End: r.End})
+		}
+	}
+
+	result, err := processor.ProcessContentAST(path, src, cfg)
+	return result, publicError(path, err)
+}
+
+// publicError converts the processor's errors into the package's own types
+func publicError(path string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var parseErr *processor.ParseError
+	var verifyErr *processor.VerificationError
+	switch {
+	case errors.As(err, &parseErr):
+		return &SyntaxError{
+			Line:    parseErr.Line,
+			Column:  parseErr.Column,
+			Missing: parseErr.Missing,
+			Snippet: parseErr.Snippet,
+			err:     fmt.Errorf("%s: %w", path, err),
+		}
+	case errors.As(err, &verifyErr):
+		return &VerificationError{
+			Reason:  verifyErr.Reason,
+			Details: verifyErr.Details,
+			err:     fmt.Errorf("%s: %w", path, err),
+		}
+	case errors.Is(err, processor.ErrInvalidMagicComment), errors.Is(err, processor.ErrUnbalancedSection):
+		return fmt.Errorf("%s: %w: %v", path, ErrInvalidMagicComment, err)
+	case errors.Is(err, processor.ErrUnsafeEnum):
+		return fmt.Errorf("%s: %w: %v", path, ErrUnsafeEnum, err)
+	case errors.Is(err, processor.ErrUnsafeStatements):
+		return fmt.Errorf("%s: %w: %v", path, ErrUnsafeStatements, err)
+	case errors.Is(err, processor.ErrUnsafeClassMembers):
+		return fmt.Errorf("%s: %w: %v", path, ErrUnsafeClassMembers, err)
+	}
+	return fmt.Errorf("%s: %w", path, err)
+}
+
+func structures(result processor.ProcessResult) []Structure {
+	converted := make([]Structure, 0, len(result.Structures))
+	for _, s := range result.Structures {
+		structure := Structure{
+			Kind:      s.Kind,
+			Start:     Position(s.Start),
+			End:       Position(s.End),
+			Comment:   Position(s.Comment),
+			StartByte: s.StartByte,
+			EndByte:   s.EndByte,
+			Options: StructureOptions{
+				WithNewLine:       s.Options.WithNewLine,
+				DeprecatedAtEnd:   s.Options.DeprecatedAtEnd,
+				Key:               s.Options.Key,
+				SortByComment:     s.Options.SortByComment,
+				GroupByKind:       s.Options.GroupByKind,
+				SortByValue:       s.Options.SortByValue,
+				MaterializeValues: s.Options.MaterializeValues,
+			},
+			Strategy: s.Strategy,
+			Sorted:   s.Sorted,
+			Error:    s.Error,
+			Edits:    textEdits(s.Edits),
+		}
+		converted = append(converted, structure)
+	}
+	return converted
+}
+
+func textEdits(edits []processor.TextEdit) []TextEdit {
+	converted := make([]TextEdit, 0, len(edits))
+	for _, edit := range edits {
+		converted = append(converted, TextEdit{
+			StartByte: edit.StartByte,
+			EndByte:   edit.EndByte,
+			Start:     Position(edit.StartPoint),
+			End:       Position(edit.EndPoint),
+			NewText:   edit.NewText,
+		})
+	}
+	return converted
+}
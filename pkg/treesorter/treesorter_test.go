@@ -0,0 +1,207 @@
+package treesorter
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const unsortedSource = `const label = "é";
+
+export const config = {
+  /** tree-sorter-ts: keep-sorted **/
+  zebra: 1,
+  apple: 2,
+};
+
+const list = [
+  /** tree-sorter-ts: keep-sorted **/
+  "b",
+  "a",
+];
+`
+
+func TestSortSource(t *testing.T) {
+	src := []byte(unsortedSource)
+	original := append([]byte(nil), src...)
+
+	result, err := SortSource("config.ts", src, Options{})
+	if err != nil {
+		t.Fatalf("Failed to sort: %v", err)
+	}
+	if !bytes.Equal(src, original) {
+		t.Errorf("SortSource modified its input")
+	}
+
+	expected := strings.NewReplacer(
+		"  zebra: 1,\n  apple: 2,\n", "  apple: 2,\n  zebra: 1,\n",
+		"  \"b\",\n  \"a\",\n", "  \"a\",\n  \"b\",\n",
+	).Replace(unsortedSource)
+	if !result.Changed || string(result.Source) != expected {
+		t.Errorf("unexpected sorted source:\n%s", result.Source)
+	}
+
+	if got := string(applyEdits(src, result.Edits)); got != expected {
+		t.Errorf("applying the edits gave:\n%s", got)
+	}
+
+	if len(result.Structures) != 2 {
+		t.Fatalf("got %d structures, want 2", len(result.Structures))
+	}
+	object := result.Structures[0]
+	if object.Kind != KindObject || object.Sorted || object.Start != (Position{Line: 3, Column: 23}) || object.Comment != (Position{Line: 4, Column: 3}) {
+		t.Errorf("unexpected object structure: %+v", object)
+	}
+	if len(object.Edits) == 0 {
+		t.Fatalf("expected edits for the unsorted object")
+	}
+	sortedObject := string(applyEdits(src, object.Edits))
+	if !strings.Contains(sortedObject, "  apple: 2,\n  zebra: 1,\n") || !strings.Contains(sortedObject, "  \"b\",\n  \"a\",\n") {
+		t.Errorf("the object's edits should sort only the object, got:\n%s", sortedObject)
+	}
+	for _, edit := range object.Edits {
+		if edit.StartByte < object.StartByte || edit.EndByte > object.EndByte {
+			t.Errorf("edit %+v is outside the object", edit)
+		}
+	}
+	if edit := object.Edits[0]; edit.Start != (Position{Line: 5, Column: 3}) {
+		t.Errorf("edit starts at %+v, want line 5, column 3", edit.Start)
+	}
+}
+
+func TestSortSourceOptions(t *testing.T) {
+	src := []byte(unsortedSource)
+
+	// Only the array is on the requested lines
+	result, err := SortSource("config.ts", src, Options{Lines: []LineRange{{Start: 11, End: 11}}})
+	if err != nil {
+		t.Fatalf("Failed to sort: %v", err)
+	}
+	if len(result.Structures) != 1 || result.Structures[0].Kind != KindArray {
+		t.Errorf("expected only the array, got %+v", result.Structures)
+	}
+
+	result, err = SortSource("config.ts", src, Options{Defaults: "with-new-line"})
+	if err != nil {
+		t.Fatalf("Failed to sort: %v", err)
+	}
+	if !result.Structures[0].Options.WithNewLine || !strings.Contains(string(result.Source), "  apple: 2,\n\n  zebra: 1,\n") {
+		t.Errorf("default options were not applied:\n%s", result.Source)
+	}
+
+	if _, err := SortSource("config.ts", src, Options{Defaults: "sideways"}); err == nil {
+		t.Errorf("expected an error for unknown default options")
+	}
+}
+
+func TestSortSourceErrors(t *testing.T) {
+	broken := []byte(strings.Replace(unsortedSource, "const list = [", "const list = [[", 1))
+	result, err := SortSource("broken.ts", broken, Options{})
+	var syntaxErr *SyntaxError
+	if !errors.As(err, &syntaxErr) || syntaxErr.Line < 9 {
+		t.Fatalf("expected a syntax error in the array, got %v", err)
+	}
+	if result.Changed || !bytes.Equal(result.Source, broken) {
+		t.Errorf("source with syntax errors must be left unchanged")
+	}
+
+	// The object still sorts when parse errors are allowed
+	result, err = SortSource("broken.ts", broken, Options{AllowParseErrors: true})
+	if err != nil {
+		t.Fatalf("Failed to sort with parse errors allowed: %v", err)
+	}
+	if !result.Changed || !strings.Contains(string(result.Source), "  apple: 2,\n  zebra: 1,\n") {
+		t.Errorf("expected the object to be sorted:\n%s", result.Source)
+	}
+
+	invalid := []byte(`const ids = [
+  /** tree-sorter-ts: keep-sorted key="id" sort-by-comment **/
+  { id: 2 },
+  { id: 1 },
+];
+`)
+	result, err = SortSource("ids.ts", invalid, Options{})
+	if !errors.Is(err, ErrInvalidMagicComment) || !strings.HasPrefix(err.Error(), "ids.ts: ") {
+		t.Errorf("expected ErrInvalidMagicComment, got %v", err)
+	}
+	if len(result.Structures) != 1 || result.Structures[0].Error == "" {
+		t.Errorf("expected the invalid structure to be described, got %+v", result.Structures)
+	}
+
+	implicit := []byte(`enum Direction {
+  /** tree-sorter-ts: keep-sorted **/
+  Up,
+  Down,
+}
+`)
+	result, err = SortSource("direction.ts", implicit, Options{})
+	if !errors.Is(err, ErrUnsafeEnum) || !strings.HasPrefix(err.Error(), "direction.ts: ") {
+		t.Errorf("expected ErrUnsafeEnum, got %v", err)
+	}
+	if len(result.Structures) != 1 || result.Structures[0].Kind != KindEnumMembers || result.Structures[0].Error == "" {
+		t.Errorf("expected the enum to be described, got %+v", result.Structures)
+	}
+
+	calls := []byte(`/** tree-sorter-ts: keep-sorted **/
+registerPlugin(zed);
+registerPlugin(alpha);
+`)
+	result, err = SortSource("plugins.ts", calls, Options{})
+	if !errors.Is(err, ErrUnsafeStatements) || !strings.HasPrefix(err.Error(), "plugins.ts: ") {
+		t.Errorf("expected ErrUnsafeStatements, got %v", err)
+	}
+	if len(result.Structures) != 1 || result.Structures[0].Kind != KindStatements || result.Structures[0].Error == "" {
+		t.Errorf("expected the statements to be described, got %+v", result.Structures)
+	}
+
+	fields := []byte(`class Counter {
+  /** tree-sorter-ts: keep-sorted **/
+  b = this.a + 1;
+  a = 1;
+}
+`)
+	result, err = SortSource("counter.ts", fields, Options{})
+	if !errors.Is(err, ErrUnsafeClassMembers) || !strings.HasPrefix(err.Error(), "counter.ts: ") {
+		t.Errorf("expected ErrUnsafeClassMembers, got %v", err)
+	}
+	if len(result.Structures) != 1 || result.Structures[0].Kind != KindMembers || result.Structures[0].Error == "" {
+		t.Errorf("expected the class body to be described, got %+v", result.Structures)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name      string
+		src       string
+		sorted    bool
+		needsSort int
+	}{
+		{name: "unsorted", src: unsortedSource, sorted: false, needsSort: 2},
+		{name: "no structures", src: "const a = { b: 1, a: 2 };\n", sorted: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Check("check.tsx", []byte(tt.src), Options{})
+			if err != nil {
+				t.Fatalf("Failed to check: %v", err)
+			}
+			if result.Sorted != tt.sorted || result.StructuresNeedSort != tt.needsSort {
+				t.Errorf("Check() = %+v, want sorted=%v needsSort=%d", result, tt.sorted, tt.needsSort)
+			}
+		})
+	}
+}
+
+// applyEdits applies non-overlapping edits ordered by position
+func applyEdits(src []byte, edits []TextEdit) []byte {
+	var out []byte
+	last := 0
+	for _, edit := range edits {
+		out = append(out, src[last:edit.StartByte]...)
+		out = append(out, edit.NewText...)
+		last = edit.EndByte
+	}
+	return append(out, src[last:]...)
+}
@@ -0,0 +1,44 @@
+import React from "react";
+
+type Props = {
+  items: Array<{ id: string; label: string }>;
+  title: string;
+};
+
+export function Dashboard({ items, title }: Props) {
+  const theme = {
+    /** tree-sorter-ts: keep-sorted **/
+    background: "white",
+    color: "black",
+    padding: 16,
+  };
+
+  const columns = [
+    /** tree-sorter-ts: keep-sorted **/
+    "createdAt",
+    "name",
+    "status",
+  ];
+
+  return (
+    <section style={theme} className="dashboard">
+      <h1>{title}</h1>
+      <Table columns={columns} rows={items} />
+      {items.map((item) => (
+        <Row key={item.id} {...item}>
+          <span>{item.label}</span>
+        </Row>
+      ))}
+      <Chart
+        options={{
+          /** tree-sorter-ts: keep-sorted **/
+          axis: "x",
+          legend: false,
+          zoom: true,
+        }}
+      />
+    </section>
+  );
+}
+
+export const Empty = () => <div className="empty">Nothing here</div>;
@@ -0,0 +1,22 @@
+export const List = <T,>({ rows }: { rows: T[] }) => {
+  const labels: Record<string, string> = {
+    /** tree-sorter-ts: keep-sorted **/
+    alpha: "A",
+    zeta: "Z",
+  };
+  return (
+    <ul>
+      {rows.map((row, i) => (
+        <li key={i} title={labels.alpha}>
+          {String(row)}
+        </li>
+      ))}
+    </ul>
+  );
+};
+
+const sizes = [
+  /** tree-sorter-ts: keep-sorted **/
+  <Icon size="lg" />,
+  <Icon size="md" />,
+];